@@ -0,0 +1,73 @@
+package collections
+
+import (
+	"slices"
+
+	"github.org/jccarlson/collections/compare"
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// BoundedPriorityQueue is a priority queue holding at most a fixed number of
+// elements, which keeps the highest-priority elements offered to it. An
+// element's priority is its position in the queue's compare.Ordering: later
+// elements have higher priority, so with compare.Less the queue keeps the
+// largest elements.
+type BoundedPriorityQueue[E any] struct {
+	// heap's top is the lowest-priority element retained.
+	heap     ds.BinaryHeap[E]
+	capacity int
+}
+
+// NewBoundedPriorityQueue returns a pointer to a new, empty
+// BoundedPriorityQueue holding at most capacity elements ordered by ord.
+func NewBoundedPriorityQueue[E any](capacity int, ord compare.Ordering[E]) *BoundedPriorityQueue[E] {
+	if capacity <= 0 {
+		panic("BoundedPriorityQueue capacity must be > 0")
+	}
+	return &BoundedPriorityQueue[E]{heap: ds.BinaryHeap[E]{Ordering: ord}, capacity: capacity}
+}
+
+// Offer adds e to q if q is not full, or if e has higher priority than the
+// lowest-priority element in q, which is then discarded. It returns true if
+// e was admitted.
+func (q *BoundedPriorityQueue[E]) Offer(e E) bool {
+	if q.heap.Len() < q.capacity {
+		q.heap.Push(e)
+		return true
+	}
+	if lowest, _ := q.heap.Peek(); !q.heap.Ordering(lowest, e) {
+		return false
+	}
+	q.heap.Replace(e)
+	return true
+}
+
+// Peek returns the lowest-priority element in q, which is the next to be
+// discarded, without removing it. ok is false if q is empty.
+func (q *BoundedPriorityQueue[E]) Peek() (e E, ok bool) {
+	return q.heap.Peek()
+}
+
+// Poll removes and returns the lowest-priority element in q. ok is false if q
+// is empty.
+func (q *BoundedPriorityQueue[E]) Poll() (e E, ok bool) {
+	return q.heap.Pop()
+}
+
+// Len returns the number of elements in q.
+func (q *BoundedPriorityQueue[E]) Len() int {
+	return q.heap.Len()
+}
+
+// Cap returns the maximum number of elements q holds.
+func (q *BoundedPriorityQueue[E]) Cap() int {
+	return q.capacity
+}
+
+// Sorted returns a new slice holding the elements of q from highest to lowest
+// priority. q is not modified.
+func (q *BoundedPriorityQueue[E]) Sorted() []E {
+	es := slices.Clone(q.heap.Elems())
+	slices.SortFunc(es, compare.Reverse(q.heap.Ordering).Compare)
+	return es
+}
@@ -0,0 +1,138 @@
+package collections
+
+import "io"
+
+// minReadFrom is the least free space ByteDeque.ReadFrom offers to each Read.
+const minReadFrom = 512
+
+// ByteDeque is a Deque of bytes which is also a byte queue implementing
+// io.Reader, io.Writer, io.ReaderFrom, io.WriterTo, io.ByteReader and
+// io.ByteWriter. Write appends at the back and Read consumes from the front,
+// copying whole runs of the ring buffer at once, so it can buffer a stream
+// for protocol framing without reallocating as bytes pass through it. The
+// zero value is an empty ByteDeque ready to use.
+type ByteDeque struct {
+	Deque[byte]
+}
+
+// NewByteDeque returns a pointer to a new, empty ByteDeque with room for at
+// least capacity bytes before it must grow.
+func NewByteDeque(capacity int) *ByteDeque {
+	return &ByteDeque{*NewDeque[byte](capacity)}
+}
+
+// used returns the first contiguous run of bytes in d's buffer, starting at
+// the front.
+func (d *ByteDeque) used() []byte {
+	if d.size == 0 {
+		return nil
+	}
+	return d.buf[d.head:min(d.head+d.size, len(d.buf))]
+}
+
+// free returns the first contiguous run of unused space in d's buffer,
+// starting after the back.
+func (d *ByteDeque) free() []byte {
+	if d.size == len(d.buf) {
+		return nil
+	}
+	tail := d.index(d.size)
+	if tail < d.head {
+		return d.buf[tail:d.head]
+	}
+	return d.buf[tail:]
+}
+
+// discard removes the first n bytes of d, which must hold at least n.
+func (d *ByteDeque) discard(n int) {
+	d.size -= n
+	if d.size == 0 {
+		d.head = 0
+	} else {
+		d.head = d.index(n)
+	}
+}
+
+// Write appends p to the back of d. It always returns len(p), nil.
+func (d *ByteDeque) Write(p []byte) (int, error) {
+	d.grow(len(p))
+	for n := 0; n < len(p); {
+		c := copy(d.free(), p[n:])
+		d.size += c
+		n += c
+	}
+	return len(p), nil
+}
+
+// WriteByte appends c to the back of d. It always returns nil.
+func (d *ByteDeque) WriteByte(c byte) error {
+	d.AddLast(c)
+	return nil
+}
+
+// Read removes up to len(p) bytes from the front of d into p. It returns
+// io.EOF if d is empty and p is not.
+func (d *ByteDeque) Read(p []byte) (int, error) {
+	if d.size == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n := min(len(p), d.size)
+	d.copyTo(p, n)
+	d.discard(n)
+	return n, nil
+}
+
+// ReadByte removes and returns the byte at the front of d, or io.EOF if d is
+// empty.
+func (d *ByteDeque) ReadByte() (byte, error) {
+	c, ok := d.RemoveFirst()
+	if !ok {
+		return 0, io.EOF
+	}
+	return c, nil
+}
+
+// ReadFrom appends the bytes read from r to the back of d until r returns
+// io.EOF, reading directly into d's buffer, which it grows whenever less than
+// minReadFrom bytes are free. It returns the number of bytes
+// read, and any error from r other than io.EOF.
+func (d *ByteDeque) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for {
+		d.grow(minReadFrom)
+		n, err := r.Read(d.free())
+		if n < 0 {
+			panic("ByteDeque.ReadFrom: Reader returned a negative count")
+		}
+		d.size += n
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// WriteTo writes the bytes of d to w, removing them from d as they are
+// written, until d is empty or w returns an error.
+func (d *ByteDeque) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for d.size > 0 {
+		run := d.used()
+		n, err := w.Write(run)
+		d.discard(n)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+		if n < len(run) {
+			return total, io.ErrShortWrite
+		}
+	}
+	return total, nil
+}
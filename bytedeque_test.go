@@ -0,0 +1,75 @@
+package collections
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestByteDequeReadWrite(t *testing.T) {
+	var d ByteDeque
+	var out []byte
+	p := make([]byte, 5)
+	// Interleave writes and reads so the contents wrap around the buffer.
+	for i := 0; i < 20; i++ {
+		d.Write([]byte("abcdefg"))
+		n, err := d.Read(p)
+		if err != nil || n != 5 {
+			t.Fatalf("Want Read() == (5, nil), Got (%d, %v)", n, err)
+		}
+		out = append(out, p[:n]...)
+	}
+	rest, err := io.ReadAll(&d)
+	if err != nil {
+		t.Errorf("Want ReadAll() error == nil, Got %v", err)
+	}
+	out = append(out, rest...)
+	if want := strings.Repeat("abcdefg", 20); string(out) != want {
+		t.Errorf("Want %q, Got %q", want, out)
+	}
+	if n, err := d.Read(p); n != 0 || err != io.EOF {
+		t.Errorf("Want Read() on empty ByteDeque == (0, EOF), Got (%d, %v)", n, err)
+	}
+}
+
+func TestByteDequeBytes(t *testing.T) {
+	d := NewByteDeque(0)
+	d.WriteByte('x')
+	d.Write([]byte("yz"))
+	for _, want := range []byte("xyz") {
+		if c, err := d.ReadByte(); c != want || err != nil {
+			t.Errorf("Want ReadByte() == (%q, nil), Got (%q, %v)", want, c, err)
+		}
+	}
+	if _, err := d.ReadByte(); err != io.EOF {
+		t.Errorf("Want ReadByte() on empty ByteDeque to return EOF, Got %v", err)
+	}
+}
+
+func TestByteDequeReadFromWriteTo(t *testing.T) {
+	src := bytes.Repeat([]byte("0123456789"), 1000)
+	d := NewByteDeque(8)
+	d.Write([]byte("head:"))
+	d.Read(make([]byte, 3))
+	n, err := d.ReadFrom(iotest.OneByteReader(bytes.NewReader(src)))
+	if n != int64(len(src)) || err != nil {
+		t.Errorf("Want ReadFrom() == (%d, nil), Got (%d, %v)", len(src), n, err)
+	}
+
+	var buf bytes.Buffer
+	n, err = d.WriteTo(&buf)
+	if n != int64(len(src)+2) || err != nil {
+		t.Errorf("Want WriteTo() == (%d, nil), Got (%d, %v)", len(src)+2, n, err)
+	}
+	if want := "d:" + string(src); buf.String() != want || d.Len() != 0 {
+		t.Errorf("Want WriteTo to drain the ByteDeque, Got %d bytes written, Len() == %d", buf.Len(), d.Len())
+	}
+
+	errRead := errors.New("read failed")
+	if _, err := d.ReadFrom(iotest.ErrReader(errRead)); err != errRead {
+		t.Errorf("Want ReadFrom() to return the Reader's error, Got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package cache
+
+// arcList identifies which of the four ARC lists a key is on.
+type arcList int
+
+const (
+	// t1 holds resident keys which have been seen once recently.
+	t1 arcList = iota
+	// t2 holds resident keys which have been seen at least twice recently.
+	t2
+	// b1 holds ghost keys recently evicted from t1.
+	b1
+	// b2 holds ghost keys recently evicted from t2.
+	b2
+)
+
+type arcNode[K any] struct {
+	node[K]
+	list arcList
+}
+
+// arcPolicy implements the Adaptive Replacement Cache algorithm described by
+// Megiddo and Modha. Each list is ordered from least- to most-recently used.
+type arcPolicy[K comparable] struct {
+	// c is the number of entries in the cache.
+	c int
+	// p is the adaptive target size of t1.
+	p int
+
+	nodes map[K]*arcNode[K]
+	lists [4]list[K]
+
+	// pendingB2 is true if the key passed to PrepareAdmit is a ghost in b2.
+	pendingB2 bool
+}
+
+// ARC returns a Policy which balances between evicting the least-recently
+// used and least-frequently used keys, adapting to the access pattern by
+// remembering the keys it recently evicted. capacity must equal the
+// MaxEntries of the Cache the policy is used with, as ARC remembers up to
+// capacity evicted keys.
+func ARC[K comparable](capacity int) Policy[K] {
+	if capacity <= 0 {
+		panic("ARC capacity must be > 0")
+	}
+	return &arcPolicy[K]{c: capacity, nodes: make(map[K]*arcNode[K])}
+}
+
+func (p *arcPolicy[K]) len(l arcList) int {
+	return p.lists[l].len
+}
+
+// move moves n to the most-recently used end of list l.
+func (p *arcPolicy[K]) move(n *arcNode[K], l arcList) {
+	p.lists[n.list].remove(&n.node)
+	n.list = l
+	p.lists[l].pushBack(&n.node)
+}
+
+// dropLRU forgets the least-recently used key in list l.
+func (p *arcPolicy[K]) dropLRU(l arcList) {
+	n := p.lists[l].head
+	p.lists[l].remove(n)
+	delete(p.nodes, n.key)
+}
+
+func (p *arcPolicy[K]) PrepareAdmit(key K) {
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	// A hit on a ghost list means the cache would have hit if the
+	// corresponding resident list were larger, so adapt the target size of
+	// t1 towards it.
+	switch n.list {
+	case b1:
+		delta := 1
+		if p.len(b2) > p.len(b1) {
+			delta = p.len(b2) / p.len(b1)
+		}
+		if p.p += delta; p.p > p.c {
+			p.p = p.c
+		}
+	case b2:
+		delta := 1
+		if p.len(b1) > p.len(b2) {
+			delta = p.len(b1) / p.len(b2)
+		}
+		if p.p -= delta; p.p < 0 {
+			p.p = 0
+		}
+		p.pendingB2 = true
+	}
+}
+
+func (p *arcPolicy[K]) Admit(key K) {
+	p.pendingB2 = false
+	if n, ok := p.nodes[key]; ok {
+		// key was a ghost, so it has now been seen twice.
+		p.move(n, t2)
+	} else {
+		n = &arcNode[K]{node: node[K]{key: key}, list: t1}
+		p.nodes[key] = n
+		p.lists[t1].pushBack(&n.node)
+	}
+
+	// Bound the ghost lists so that at most c keys are remembered on
+	// each side, and at most 2c keys in total.
+	for p.len(t1)+p.len(b1) > p.c && p.len(b1) > 0 {
+		p.dropLRU(b1)
+	}
+	for p.len(t1)+p.len(t2)+p.len(b1)+p.len(b2) > 2*p.c && p.len(b2) > 0 {
+		p.dropLRU(b2)
+	}
+}
+
+func (p *arcPolicy[K]) Touch(key K) {
+	if n, ok := p.nodes[key]; ok && (n.list == t1 || n.list == t2) {
+		p.move(n, t2)
+	}
+}
+
+func (p *arcPolicy[K]) Remove(key K) {
+	if n, ok := p.nodes[key]; ok {
+		p.lists[n.list].remove(&n.node)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *arcPolicy[K]) Evict() (key K, ok bool) {
+	from, to := t2, b2
+	if l1 := p.len(t1); l1 > 0 && (l1 > p.p || (p.pendingB2 && l1 == p.p) || p.len(t2) == 0) {
+		from, to = t1, b1
+	}
+	n := p.lists[from].head
+	if n == nil {
+		return
+	}
+	p.move(p.nodes[n.key], to)
+	return n.key, true
+}
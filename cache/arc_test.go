@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// skewedTrace returns a trace of n accesses, mostly drawn from a Zipf
+// distribution over a small key space, interleaved with long scans over keys
+// which are never accessed again.
+func skewedTrace(n int) []int {
+	rng := rand.New(rand.NewSource(0xC0FFEE))
+	zipf := rand.NewZipf(rng, 1.1, 1, 1000)
+	trace := make([]int, 0, n)
+	scanKey := 1 << 20
+	for len(trace) < n {
+		for i := 0; i < 500; i++ {
+			trace = append(trace, int(zipf.Uint64()))
+		}
+		for i := 0; i < 200; i++ {
+			trace = append(trace, scanKey)
+			scanKey++
+		}
+	}
+	return trace
+}
+
+func hitRate(c *Cache[int, int], trace []int) float64 {
+	hits := 0
+	for _, k := range trace {
+		if _, ok := c.Get(k); ok {
+			hits++
+			continue
+		}
+		c.Put(k, k)
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+func TestARCHitRateBeatsLRUOnSkewedTrace(t *testing.T) {
+	const size = 100
+	trace := skewedTrace(100000)
+
+	lru := hitRate(New[int, int](LRU[int](), MaxEntries(size)), trace)
+	arc := hitRate(New[int, int](ARC[int](size), MaxEntries(size)), trace)
+	t.Logf("LRU hit rate: %.3f, ARC hit rate: %.3f", lru, arc)
+	if arc <= lru {
+		t.Errorf("Want ARC hit rate > LRU hit rate, Got ARC: %.3f, LRU: %.3f", arc, lru)
+	}
+}
+
+func TestARCBookkeeping(t *testing.T) {
+	const size = 4
+	p := ARC[int](size).(*arcPolicy[int])
+	c := New[int, int](p, MaxEntries(size))
+
+	for i := 0; i < 1000; i++ {
+		k := (i * 7) % 13
+		if _, ok := c.Get(k); !ok {
+			c.Put(k, k)
+		}
+		if i%5 == 0 {
+			c.Delete((i * 3) % 13)
+		}
+
+		resident := p.len(t1) + p.len(t2)
+		if resident != c.Len() {
+			t.Fatalf("Want len(t1) + len(t2) == Len() == %d, Got %d", c.Len(), resident)
+		}
+		if p.len(t1)+p.len(b1) > size {
+			t.Fatalf("Want len(t1) + len(b1) <= %d, Got %d", size, p.len(t1)+p.len(b1))
+		}
+		if total := resident + p.len(b1) + p.len(b2); total > 2*size || total != len(p.nodes) {
+			t.Fatalf("Want %d tracked keys <= %d, Got %d", len(p.nodes), 2*size, total)
+		}
+		if p.p < 0 || p.p > size {
+			t.Fatalf("Want 0 <= p <= %d, Got %d", size, p.p)
+		}
+	}
+}
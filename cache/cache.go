@@ -0,0 +1,495 @@
+// Package cache provides bounded key-value caches with pluggable eviction
+// policies.
+package cache
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+type entry[K, V any] struct {
+	key   K
+	value V
+
+	// expires is the time after which the entry is treated as absent, or the
+	// zero Time if the entry never expires.
+	expires time.Time
+	// weight is the entry's cost as computed by the cache's weigher.
+	weight int
+	// heapIndex is the entry's index in the cache's expiry heap, or -1 if it
+	// never expires.
+	heapIndex int
+	// refreshAt is the time after which a loading cache with RefreshAhead
+	// reloads the entry, or the zero Time if it is not due a refresh.
+	refreshAt time.Time
+	// pins is the number of Pins of the entry not yet Unpinned. A pinned
+	// entry is not tracked by the cache's Policy or expiry heap.
+	pins int
+
+	// putAt is when the entry's value was Put. lastAccess, in Unix
+	// nanoseconds, and hits are updated by Get, which may hold only a read
+	// lock.
+	putAt      time.Time
+	lastAccess atomic.Int64
+	hits       atomic.Uint64
+}
+
+// access records a hit on e at now.
+func (e *entry[K, V]) access(now time.Time) {
+	e.lastAccess.Store(now.UnixNano())
+	e.hits.Add(1)
+}
+
+// EntryInfo describes an entry of a Cache, for debugging or for deciding
+// which entries to Delete or Pin.
+type EntryInfo[K, V any] struct {
+	Key   K
+	Value V
+	// Put is when the entry's value was Put.
+	Put time.Time
+	// LastAccess is when the entry was last read by Get, or the zero Time if
+	// it hasn't been.
+	LastAccess time.Time
+	// Hits is the number of Gets which have read the entry's value.
+	Hits uint64
+	// Expires is when the entry expires, or the zero Time if it never does.
+	Expires time.Time
+	Weight  int
+	Pinned  bool
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// Cache is a key-value map holding a bounded number of entries. Every Put
+// first removes any expired entries, and then if the cache is full, inserting
+// a new key evicts the entry chosen by the cache's Policy.
+// Entries expiring at different times are tracked in a heap beside the
+// Policy, so an LRU Cache with TTLs needs no separate expiry wrapper. A Cache
+// is safe for concurrent use by multiple goroutines.
+type Cache[K comparable, V any] struct {
+	mu sync.RWMutex
+
+	policy     Policy[K]
+	maxEntries int
+	maxWeight  int
+	weigher    func(K, V) int
+	ttl        time.Duration
+	onEvict    func(K, V)
+	// refreshAhead is the fraction of an entry's TTL after which it is due a
+	// refresh, or 0.
+	refreshAhead float64
+
+	// now returns the current time, and can be replaced in tests.
+	now func() time.Time
+
+	entries map[K]*entry[K, V]
+	// expiring holds the entries which expire, the earliest at the top.
+	expiring ds.BinaryHeap[*entry[K, V]]
+	// weight is the total weight of the entries.
+	weight int
+
+	stats statsCounter
+}
+
+// New returns a pointer to a new, empty Cache which evicts entries according
+// to policy. The policy must not be shared with any other Cache. New supports
+// the MaxEntries() (default: 0, unbounded), MaxWeight() (default: 0,
+// unbounded), Weigher() (default: every entry weighs 1), TTL() (default: 0, no
+// expiry) and OnEvict() Options.
+func New[K comparable, V any](policy Policy[K], opts ...Option) *Cache[K, V] {
+	o, onEvict := initCacheOptions[K, V](opts)
+	return newCache[K, V](policy, o, onEvict)
+}
+
+// NewLRUWithTTL returns a pointer to a new, empty Cache holding at most
+// maxEntries entries, which evicts the least recently used entry when full,
+// and in which entries expire ttl after they are Put unless Put with
+// PutWithTTL. It supports the same Options as New.
+func NewLRUWithTTL[K comparable, V any](maxEntries int, ttl time.Duration, opts ...Option) *Cache[K, V] {
+	return New[K, V](LRU[K](), append([]Option{MaxEntries(maxEntries), TTL(ttl)}, opts...)...)
+}
+
+func newCache[K comparable, V any](policy Policy[K], o cacheOpts, onEvict func(K, V)) *Cache[K, V] {
+	c := &Cache[K, V]{
+		policy:       policy,
+		maxEntries:   o.maxEntries,
+		maxWeight:    o.maxWeight,
+		weigher:      initWeigher[K, V](o),
+		ttl:          o.ttl,
+		onEvict:      onEvict,
+		refreshAhead: o.refreshAhead,
+		now:          time.Now,
+		entries:      make(map[K]*entry[K, V]),
+	}
+	c.expiring.Ordering = func(e1, e2 *entry[K, V]) bool {
+		return e1.expires.Before(e2.expires)
+	}
+	c.expiring.Swapped = func(i, j int) {
+		es := c.expiring.Elems()
+		es[i].heapIndex, es[j].heapIndex = i, j
+	}
+	return c
+}
+
+// expiry returns the expiry time for an entry Put now with a TTL of ttl.
+func (c *Cache[K, V]) expiry(ttl time.Duration) time.Time {
+	if ttl == 0 {
+		return time.Time{}
+	}
+	return c.now().Add(ttl)
+}
+
+// setExpiry sets the expiry time of e, and updates its place in the expiry
+// heap. c.mu must be held.
+func (c *Cache[K, V]) setExpiry(e *entry[K, V], expires time.Time) {
+	e.expires = expires
+	switch {
+	case e.pins > 0:
+	case e.heapIndex >= 0 && expires.IsZero():
+		c.unexpire(e)
+	case e.heapIndex >= 0:
+		c.expiring.Fix(e.heapIndex)
+	case !expires.IsZero():
+		e.heapIndex = c.expiring.Len()
+		c.expiring.Push(e)
+	}
+}
+
+// unexpire removes e from the expiry heap, if it is there. c.mu must be
+// held.
+func (c *Cache[K, V]) unexpire(e *entry[K, V]) {
+	if e.heapIndex >= 0 {
+		c.expiring.Remove(e.heapIndex)
+		e.heapIndex = -1
+	}
+}
+
+// removeExpired removes the entries which have expired. c.mu must be held.
+func (c *Cache[K, V]) removeExpired() {
+	if c.expiring.Len() == 0 {
+		return
+	}
+	now := c.now()
+	for e, ok := c.expiring.Peek(); ok && e.expired(now); e, ok = c.expiring.Peek() {
+		c.remove(e.key)
+		c.stats.expirations.Add(1)
+	}
+}
+
+// lookup returns the live entry for key, if any. c.mu must be held for at
+// least reading.
+func (c *Cache[K, V]) lookup(key K) (*entry[K, V], bool) {
+	e, ok := c.entries[key]
+	if !ok || (e.pins == 0 && !e.expires.IsZero() && e.expired(c.now())) {
+		return nil, false
+	}
+	return e, true
+}
+
+// Pin exempts the entry for key from eviction and expiry until it is
+// Unpinned, and returns false if key is not in the cache. Pins are counted,
+// so an entry pinned twice must be Unpinned twice. A pinned entry still
+// counts towards MaxEntries and MaxWeight, and if every entry is pinned, Put
+// admits new entries beyond them. It can still be removed by Delete, but a
+// Put of a value heavier than MaxWeight leaves it unchanged.
+func (c *Cache[K, V]) Pin(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.lookup(key)
+	if !ok {
+		return false
+	}
+	if e.pins == 0 {
+		c.policy.Remove(key)
+		c.unexpire(e)
+	}
+	e.pins++
+	return true
+}
+
+// Unpin undoes a Pin of key, and once every Pin is undone, makes its entry
+// evictable again, and expire at its original expiry time. It returns false,
+// and does nothing, if key is not pinned, e.g. because its pinned entry was
+// Deleted since.
+func (c *Cache[K, V]) Unpin(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || e.pins == 0 {
+		return false
+	}
+	if e.pins--; e.pins > 0 {
+		return true
+	}
+	if p, ok := c.policy.(PreparingPolicy[K]); ok {
+		p.PrepareAdmit(key)
+	}
+	c.policy.Admit(key)
+	c.setExpiry(e, e.expires)
+	for (c.maxEntries > 0 && len(c.entries) > c.maxEntries || c.overWeight(0)) && c.evict() {
+	}
+	return true
+}
+
+// overWeight returns true if adding extra to the cache's weight would exceed
+// its maximum weight. c.mu must be held.
+func (c *Cache[K, V]) overWeight(extra int) bool {
+	return c.maxWeight > 0 && c.weight+extra > c.maxWeight
+}
+
+// remove deletes key from the cache and its policy. c.mu must be held.
+func (c *Cache[K, V]) remove(key K) {
+	if e, ok := c.entries[key]; ok {
+		c.weight -= e.weight
+		c.unexpire(e)
+	}
+	delete(c.entries, key)
+	c.policy.Remove(key)
+}
+
+// evict removes the entry chosen by the policy, returning false if the
+// policy has nothing left to evict. c.mu must be held.
+func (c *Cache[K, V]) evict() bool {
+	key, ok := c.policy.Evict()
+	if !ok {
+		return false
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		panic(fmt.Sprintf("cache policy evicted key %v which is not in the cache", key))
+	}
+	delete(c.entries, key)
+	c.weight -= e.weight
+	c.unexpire(e)
+	c.stats.evictions.Add(1)
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+	return true
+}
+
+func (c *Cache[K, V]) Put(key K, val V) {
+	c.PutWithTTL(key, val, c.ttl)
+}
+
+// PutWithTTL puts key and val in the cache, to expire after ttl instead of
+// the cache's TTL. A ttl of 0 means the entry never expires.
+func (c *Cache[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	if ttl < 0 {
+		panic("TTL must be >= 0")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.putLocked(key, val, ttl)
+}
+
+// Preload puts the keys and values of seq in the cache, holding its lock
+// once rather than for each Put, e.g. to warm the cache at startup. Entries
+// expire after the cache's TTL.
+func (c *Cache[K, V]) Preload(seq iter.Seq2[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range seq {
+		c.putLocked(k, v, c.ttl)
+	}
+}
+
+// setRefresh sets when e is due a refresh, given that it was Put with ttl.
+func (c *Cache[K, V]) setRefresh(e *entry[K, V], ttl time.Duration) {
+	e.refreshAt = time.Time{}
+	if c.refreshAhead > 0 && ttl > 0 {
+		e.refreshAt = c.now().Add(time.Duration(float64(ttl) * c.refreshAhead))
+	}
+}
+
+// claimRefresh returns true if the live entry for key is due a refresh, and
+// if so, marks it as no longer due, so that only one caller refreshes it.
+func (c *Cache[K, V]) claimRefresh(key K) bool {
+	if c.refreshAhead == 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.lookup(key)
+	if !ok || e.refreshAt.IsZero() || c.now().Before(e.refreshAt) {
+		return false
+	}
+	e.refreshAt = time.Time{}
+	return true
+}
+
+// putLocked puts key and val in the cache, to expire after ttl. c.mu must be
+// held.
+func (c *Cache[K, V]) putLocked(key K, val V, ttl time.Duration) {
+	// Expired entries are removed on every Put, not only when the cache is
+	// full, so that they don't pile up in a cache without bounds. When
+	// nothing has expired this only peeks at the top of the heap.
+	c.removeExpired()
+	w := c.weigher(key, val)
+	if c.maxWeight > 0 && w > c.maxWeight {
+		// The entry could never fit; admitting it would only flush the cache.
+		// A pinned entry is kept as it was instead of being removed.
+		if e, ok := c.entries[key]; ok && e.pins == 0 {
+			c.remove(key)
+		}
+		return
+	}
+
+	if e, ok := c.entries[key]; ok {
+		c.weight += w - e.weight
+		e.value, e.weight, e.putAt = val, w, c.now()
+		c.setExpiry(e, c.expiry(ttl))
+		c.setRefresh(e, ttl)
+		c.policy.Touch(key)
+		for c.overWeight(0) && c.evict() {
+		}
+		return
+	}
+
+	if p, ok := c.policy.(PreparingPolicy[K]); ok {
+		p.PrepareAdmit(key)
+	}
+	// Make room for the new entry before admitting it, so that the policy
+	// never chooses the new entry as its own victim.
+	full := func() bool {
+		return c.maxEntries > 0 && len(c.entries) >= c.maxEntries || c.overWeight(w)
+	}
+	for full() && c.evict() {
+	}
+	e := &entry[K, V]{key: key, value: val, weight: w, heapIndex: -1, putAt: c.now()}
+	c.setExpiry(e, c.expiry(ttl))
+	c.setRefresh(e, ttl)
+	c.entries[key] = e
+	c.weight += w
+	c.policy.Admit(key)
+}
+
+func (c *Cache[K, V]) Get(key K) (val V, ok bool) {
+	if p, shared := c.policy.(SharedTouchPolicy[K]); shared {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		e, ok := c.lookup(key)
+		c.stats.recordLookup(ok)
+		if !ok {
+			return val, false
+		}
+		p.TouchShared(key)
+		e.access(c.now())
+		return e.value, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.lookup(key)
+	c.stats.recordLookup(ok)
+	if !ok {
+		if _, expired := c.entries[key]; expired {
+			c.remove(key)
+			c.stats.expirations.Add(1)
+		}
+		return
+	}
+	c.policy.Touch(key)
+	e.access(c.now())
+	return e.value, true
+}
+
+// Inspect returns an iter.Seq over descriptions of the live entries of the
+// cache, in unspecified order. They are taken together when iteration
+// starts, so the cache may be used during iteration; inspecting entries
+// doesn't count as accessing them.
+func (c *Cache[K, V]) Inspect() iter.Seq[EntryInfo[K, V]] {
+	return func(yield func(EntryInfo[K, V]) bool) {
+		c.mu.RLock()
+		infos := make([]EntryInfo[K, V], 0, len(c.entries))
+		for key := range c.entries {
+			e, ok := c.lookup(key)
+			if !ok {
+				continue
+			}
+			info := EntryInfo[K, V]{
+				Key:     e.key,
+				Value:   e.value,
+				Put:     e.putAt,
+				Hits:    e.hits.Load(),
+				Expires: e.expires,
+				Weight:  e.weight,
+				Pinned:  e.pins > 0,
+			}
+			if ns := e.lastAccess.Load(); ns != 0 {
+				info.LastAccess = time.Unix(0, ns)
+			}
+			infos = append(infos, info)
+		}
+		c.mu.RUnlock()
+
+		for _, info := range infos {
+			if !yield(info) {
+				return
+			}
+		}
+	}
+}
+
+// Peek returns the value for key without updating its recency or frequency
+// in the cache's Policy.
+func (c *Cache[K, V]) Peek(key K) (val V, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.lookup(key)
+	if !ok {
+		return
+	}
+	return e.value, true
+}
+
+// Has returns true if key is in the cache. Like Peek, it does not count as
+// an access.
+func (c *Cache[K, V]) Has(key K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.lookup(key)
+	return ok
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		c.remove(key)
+	}
+}
+
+// Len returns the number of entries in the cache. Expired entries which have
+// not yet been removed are included.
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Weight returns the total weight of the entries in the cache. Expired
+// entries which have not yet been removed are included.
+func (c *Cache[K, V]) Weight() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.weight
+}
+
+// Stats returns a snapshot of the cache's statistics. Only Get counts as a
+// lookup; Peek and Has do not.
+func (c *Cache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
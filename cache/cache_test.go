@@ -0,0 +1,393 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachePolicies(t *testing.T) {
+	tcs := []struct {
+		name    string
+		policy  Policy[int]
+		evicted []int
+	}{
+		{
+			// Cache holds 3 entries: 1, 2, 3 are inserted, 1 is read three
+			// times while 2 and 3 are read once, then 4 and 5 are inserted.
+			name:    "LRU",
+			policy:  LRU[int](),
+			evicted: []int{2, 3},
+		},
+		{
+			name:    "FIFO",
+			policy:  FIFO[int](),
+			evicted: []int{1, 2},
+		},
+		{
+			name:    "LFU",
+			policy:  LFU[int](),
+			evicted: []int{2, 4},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			evicted := []int{}
+			c := New[int, string](tc.policy, MaxEntries(3), OnEvict(func(k int, _ string) {
+				evicted = append(evicted, k)
+			}))
+
+			c.Put(1, "one")
+			c.Put(2, "two")
+			c.Put(3, "three")
+			for _, k := range []int{1, 1, 2, 3, 1} {
+				if _, ok := c.Get(k); !ok {
+					t.Errorf("Want Get(%d) to be found, Got not found", k)
+				}
+			}
+			c.Put(4, "four")
+			c.Put(5, "five")
+
+			if l := c.Len(); l != 3 {
+				t.Errorf("Want Len() == 3, Got %d", l)
+			}
+			if len(evicted) != len(tc.evicted) {
+				t.Fatalf("Want evicted keys %v, Got %v", tc.evicted, evicted)
+			}
+			for i := range evicted {
+				if evicted[i] != tc.evicted[i] {
+					t.Errorf("Want evicted keys %v, Got %v", tc.evicted, evicted)
+					break
+				}
+			}
+			for _, k := range evicted {
+				if c.Has(k) {
+					t.Errorf("Want Has(%d) == false after eviction, Got true", k)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheDelete(t *testing.T) {
+	for name, p := range map[string]Policy[string]{"LRU": LRU[string](), "FIFO": FIFO[string](), "LFU": LFU[string]()} {
+		t.Run(name, func(t *testing.T) {
+			c := New[string, int](p, MaxEntries(2))
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Delete("a")
+			c.Put("c", 3)
+			if !c.Has("b") || !c.Has("c") {
+				t.Errorf("Want Has(b) && Has(c) after Delete(a), Got %t, %t", c.Has("b"), c.Has("c"))
+			}
+			c.Put("d", 4)
+			if l := c.Len(); l != 2 {
+				t.Errorf("Want Len() == 2, Got %d", l)
+			}
+		})
+	}
+}
+
+func TestOnEvictTypeMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want New() to panic for mismatched OnEvict, Got no panic")
+		}
+	}()
+	New[int, string](LRU[int](), OnEvict(func(string, int) {}))
+}
+
+func TestSLRUResistsScans(t *testing.T) {
+	c := New[int, int](SLRU[int](3), MaxEntries(5))
+	hot := []int{1, 2, 3}
+	for _, k := range hot {
+		c.Put(k, k)
+		c.Get(k)
+	}
+
+	// Scan over many keys which are only accessed once.
+	for k := 100; k < 200; k++ {
+		c.Put(k, k)
+	}
+
+	for _, k := range hot {
+		if !c.Has(k) {
+			t.Errorf("Want Has(%d) == true for protected key after scan, Got false", k)
+		}
+	}
+	if l := c.Len(); l != 5 {
+		t.Errorf("Want Len() == 5, Got %d", l)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	c := New[int, int](LRU[int](), MaxEntries(2))
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Get(1)
+	c.Get(3)
+	c.Put(3, 3)
+	c.Peek(2)
+	c.Has(2)
+
+	want := Stats{Hits: 1, Misses: 1, Evictions: 1}
+	if got := c.Stats(); got != want {
+		t.Errorf("Want Stats() == %+v, Got %+v", want, got)
+	}
+	if r := c.Stats().HitRate(); r != 0.5 {
+		t.Errorf("Want HitRate() == 0.5, Got %v", r)
+	}
+}
+
+func TestTieredCache(t *testing.T) {
+	l1 := New[int, string](LRU[int](), MaxEntries(1))
+	l2 := New[int, string](LRU[int](), MaxEntries(10))
+	c := Tiered[int, string](l1, l2)
+
+	c.Put(1, "one")
+	c.Put(2, "two")
+	if l1.Has(1) || !l1.Has(2) || !l2.Has(1) || !l2.Has(2) {
+		t.Fatalf("Want L1 == {2} and L2 == {1, 2} after Put(1), Put(2)")
+	}
+
+	if v, ok := c.Get(1); !ok || v != "one" {
+		t.Errorf(`Want Get(1) == ("one", true), Got (%q, %t)`, v, ok)
+	}
+	if !l1.Has(1) {
+		t.Errorf("Want L2 hit for 1 promoted into L1, Got L1.Has(1) == false")
+	}
+
+	c.Delete(1)
+	if c.Has(1) || l1.Has(1) || l2.Has(1) {
+		t.Errorf("Want Delete(1) to remove 1 from both tiers")
+	}
+	if l := c.Len(); l != 1 {
+		t.Errorf("Want Len() == 1, Got %d", l)
+	}
+}
+
+func TestCacheMaxWeight(t *testing.T) {
+	evicted := []string{}
+	c := New[string, string](LRU[string](), MaxWeight(10),
+		Weigher(func(_ string, v string) int { return len(v) }),
+		OnEvict(func(k string, _ string) { evicted = append(evicted, k) }))
+
+	c.Put("a", "xxxx")
+	c.Put("b", "xxxx")
+	if c.Weight() != 8 {
+		t.Errorf("Want Weight() == 8, Got %d", c.Weight())
+	}
+	// Needs 2 more than the 2 remaining, so "a" is evicted.
+	c.Put("c", "xxxx")
+	if c.Has("a") || !c.Has("b") || !c.Has("c") {
+		t.Errorf("Want a evicted and b, c present, Got evicted %v", evicted)
+	}
+
+	// Growing "b" in place evicts the least recently used other entry.
+	c.Put("b", "xxxxxxxx")
+	if c.Has("c") || c.Weight() != 8 {
+		t.Errorf("Want c evicted and Weight() == 8, Got Has(c) == %t, Weight() == %d", c.Has("c"), c.Weight())
+	}
+
+	// An entry heavier than MaxWeight is never cached, and replaces nothing.
+	c.Put("d", "xxxxxxxxxxx")
+	c.Put("b", "xxxxxxxxxxx")
+	if c.Has("d") || c.Has("b") || c.Weight() != 0 {
+		t.Errorf("Want oversized entries dropped and Weight() == 0, Got Weight() == %d", c.Weight())
+	}
+
+	c.Put("e", "xx")
+	c.Delete("e")
+	if c.Weight() != 0 {
+		t.Errorf("Want Weight() == 0 after Delete, Got %d", c.Weight())
+	}
+}
+
+func TestWeigherTypeMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want New to panic on mismatched Weigher, Got no panic")
+		}
+	}()
+	New[int, string](LRU[int](), Weigher(func(string, string) int { return 1 }))
+}
+
+func TestLRUWithTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	var evicted []string
+	c := NewLRUWithTTL[string, int](3, time.Minute, OnEvict(func(k string, _ int) { evicted = append(evicted, k) }))
+	c.now = func() time.Time { return now }
+
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, time.Second)
+	c.PutWithTTL("c", 3, 0)
+	c.Get("a")
+
+	// b has expired, so it makes room for d instead of the least recently
+	// used live entry, c.
+	now = now.Add(2 * time.Second)
+	c.Put("d", 4)
+	if c.Has("b") || !c.Has("c") || len(evicted) != 0 {
+		t.Errorf("Want expired b removed before evicting, Got Has(c) == %t, evicted %v", c.Has("c"), evicted)
+	}
+	if s := c.Stats(); s.Expirations != 1 || s.Evictions != 0 {
+		t.Errorf("Want 1 expiration and 0 evictions, Got %+v", s)
+	}
+
+	c.Put("e", 5)
+	if len(evicted) != 1 || evicted[0] != "c" {
+		t.Errorf("Want least recently used c evicted, Got %v", evicted)
+	}
+
+	// c never expired, but a and d do after the cache's TTL.
+	now = now.Add(time.Hour)
+	if c.Has("a") || c.Has("d") || c.Has("e") {
+		t.Errorf("Want a, d and e expired after the cache's TTL, Got a live entry")
+	}
+	c.PutWithTTL("e", 6, 0)
+	if v, ok := c.Get("e"); !ok || v != 6 {
+		t.Errorf("Want PutWithTTL(e, 6, 0) to make e live forever, Got (%d, %t)", v, ok)
+	}
+	c.Put("f", 7)
+	c.Put("g", 8)
+	if c.Len() != 3 || !c.Has("e") {
+		t.Errorf("Want expired a and d removed for f and g, Got Len() == %d", c.Len())
+	}
+}
+
+func TestTTLWithoutBoundsRemovesExpired(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := New[int, int](LRU[int](), TTL(time.Minute))
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		c.Put(i, i)
+	}
+	now = now.Add(time.Hour)
+	c.Put(100, 100)
+	if c.Len() != 1 || len(c.entries) != 1 {
+		t.Errorf("Want expired entries removed by Put in an unbounded cache, Got %d entries", len(c.entries))
+	}
+	if s := c.Stats(); s.Expirations != 100 {
+		t.Errorf("Want 100 expirations, Got %+v", s)
+	}
+}
+
+func TestGetCountsExpiration(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := New[int, int](LRU[int](), TTL(time.Minute))
+	c.now = func() time.Time { return now }
+
+	c.Put(1, 1)
+	now = now.Add(time.Hour)
+	if _, ok := c.Get(1); ok {
+		t.Errorf("Want Get of an expired key to miss")
+	}
+	if s := c.Stats(); s.Expirations != 1 || c.Len() != 0 {
+		t.Errorf("Want the expired entry removed by Get and counted, Got %+v with Len() == %d", s, c.Len())
+	}
+}
+
+func TestCachePin(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := New[string, int](LRU[string](), MaxEntries(2), TTL(time.Minute))
+	c.now = func() time.Time { return now }
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if !c.Pin("a") || !c.Pin("a") || c.Pin("missing") {
+		t.Errorf("Want Pin to succeed for keys in the cache only")
+	}
+	c.Put("c", 3)
+	c.Put("d", 4)
+	if !c.Has("a") || c.Has("b") || c.Has("c") || c.Len() != 2 {
+		t.Errorf("Want pinned a to survive eviction, Got Len() == %d", c.Len())
+	}
+
+	now = now.Add(time.Hour)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Want pinned a not to expire, Got (%d, %t)", v, ok)
+	}
+
+	if !c.Unpin("a") || !c.Has("a") {
+		t.Errorf("Want a still pinned after one of two Unpins, Got expired")
+	}
+	if !c.Unpin("a") || c.Has("a") {
+		t.Errorf("Want a to expire once fully unpinned, Got Has(a) == true")
+	}
+	if c.Unpin("d") {
+		t.Errorf("Want Unpin of an unpinned key to return false, Got true")
+	}
+
+	// A pinned entry can be Deleted, after which Unpin does nothing.
+	c.Pin("d")
+	c.Delete("d")
+	if c.Unpin("d") {
+		t.Errorf("Want Unpin of a deleted key to return false, Got true")
+	}
+}
+
+func TestCachePinAllOverflows(t *testing.T) {
+	c := New[int, int](LFU[int](), MaxEntries(1))
+	c.Put(1, 1)
+	c.Pin(1)
+	c.Put(2, 2)
+	if c.Len() != 2 {
+		t.Errorf("Want Put beyond MaxEntries when every entry is pinned, Got Len() == %d", c.Len())
+	}
+	c.Unpin(1)
+	if c.Len() != 1 {
+		t.Errorf("Want Unpin to evict back down to MaxEntries, Got Len() == %d", c.Len())
+	}
+}
+
+func TestCachePinOverweightPut(t *testing.T) {
+	c := New[string, string](LRU[string](), MaxWeight(4), Weigher(func(_, v string) int { return len(v) }))
+	c.Put("a", "aa")
+	c.Pin("a")
+	c.Put("a", "too heavy")
+	if v, ok := c.Get("a"); !ok || v != "aa" {
+		t.Errorf("Want pinned a unchanged by an overweight Put, Got (%s, %t)", v, ok)
+	}
+	if !c.Unpin("a") {
+		t.Errorf("Want a still pinned after an overweight Put")
+	}
+}
+
+func TestCacheInspect(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	c := New[string, int](CLOCK[string](), TTL(time.Minute))
+	c.now = func() time.Time { return now }
+
+	c.Put("a", 1)
+	now = now.Add(time.Second)
+	c.Put("b", 2)
+	c.Pin("b")
+	now = now.Add(time.Second)
+	c.Get("a")
+	c.Get("a")
+	c.Peek("b")
+
+	infos := map[string]EntryInfo[string, int]{}
+	for info := range c.Inspect() {
+		infos[info.Key] = info
+	}
+	a, b := infos["a"], infos["b"]
+	if len(infos) != 2 || a.Value != 1 || b.Value != 2 {
+		t.Fatalf("Want entries a and b, Got %+v", infos)
+	}
+	if a.Hits != 2 || !a.Put.Equal(start) || !a.LastAccess.Equal(now) || !a.Expires.Equal(start.Add(time.Minute)) {
+		t.Errorf("Want a put at start with 2 hits, the last now, Got %+v", a)
+	}
+	if b.Hits != 0 || !b.LastAccess.IsZero() || !b.Pinned || b.Weight != 1 {
+		t.Errorf("Want pinned b with no hits, Got %+v", b)
+	}
+
+	now = now.Add(time.Hour)
+	for info := range c.Inspect() {
+		if info.Key != "b" {
+			t.Errorf("Want only pinned b live after the TTL, Got %+v", info)
+		}
+	}
+}
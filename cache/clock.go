@@ -0,0 +1,95 @@
+package cache
+
+import "sync/atomic"
+
+type clockSlot[K any] struct {
+	key  K
+	used bool
+	// referenced is set when the key is accessed, and cleared when the
+	// clock hand passes over it.
+	referenced atomic.Bool
+}
+
+// clockPolicy implements the CLOCK (second-chance) algorithm. Keys are kept
+// in a ring of slots, and a hand sweeps the ring looking for a key which has
+// not been referenced since the hand last passed it.
+type clockPolicy[K comparable] struct {
+	slots []*clockSlot[K]
+	index map[K]int
+	free  []int
+	hand  int
+	size  int
+}
+
+// CLOCK returns a Policy which approximates LRU using the CLOCK
+// (second-chance) algorithm. Recording an access is a single atomic bit set
+// rather than a list manipulation, so CLOCK implements SharedTouchPolicy and
+// a Cache using it serves concurrent Gets under a read lock.
+func CLOCK[K comparable]() Policy[K] {
+	return &clockPolicy[K]{index: make(map[K]int)}
+}
+
+func (p *clockPolicy[K]) Admit(key K) {
+	var s *clockSlot[K]
+	if n := len(p.free); n > 0 {
+		i := p.free[n-1]
+		p.free = p.free[:n-1]
+		s = p.slots[i]
+		p.index[key] = i
+	} else {
+		s = &clockSlot[K]{}
+		p.index[key] = len(p.slots)
+		p.slots = append(p.slots, s)
+	}
+	s.key, s.used = key, true
+	s.referenced.Store(false)
+	p.size++
+}
+
+func (p *clockPolicy[K]) Touch(key K) {
+	p.TouchShared(key)
+}
+
+func (p *clockPolicy[K]) TouchShared(key K) {
+	if i, ok := p.index[key]; ok {
+		p.slots[i].referenced.Store(true)
+	}
+}
+
+// release frees slot i, which holds a used key.
+func (p *clockPolicy[K]) release(i int) {
+	s := p.slots[i]
+	delete(p.index, s.key)
+	var zero K
+	s.key, s.used = zero, false
+	p.free = append(p.free, i)
+	p.size--
+}
+
+func (p *clockPolicy[K]) Remove(key K) {
+	if i, ok := p.index[key]; ok {
+		p.release(i)
+	}
+}
+
+func (p *clockPolicy[K]) Evict() (key K, ok bool) {
+	if p.size == 0 {
+		return
+	}
+	// Every used slot is visited at most twice: once to clear its referenced
+	// bit, and once to evict it.
+	for {
+		i := p.hand
+		p.hand = (p.hand + 1) % len(p.slots)
+		s := p.slots[i]
+		if !s.used {
+			continue
+		}
+		if s.referenced.Swap(false) {
+			continue
+		}
+		key = s.key
+		p.release(i)
+		return key, true
+	}
+}
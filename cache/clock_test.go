@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCLOCKGivesSecondChance(t *testing.T) {
+	c := New[int, int](CLOCK[int](), MaxEntries(3))
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	c.Get(1)
+
+	// 1 was referenced, so the hand passes over it and evicts 2.
+	c.Put(4, 4)
+	if !c.Has(1) || c.Has(2) {
+		t.Errorf("Want Has(1) == true and Has(2) == false, Got %t and %t", c.Has(1), c.Has(2))
+	}
+
+	c.Delete(3)
+	c.Put(5, 5)
+	c.Put(6, 6)
+	if l := c.Len(); l != 3 {
+		t.Errorf("Want Len() == 3, Got %d", l)
+	}
+}
+
+func BenchmarkCacheGetParallel(b *testing.B) {
+	const size = 1 << 12
+	policies := []struct {
+		name   string
+		policy func() Policy[int]
+	}{
+		{"LRU", LRU[int]},
+		{"CLOCK", CLOCK[int]},
+	}
+	for _, p := range policies {
+		b.Run(p.name, func(b *testing.B) {
+			c := New[int, int](p.policy(), MaxEntries(size))
+			for i := 0; i < size; i++ {
+				c.Put(i, i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				rng := rand.New(rand.NewSource(rand.Int63()))
+				for pb.Next() {
+					c.Get(rng.Intn(size))
+				}
+			})
+		})
+	}
+}
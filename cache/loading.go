@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNotFound can be returned by a LoadingCache's loader for a key with no
+// value. With the NegativeTTL Option, it is cached like a value.
+var ErrNotFound = errors.New("cache: not found")
+
+// defaultMaxNegativeEntries is the number of errors a loading cache holds if
+// neither MaxNegativeEntries nor MaxEntries is set.
+const defaultMaxNegativeEntries = 10000
+
+// loadCall is an in-flight or completed call to a LoadingCache's loader.
+type loadCall[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// LoadingCache is a read-through Cache: a Get for a key which is not in the
+// cache calls the cache's loader to produce the value, and stores it. Calls
+// to the loader are deduplicated, so concurrent Gets for the same missing key
+// invoke the loader once and all receive its result.
+type LoadingCache[K comparable, V any] struct {
+	*Cache[K, V]
+
+	loader func(K) (V, error)
+
+	// negative caches the loader's cacheable errors, or is nil if errors
+	// aren't cached.
+	negative       *Cache[K, error]
+	cacheableError func(error) bool
+
+	mu    sync.Mutex
+	calls map[K]*loadCall[V]
+}
+
+// NewLoading returns a pointer to a new, empty LoadingCache which calls
+// loader to load missing values. Values for which loader returns an error are
+// not cached, unless NegativeTTL is set. NewLoading supports the MaxEntries()
+// (default: 0, unbounded), MaxWeight(), Weigher(), TTL() (default: 0, no
+// expiry), OnEvict(), EvictionPolicy() (default: LRU), NegativeTTL(),
+// MaxNegativeEntries(), CacheErrorIf() and RefreshAhead() Options. Cached
+// errors are held apart from values, in an LRU cache bounded by
+// MaxNegativeEntries.
+func NewLoading[K comparable, V any](loader func(K) (V, error), opts ...Option) *LoadingCache[K, V] {
+	o, onEvict := initCacheOptions[K, V](opts)
+	var policy Policy[K] = LRU[K]()
+	if o.policy != nil {
+		var ok bool
+		if policy, ok = o.policy.(Policy[K]); !ok {
+			panic(fmt.Sprintf("EvictionPolicy %T does not match cache key type %T", o.policy, *new(K)))
+		}
+	}
+	c := &LoadingCache[K, V]{
+		Cache:  newCache[K, V](policy, o, onEvict),
+		loader: loader,
+		calls:  make(map[K]*loadCall[V]),
+	}
+	if o.negativeTTL > 0 {
+		maxNegative := o.maxNegativeEntries
+		if maxNegative == 0 {
+			maxNegative = o.maxEntries
+		}
+		if maxNegative == 0 {
+			maxNegative = defaultMaxNegativeEntries
+		}
+		c.negative = New[K, error](LRU[K](), MaxEntries(maxNegative), TTL(o.negativeTTL))
+		c.cacheableError = o.cacheableError
+		if c.cacheableError == nil {
+			c.cacheableError = func(err error) bool { return errors.Is(err, ErrNotFound) }
+		}
+	}
+	return c
+}
+
+// Get returns the value for key, calling the cache's loader if it is not
+// present. The error returned by the loader, if any, is returned to every
+// caller waiting on that load.
+func (c *LoadingCache[K, V]) Get(key K) (V, error) {
+	if val, ok := c.Cache.Get(key); ok {
+		if c.claimRefresh(key) {
+			go c.refresh(key)
+		}
+		return val, nil
+	}
+	if c.negative != nil {
+		if err, ok := c.negative.Get(key); ok {
+			var zero V
+			return zero, err
+		}
+	}
+
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.val, call.err
+	}
+	call := &loadCall[V]{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	c.load(key, call)
+	return call.val, call.err
+}
+
+// refresh reloads key in the background, unless a load of key is already in
+// flight. Errors are dropped, leaving the old value to expire.
+func (c *LoadingCache[K, V]) refresh(key K) {
+	c.mu.Lock()
+	if _, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		return
+	}
+	call := &loadCall[V]{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	c.load(key, call)
+}
+
+// load calls the loader for key, storing the result in call and, if
+// successful, in the cache. Waiters are released even if the loader panics.
+func (c *LoadingCache[K, V]) load(key K, call *loadCall[V]) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	start := time.Now()
+	// If the loader panics, waiters receive this error instead.
+	call.err = fmt.Errorf("loader for key %v panicked", key)
+	call.val, call.err = c.loader(key)
+	c.stats.recordLoad(time.Since(start), call.err)
+	if call.err == nil {
+		c.Put(key, call.val)
+	} else if c.negative != nil && c.cacheableError(call.err) {
+		c.negative.Put(key, call.err)
+	}
+}
+
+// Delete removes key from the cache, along with any cached error for it.
+func (c *LoadingCache[K, V]) Delete(key K) {
+	c.Cache.Delete(key)
+	if c.negative != nil {
+		c.negative.Delete(key)
+	}
+}
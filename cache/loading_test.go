@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadingCacheDeduplicatesLoads(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := NewLoading(func(k int) (string, error) {
+		calls.Add(1)
+		<-release
+		return "loaded", nil
+	}, MaxEntries(10))
+
+	const n = 10
+	var started, wg sync.WaitGroup
+	started.Add(n)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			started.Done()
+			if v, err := c.Get(1); err != nil || v != "loaded" {
+				t.Errorf(`Want Get(1) == ("loaded", nil), Got (%q, %v)`, v, err)
+			}
+		}()
+	}
+	started.Wait()
+	// Give the goroutines a chance to block on the in-flight load.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Want loader called once, Got %d calls", n)
+	}
+	if s := c.Stats(); s.LoadSuccesses != 1 {
+		t.Errorf("Want Stats().LoadSuccesses == 1, Got %d", s.LoadSuccesses)
+	}
+}
+
+func TestLoadingCacheDoesNotCacheErrors(t *testing.T) {
+	errNotFound := errors.New("not found")
+	calls := 0
+	c := NewLoading(func(k string) (int, error) {
+		calls++
+		return 0, errNotFound
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get("a"); err != errNotFound {
+			t.Errorf("Want Get(a) to return %v, Got %v", errNotFound, err)
+		}
+	}
+	if calls != 2 || c.Has("a") {
+		t.Errorf("Want 2 loader calls and Has(a) == false, Got %d and %t", calls, c.Has("a"))
+	}
+	if s := c.Stats(); s.LoadFailures != 2 {
+		t.Errorf("Want Stats().LoadFailures == 2, Got %d", s.LoadFailures)
+	}
+}
+
+func TestLoadingCacheTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	calls := 0
+	c := NewLoading(func(k int) (int, error) {
+		calls++
+		return k * calls, nil
+	}, TTL(time.Minute), EvictionPolicy(FIFO[int]()))
+	c.now = func() time.Time { return now }
+
+	if v, _ := c.Get(2); v != 2 {
+		t.Errorf("Want Get(2) == 2, Got %d", v)
+	}
+	now = now.Add(30 * time.Second)
+	if v, _ := c.Get(2); v != 2 {
+		t.Errorf("Want Get(2) == 2 before expiry, Got %d", v)
+	}
+	now = now.Add(time.Minute)
+	if c.Has(2) {
+		t.Errorf("Want Has(2) == false after expiry, Got true")
+	}
+	if v, _ := c.Get(2); v != 4 {
+		t.Errorf("Want Get(2) == 4 after reload, Got %d", v)
+	}
+}
+
+func TestLoadingCacheNegativeTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	calls := map[string]int{}
+	errTimeout := errors.New("timeout")
+	c := NewLoading(func(k string) (int, error) {
+		calls[k]++
+		switch k {
+		case "missing":
+			return 0, fmt.Errorf("no row for %s: %w", k, ErrNotFound)
+		case "slow":
+			return 0, errTimeout
+		}
+		return len(k), nil
+	}, NegativeTTL(time.Second))
+	c.negative.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, err := c.Get("missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Want Get(missing) to return ErrNotFound, Got %v", err)
+		}
+		if _, err := c.Get("slow"); err != errTimeout {
+			t.Errorf("Want Get(slow) to return the timeout, Got %v", err)
+		}
+	}
+	if calls["missing"] != 1 || calls["slow"] != 3 {
+		t.Errorf("Want only the not found error cached, Got %v loader calls", calls)
+	}
+
+	now = now.Add(2 * time.Second)
+	c.Get("missing")
+	if calls["missing"] != 2 {
+		t.Errorf("Want the loader called again after NegativeTTL, Got %d calls", calls["missing"])
+	}
+	c.Delete("missing")
+	c.Get("missing")
+	if calls["missing"] != 3 {
+		t.Errorf("Want Delete to drop the cached error, Got %d calls", calls["missing"])
+	}
+}
+
+func TestLoadingCacheNegativeEntriesBounded(t *testing.T) {
+	notFound := func(int) (int, error) { return 0, ErrNotFound }
+	tcs := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"Default", nil, defaultMaxNegativeEntries},
+		{"MaxEntries", []Option{MaxEntries(100)}, 100},
+		{"MaxNegativeEntries", []Option{MaxEntries(100), MaxNegativeEntries(10)}, 10},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewLoading(notFound, append(tc.opts, NegativeTTL(time.Hour))...)
+			for i := 0; i < defaultMaxNegativeEntries+1000; i++ {
+				c.Get(i)
+			}
+			if n := c.negative.Len(); n != tc.want {
+				t.Errorf("Want %d cached errors, Got %d", tc.want, n)
+			}
+		})
+	}
+}
+
+func TestLoadingCacheCacheErrorIf(t *testing.T) {
+	calls := 0
+	errGone := errors.New("gone")
+	c := NewLoading(func(k int) (int, error) {
+		calls++
+		return 0, errGone
+	}, NegativeTTL(time.Minute), CacheErrorIf(func(err error) bool { return err == errGone }))
+	c.Get(1)
+	if _, err := c.Get(1); err != errGone || calls != 1 {
+		t.Errorf("Want cached errGone from 1 loader call, Got %v from %d calls", err, calls)
+	}
+}
+
+func TestLoadingCacheRefreshAhead(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Unix(0, 0)
+	var calls atomic.Int32
+	c := NewLoading(func(k int) (int, error) {
+		return k * int(calls.Add(1)), nil
+	}, TTL(time.Minute), RefreshAhead(0.5))
+	c.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+
+	c.Get(10)
+	advance(20 * time.Second)
+	if v, _ := c.Get(10); v != 10 || calls.Load() != 1 {
+		t.Errorf("Want no refresh before half the TTL, Got %d after %d calls", v, calls.Load())
+	}
+	advance(20 * time.Second)
+	if v, _ := c.Get(10); v != 10 {
+		t.Errorf("Want the old value while refreshing, Got %d", v)
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		if v, _ := c.Peek(10); v == 20 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// The refreshed entry is good for a full TTL from when it was reloaded.
+	advance(50 * time.Second)
+	if v, _ := c.Get(10); v != 20 || calls.Load() != 2 {
+		t.Errorf("Want refreshed value 20 after 2 calls, Got %d after %d calls", v, calls.Load())
+	}
+}
+
+func TestCachePreload(t *testing.T) {
+	c := New[string, int](LRU[string](), MaxEntries(2))
+	c.Preload(maps.All(map[string]int{"a": 1, "b": 2, "c": 3}))
+	if c.Len() != 2 {
+		t.Errorf("Want Preload bounded by MaxEntries, Got Len() == %d", c.Len())
+	}
+	if s := c.Stats(); s.Evictions != 1 || s.Requests() != 0 {
+		t.Errorf("Want 1 eviction and no lookups, Got %+v", s)
+	}
+}
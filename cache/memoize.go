@@ -0,0 +1,20 @@
+package cache
+
+// Memoize returns a function which returns f(key), calling f only if the
+// result for key is not already cached. The returned function is safe for
+// concurrent use, and concurrent calls for the same key call f once. Memoize
+// supports the same Options as NewLoading.
+func Memoize[K comparable, V any](f func(K) V, opts ...Option) func(K) V {
+	c := NewLoading(func(key K) (V, error) { return f(key), nil }, opts...)
+	return func(key K) V {
+		v, _ := c.Get(key)
+		return v
+	}
+}
+
+// MemoizeE is like Memoize for functions which can fail. Results for which f
+// returns an error are not cached, so a later call for the same key calls f
+// again.
+func MemoizeE[K comparable, V any](f func(K) (V, error), opts ...Option) func(K) (V, error) {
+	return NewLoading(f, opts...).Get
+}
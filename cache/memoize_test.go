@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	square := Memoize(func(n int) int {
+		calls++
+		return n * n
+	}, MaxEntries(2))
+
+	for _, n := range []int{2, 2, 3, 2} {
+		if got := square(n); got != n*n {
+			t.Errorf("Want square(%d) == %d, Got %d", n, n*n, got)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Want 2 calls to f, Got %d", calls)
+	}
+
+	// 4 evicts 3, the least recently used.
+	square(4)
+	square(3)
+	if calls != 4 {
+		t.Errorf("Want 4 calls to f after eviction, Got %d", calls)
+	}
+}
+
+func TestMemoizeEDoesNotCacheErrors(t *testing.T) {
+	calls := 0
+	errFail := errors.New("fail")
+	f := MemoizeE(func(n int) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errFail
+		}
+		return n, nil
+	})
+
+	if _, err := f(1); err != errFail {
+		t.Errorf("Want first call to fail with %v, Got %v", errFail, err)
+	}
+	if v, err := f(1); err != nil || v != 1 {
+		t.Errorf("Want second call == (1, nil), Got (%d, %v)", v, err)
+	}
+	if v, err := f(1); err != nil || v != 1 || calls != 2 {
+		t.Errorf("Want cached (1, nil) after 2 calls, Got (%d, %v) after %d calls", v, err, calls)
+	}
+}
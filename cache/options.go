@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+type cacheOpts struct {
+	maxEntries int
+	maxWeight  int
+	weigher    any
+	ttl        time.Duration
+	onEvict    any
+	policy     any
+
+	batchSize     int
+	flushInterval time.Duration
+	onWriteError  any
+	onFlush       func(int)
+
+	negativeTTL        time.Duration
+	maxNegativeEntries int
+	cacheableError     func(error) bool
+	refreshAhead       float64
+}
+
+// Option is an interface which wraps an adjustable parameter for a cache at
+// creation. An Option should only be created via one of the functions below.
+type Option interface {
+	setOpt(*cacheOpts)
+	String() string
+}
+
+type maxEntriesOpt int
+
+func (o maxEntriesOpt) setOpt(opts *cacheOpts) {
+	opts.maxEntries = int(o)
+}
+
+func (o maxEntriesOpt) String() string { return fmt.Sprintf("MaxEntries(%v)", int(o)) }
+
+// MaxEntries returns an Option which sets the maximum number of entries the
+// cache holds before evicting. A value of 0 means the cache is unbounded.
+func MaxEntries(n int) Option {
+	if n < 0 {
+		panic("MaxEntries must be >= 0")
+	}
+	return maxEntriesOpt(n)
+}
+
+type maxWeightOpt int
+
+func (o maxWeightOpt) setOpt(opts *cacheOpts) {
+	opts.maxWeight = int(o)
+}
+
+func (o maxWeightOpt) String() string { return fmt.Sprintf("MaxWeight(%v)", int(o)) }
+
+// MaxWeight returns an Option which sets the maximum total weight of the
+// entries in the cache, as computed by its Weigher. Entries are evicted until
+// a new entry fits; an entry heavier than n is never cached. A value of 0
+// means the cache's weight is unbounded.
+func MaxWeight(n int) Option {
+	if n < 0 {
+		panic("MaxWeight must be >= 0")
+	}
+	return maxWeightOpt(n)
+}
+
+type weigherOpt struct {
+	f any
+}
+
+func (o weigherOpt) setOpt(opts *cacheOpts) {
+	opts.weigher = o.f
+}
+
+func (o weigherOpt) String() string { return fmt.Sprintf("Weigher(%T)", o.f) }
+
+// Weigher returns an Option which sets the function used to compute the
+// weight of each entry, e.g. its approximate size in bytes, for MaxWeight. f
+// is called with the cache's lock held and must return a value >= 0. The key
+// and value types of f must match those of the cache it is passed to,
+// otherwise the constructor panics.
+func Weigher[K, V any](f func(key K, val V) int) Option {
+	return weigherOpt{f}
+}
+
+type onEvictOpt struct {
+	f any
+}
+
+func (o onEvictOpt) setOpt(opts *cacheOpts) {
+	opts.onEvict = o.f
+}
+
+func (o onEvictOpt) String() string { return fmt.Sprintf("OnEvict(%T)", o.f) }
+
+// OnEvict returns an Option which registers f to be called with each entry
+// the cache evicts. f is called with the cache's lock held, so it must not
+// call back into the cache. The key and value types of f must match those of
+// the cache it is passed to, otherwise New panics.
+func OnEvict[K, V any](f func(key K, val V)) Option {
+	return onEvictOpt{f}
+}
+
+type ttlOpt time.Duration
+
+func (o ttlOpt) setOpt(opts *cacheOpts) {
+	opts.ttl = time.Duration(o)
+}
+
+func (o ttlOpt) String() string { return fmt.Sprintf("TTL(%v)", time.Duration(o)) }
+
+// TTL returns an Option which sets how long an entry remains in the cache
+// after it is Put. Expired entries are treated as absent. A TTL of 0 means
+// entries never expire.
+func TTL(d time.Duration) Option {
+	if d < 0 {
+		panic("TTL must be >= 0")
+	}
+	return ttlOpt(d)
+}
+
+type evictionPolicyOpt struct {
+	p any
+}
+
+func (o evictionPolicyOpt) setOpt(opts *cacheOpts) {
+	opts.policy = o.p
+}
+
+func (o evictionPolicyOpt) String() string { return fmt.Sprintf("EvictionPolicy(%T)", o.p) }
+
+// EvictionPolicy returns an Option which sets the Policy of a cache whose
+// constructor does not take one explicitly. The key type of p must match that
+// of the cache it is passed to, otherwise the constructor panics.
+func EvictionPolicy[K comparable](p Policy[K]) Option {
+	return evictionPolicyOpt{p}
+}
+
+type batchSizeOpt int
+
+func (o batchSizeOpt) setOpt(opts *cacheOpts) {
+	opts.batchSize = int(o)
+}
+
+func (o batchSizeOpt) String() string { return fmt.Sprintf("BatchSize(%v)", int(o)) }
+
+// BatchSize returns an Option which sets the number of queued writes which
+// causes a write-behind cache to flush to its Store.
+func BatchSize(n int) Option {
+	if n <= 0 {
+		panic("BatchSize must be > 0")
+	}
+	return batchSizeOpt(n)
+}
+
+type flushIntervalOpt time.Duration
+
+func (o flushIntervalOpt) setOpt(opts *cacheOpts) {
+	opts.flushInterval = time.Duration(o)
+}
+
+func (o flushIntervalOpt) String() string {
+	return fmt.Sprintf("FlushInterval(%v)", time.Duration(o))
+}
+
+// FlushInterval returns an Option which sets the maximum time a write-behind
+// cache holds queued writes before flushing them to its Store.
+func FlushInterval(d time.Duration) Option {
+	if d <= 0 {
+		panic("FlushInterval must be > 0")
+	}
+	return flushIntervalOpt(d)
+}
+
+type onWriteErrorOpt struct {
+	f any
+}
+
+func (o onWriteErrorOpt) setOpt(opts *cacheOpts) {
+	opts.onWriteError = o.f
+}
+
+func (o onWriteErrorOpt) String() string { return fmt.Sprintf("OnWriteError(%T)", o.f) }
+
+// OnWriteError returns an Option which registers f to be called when a
+// write-through or write-behind cache fails to propagate a write for key to
+// its Store. The key type of f must match that of the cache it is passed to,
+// otherwise the constructor panics.
+func OnWriteError[K any](f func(key K, err error)) Option {
+	return onWriteErrorOpt{f}
+}
+
+type onFlushOpt func(int)
+
+func (o onFlushOpt) setOpt(opts *cacheOpts) {
+	opts.onFlush = o
+}
+
+func (o onFlushOpt) String() string { return "OnFlush(func(int))" }
+
+// OnFlush returns an Option which registers f to be called with the number of
+// writes after each batch a write-behind cache flushes to its Store.
+func OnFlush(f func(writes int)) Option {
+	return onFlushOpt(f)
+}
+
+type negativeTTLOpt time.Duration
+
+func (o negativeTTLOpt) setOpt(opts *cacheOpts) {
+	opts.negativeTTL = time.Duration(o)
+}
+
+func (o negativeTTLOpt) String() string { return fmt.Sprintf("NegativeTTL(%v)", time.Duration(o)) }
+
+// NegativeTTL returns an Option which makes a loading cache cache the errors
+// its loader returns for missing keys, for d, so that repeated Gets for a key
+// which doesn't exist don't each call the loader. Which errors are cached is
+// decided by CacheErrorIf (default: errors matching ErrNotFound). A
+// NegativeTTL of 0, the default, caches no errors.
+func NegativeTTL(d time.Duration) Option {
+	if d < 0 {
+		panic("NegativeTTL must be >= 0")
+	}
+	return negativeTTLOpt(d)
+}
+
+type maxNegativeEntriesOpt int
+
+func (o maxNegativeEntriesOpt) setOpt(opts *cacheOpts) {
+	opts.maxNegativeEntries = int(o)
+}
+
+func (o maxNegativeEntriesOpt) String() string { return fmt.Sprintf("MaxNegativeEntries(%v)", int(o)) }
+
+// MaxNegativeEntries returns an Option which sets the maximum number of
+// errors a loading cache with a NegativeTTL holds, evicting the least
+// recently used beyond it. Each Get of a distinct missing key may add an
+// error, so cached errors are always bounded: by default by MaxEntries if
+// it is set, or else by 10000.
+func MaxNegativeEntries(n int) Option {
+	if n <= 0 {
+		panic("MaxNegativeEntries must be > 0")
+	}
+	return maxNegativeEntriesOpt(n)
+}
+
+type cacheErrorIfOpt func(error) bool
+
+func (o cacheErrorIfOpt) setOpt(opts *cacheOpts) {
+	opts.cacheableError = o
+}
+
+func (o cacheErrorIfOpt) String() string { return "CacheErrorIf(func(error) bool)" }
+
+// CacheErrorIf returns an Option which sets the function deciding whether an
+// error returned by a loading cache's loader is cached for the NegativeTTL.
+// Transient errors, such as timeouts, should not be.
+func CacheErrorIf(f func(err error) bool) Option {
+	return cacheErrorIfOpt(f)
+}
+
+type refreshAheadOpt float64
+
+func (o refreshAheadOpt) setOpt(opts *cacheOpts) {
+	opts.refreshAhead = float64(o)
+}
+
+func (o refreshAheadOpt) String() string { return fmt.Sprintf("RefreshAhead(%v)", float64(o)) }
+
+// RefreshAhead returns an Option which makes a loading cache with a TTL
+// reload an entry in the background once fraction of its TTL has passed, on
+// the first Get after that. Gets return the old value until the new one is
+// loaded, so a frequently read entry never expires and no Get waits for the
+// loader at the TTL boundary. fraction must be in the range (0, 1).
+func RefreshAhead(fraction float64) Option {
+	if !(fraction > 0 && fraction < 1) {
+		panic(fmt.Sprintf("RefreshAhead fraction %v out of range (0.0, 1.0)", fraction))
+	}
+	return refreshAheadOpt(fraction)
+}
+
+func initCacheOptions[K, V any](opts []Option) (r cacheOpts, onEvict func(K, V)) {
+	for _, opt := range opts {
+		opt.setOpt(&r)
+	}
+	if r.onEvict != nil {
+		var ok bool
+		if onEvict, ok = r.onEvict.(func(K, V)); !ok {
+			panic(fmt.Sprintf("OnEvict callback %T does not match cache type %T", r.onEvict, onEvict))
+		}
+	}
+	return r, onEvict
+}
+
+func initOnWriteError[K any](o cacheOpts) func(K, error) {
+	if o.onWriteError == nil {
+		return func(K, error) {}
+	}
+	f, ok := o.onWriteError.(func(K, error))
+	if !ok {
+		panic(fmt.Sprintf("OnWriteError callback %T does not match cache key type %T", o.onWriteError, *new(K)))
+	}
+	return f
+}
+
+func initWeigher[K, V any](o cacheOpts) func(K, V) int {
+	if o.weigher == nil {
+		return func(K, V) int { return 1 }
+	}
+	f, ok := o.weigher.(func(K, V) int)
+	if !ok {
+		panic(fmt.Sprintf("Weigher %T does not match cache type %T", o.weigher, f))
+	}
+	return func(key K, val V) int {
+		w := f(key, val)
+		if w < 0 {
+			panic(fmt.Sprintf("Weigher returned negative weight %d for key %v", w, key))
+		}
+		return w
+	}
+}
@@ -0,0 +1,219 @@
+package cache
+
+// A Policy decides which entry a Cache evicts when it is full. The Cache
+// serializes all calls to its Policy, so implementations need not be safe for
+// concurrent use.
+type Policy[K comparable] interface {
+	// Admit is called after key is inserted into the cache.
+	Admit(key K)
+	// Touch is called when the value for key is read or replaced.
+	Touch(key K)
+	// Remove is called when key is deleted from the cache by the user. It is
+	// not called for keys returned by Evict.
+	Remove(key K)
+	// Evict chooses a key to be evicted, forgets it, and returns it. ok is
+	// false if the policy is tracking no keys.
+	Evict() (key K, ok bool)
+}
+
+// A PreparingPolicy is a Policy which needs to know which key is about to be
+// inserted before the Cache evicts entries to make room for it. This allows
+// policies such as ARC to adapt to the incoming key before choosing a victim.
+type PreparingPolicy[K comparable] interface {
+	Policy[K]
+	// PrepareAdmit is called with a key which is not in the cache, before
+	// any calls to Evict made to make room for it and before Admit.
+	PrepareAdmit(key K)
+}
+
+// A SharedTouchPolicy is a Policy which can record reads without exclusive
+// access. A Cache with a SharedTouchPolicy calls TouchShared instead of Touch
+// on Get, holding only a read lock, so concurrent readers do not contend.
+// TouchShared may be called concurrently with itself, but never concurrently
+// with any other Policy method.
+type SharedTouchPolicy[K comparable] interface {
+	Policy[K]
+	TouchShared(key K)
+}
+
+// node is an element of an intrusive doubly-linked list of keys.
+type node[K any] struct {
+	key        K
+	prev, next *node[K]
+}
+
+// list is a doubly-linked list of keys, ordered from head to tail.
+type list[K any] struct {
+	head, tail *node[K]
+	len        int
+}
+
+func (l *list[K]) pushBack(n *node[K]) {
+	n.prev, n.next = l.tail, nil
+	if l.tail == nil {
+		l.head = n
+	} else {
+		l.tail.next = n
+	}
+	l.tail = n
+	l.len++
+}
+
+func (l *list[K]) remove(n *node[K]) {
+	if n.prev == nil {
+		l.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		l.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.prev, n.next = nil, nil
+	l.len--
+}
+
+func (l *list[K]) moveToBack(n *node[K]) {
+	if l.tail == n {
+		return
+	}
+	l.remove(n)
+	l.pushBack(n)
+}
+
+// listPolicy evicts keys from the head of a list of keys in insertion order.
+// If moveOnTouch is set, touched keys are moved to the tail of the list.
+type listPolicy[K comparable] struct {
+	moveOnTouch bool
+
+	nodes map[K]*node[K]
+	order list[K]
+}
+
+// LRU returns a Policy which evicts the least-recently used key.
+func LRU[K comparable]() Policy[K] {
+	return &listPolicy[K]{moveOnTouch: true, nodes: make(map[K]*node[K])}
+}
+
+// FIFO returns a Policy which evicts the least-recently inserted key,
+// regardless of how often or recently it has been used.
+func FIFO[K comparable]() Policy[K] {
+	return &listPolicy[K]{nodes: make(map[K]*node[K])}
+}
+
+func (p *listPolicy[K]) Admit(key K) {
+	n := &node[K]{key: key}
+	p.nodes[key] = n
+	p.order.pushBack(n)
+}
+
+func (p *listPolicy[K]) Touch(key K) {
+	if !p.moveOnTouch {
+		return
+	}
+	if n, ok := p.nodes[key]; ok {
+		p.order.moveToBack(n)
+	}
+}
+
+func (p *listPolicy[K]) Remove(key K) {
+	if n, ok := p.nodes[key]; ok {
+		p.order.remove(n)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *listPolicy[K]) Evict() (key K, ok bool) {
+	n := p.order.head
+	if n == nil {
+		return
+	}
+	p.order.remove(n)
+	delete(p.nodes, n.key)
+	return n.key, true
+}
+
+// lfuNode is a key tracked by an lfuPolicy, with its access count.
+type lfuNode[K any] struct {
+	node[K]
+	freq int
+}
+
+// lfuPolicy keeps a list of keys for each access count, so that every
+// operation is O(1).
+type lfuPolicy[K comparable] struct {
+	nodes   map[K]*lfuNode[K]
+	buckets map[int]*list[K]
+	minFreq int
+}
+
+// LFU returns a Policy which evicts the least-frequently used key. Ties are
+// broken by evicting the least-recently used of the keys with the lowest
+// access count.
+func LFU[K comparable]() Policy[K] {
+	return &lfuPolicy[K]{nodes: make(map[K]*lfuNode[K]), buckets: make(map[int]*list[K])}
+}
+
+func (p *lfuPolicy[K]) bucket(freq int) *list[K] {
+	b, ok := p.buckets[freq]
+	if !ok {
+		b = &list[K]{}
+		p.buckets[freq] = b
+	}
+	return b
+}
+
+// unlink removes n from its frequency bucket, dropping the bucket if it
+// becomes empty.
+func (p *lfuPolicy[K]) unlink(n *lfuNode[K]) {
+	b := p.buckets[n.freq]
+	b.remove(&n.node)
+	if b.len == 0 {
+		delete(p.buckets, n.freq)
+	}
+}
+
+func (p *lfuPolicy[K]) Admit(key K) {
+	n := &lfuNode[K]{node: node[K]{key: key}, freq: 1}
+	p.nodes[key] = n
+	p.bucket(1).pushBack(&n.node)
+	p.minFreq = 1
+}
+
+func (p *lfuPolicy[K]) Touch(key K) {
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	p.unlink(n)
+	if p.minFreq == n.freq && p.buckets[n.freq] == nil {
+		p.minFreq++
+	}
+	n.freq++
+	p.bucket(n.freq).pushBack(&n.node)
+}
+
+func (p *lfuPolicy[K]) Remove(key K) {
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	p.unlink(n)
+	delete(p.nodes, key)
+	// minFreq is recomputed lazily by Evict.
+}
+
+func (p *lfuPolicy[K]) Evict() (key K, ok bool) {
+	if len(p.nodes) == 0 {
+		return
+	}
+	for p.buckets[p.minFreq] == nil {
+		p.minFreq++
+	}
+	b := p.buckets[p.minFreq]
+	n := b.head
+	p.unlink(p.nodes[n.key])
+	delete(p.nodes, n.key)
+	return n.key, true
+}
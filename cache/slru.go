@@ -0,0 +1,91 @@
+package cache
+
+type slruNode[K any] struct {
+	node[K]
+	protected bool
+}
+
+// slruPolicy is a segmented LRU. Keys enter the probationary segment, and
+// are promoted to the protected segment when accessed again. Victims are
+// taken from the probationary segment first, so a scan of keys which are
+// only accessed once cannot flush the protected segment.
+type slruPolicy[K comparable] struct {
+	protectedCap int
+
+	nodes                 map[K]*slruNode[K]
+	probation, protection list[K]
+}
+
+// SLRU returns a segmented LRU Policy whose protected segment holds at most
+// protectedCapacity keys. Keys which are accessed at least twice while in the
+// cache are protected; keys demoted from the protected segment when it is full
+// get another chance in the probationary segment. SLRU performs well on
+// database-page-like access patterns, where a hot working set is interleaved
+// with large scans. A protectedCapacity of around 80% of the cache's
+// MaxEntries is typical.
+func SLRU[K comparable](protectedCapacity int) Policy[K] {
+	if protectedCapacity < 0 {
+		panic("SLRU protected capacity must be >= 0")
+	}
+	return &slruPolicy[K]{protectedCap: protectedCapacity, nodes: make(map[K]*slruNode[K])}
+}
+
+func (p *slruPolicy[K]) Admit(key K) {
+	n := &slruNode[K]{node: node[K]{key: key}}
+	p.nodes[key] = n
+	p.probation.pushBack(&n.node)
+}
+
+func (p *slruPolicy[K]) Touch(key K) {
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	if n.protected {
+		p.protection.moveToBack(&n.node)
+		return
+	}
+	if p.protectedCap == 0 {
+		p.probation.moveToBack(&n.node)
+		return
+	}
+
+	p.probation.remove(&n.node)
+	n.protected = true
+	p.protection.pushBack(&n.node)
+	if p.protection.len > p.protectedCap {
+		// Demote the least-recently used protected key to the
+		// most-recently used end of the probationary segment.
+		d := p.nodes[p.protection.head.key]
+		p.protection.remove(&d.node)
+		d.protected = false
+		p.probation.pushBack(&d.node)
+	}
+}
+
+func (p *slruPolicy[K]) Remove(key K) {
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	if n.protected {
+		p.protection.remove(&n.node)
+	} else {
+		p.probation.remove(&n.node)
+	}
+	delete(p.nodes, key)
+}
+
+func (p *slruPolicy[K]) Evict() (key K, ok bool) {
+	l := &p.probation
+	if l.head == nil {
+		l = &p.protection
+	}
+	n := l.head
+	if n == nil {
+		return
+	}
+	l.remove(n)
+	delete(p.nodes, n.key)
+	return n.key, true
+}
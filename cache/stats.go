@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+// Stats is a point-in-time snapshot of a cache's statistics.
+type Stats struct {
+	// Hits and Misses count the lookups which did and did not find a value.
+	Hits, Misses uint64
+	// Evictions counts the entries removed to make room for new entries.
+	// Entries removed by Delete are not counted.
+	Evictions uint64
+	// Expirations counts the expired entries removed by Put, which happens
+	// before any live entry is evicted, or found and removed by Get.
+	Expirations uint64
+	// LoadSuccesses and LoadFailures count the calls to a loading cache's
+	// loader which returned a nil and non-nil error, respectively.
+	LoadSuccesses, LoadFailures uint64
+	// TotalLoadTime is the total time spent in calls to a loading cache's
+	// loader.
+	TotalLoadTime time.Duration
+}
+
+// Requests returns the total number of lookups, s.Hits + s.Misses.
+func (s Stats) Requests() uint64 {
+	return s.Hits + s.Misses
+}
+
+// HitRate returns the fraction of lookups which were hits, or 1 if there
+// have been no lookups.
+func (s Stats) HitRate() float64 {
+	if s.Requests() == 0 {
+		return 1
+	}
+	return float64(s.Hits) / float64(s.Requests())
+}
+
+// AverageLoadTime returns the mean time spent in each call to a loading
+// cache's loader, or 0 if there have been no loads.
+func (s Stats) AverageLoadTime() time.Duration {
+	loads := s.LoadSuccesses + s.LoadFailures
+	if loads == 0 {
+		return 0
+	}
+	return s.TotalLoadTime / time.Duration(loads)
+}
+
+// statsOf returns the Stats of m if it reports them, like a Cache or a
+// decorator of one, or zero Stats otherwise.
+func statsOf[K, V any](m kvmap.Interface[K, V]) Stats {
+	if s, ok := m.(interface{ Stats() Stats }); ok {
+		return s.Stats()
+	}
+	return Stats{}
+}
+
+// statsCounter accumulates Stats using atomic counters, so that it can be
+// updated without holding a cache's exclusive lock.
+type statsCounter struct {
+	hits, misses, evictions     atomic.Uint64
+	expirations                 atomic.Uint64
+	loadSuccesses, loadFailures atomic.Uint64
+	loadTime                    atomic.Int64
+}
+
+func (s *statsCounter) recordLookup(hit bool) {
+	if hit {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+}
+
+func (s *statsCounter) recordLoad(d time.Duration, err error) {
+	if err == nil {
+		s.loadSuccesses.Add(1)
+	} else {
+		s.loadFailures.Add(1)
+	}
+	s.loadTime.Add(int64(d))
+}
+
+func (s *statsCounter) snapshot() Stats {
+	return Stats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Evictions:     s.evictions.Load(),
+		Expirations:   s.expirations.Load(),
+		LoadSuccesses: s.loadSuccesses.Load(),
+		LoadFailures:  s.loadFailures.Load(),
+		TotalLoadTime: time.Duration(s.loadTime.Load()),
+	}
+}
@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+// Store is a backing store, such as a database, which a write-through or
+// write-behind cache propagates its writes to.
+type Store[K, V any] interface {
+	Store(key K, val V) error
+	Remove(key K) error
+}
+
+// WriteThrough is a kvmap.Interface decorator which propagates each Put and
+// Delete to a Store before applying it to the Base map. If the Store returns
+// an error, the Base map is not modified and the error is reported via the
+// OnWriteError callback. WriteThrough is safe for concurrent use if Base and
+// the Store are.
+type WriteThrough[K, V any] struct {
+	Base kvmap.Interface[K, V]
+
+	store   Store[K, V]
+	onError func(K, error)
+}
+
+// NewWriteThrough returns a pointer to a new WriteThrough wrapping base and
+// writing to store. NewWriteThrough supports the OnWriteError() Option; other
+// Options are ignored.
+func NewWriteThrough[K, V any](base kvmap.Interface[K, V], store Store[K, V], opts ...Option) *WriteThrough[K, V] {
+	o, _ := initCacheOptions[K, V](opts)
+	return &WriteThrough[K, V]{Base: base, store: store, onError: initOnWriteError[K](o)}
+}
+
+func (c *WriteThrough[K, V]) Put(key K, val V) {
+	if err := c.store.Store(key, val); err != nil {
+		c.onError(key, err)
+		return
+	}
+	c.Base.Put(key, val)
+}
+
+func (c *WriteThrough[K, V]) Delete(key K) {
+	if err := c.store.Remove(key); err != nil {
+		c.onError(key, err)
+		return
+	}
+	c.Base.Delete(key)
+}
+
+func (c *WriteThrough[K, V]) Get(key K) (V, bool) {
+	return c.Base.Get(key)
+}
+
+func (c *WriteThrough[K, V]) Has(key K) bool {
+	return c.Base.Has(key)
+}
+
+func (c *WriteThrough[K, V]) Len() int {
+	return c.Base.Len()
+}
+
+// Stats returns the Stats of Base, or zero Stats if Base doesn't report
+// them.
+func (c *WriteThrough[K, V]) Stats() Stats {
+	return statsOf(c.Base)
+}
+
+// write is a queued write to a Store.
+type write[K, V any] struct {
+	key    K
+	val    V
+	remove bool
+}
+
+const defaultBatchSize = 100
+const defaultFlushInterval = time.Second
+
+// WriteBehind is a kvmap.Interface decorator which applies each Put and Delete
+// to the Base map immediately, and queues it to be written to a Store
+// asynchronously. Queued writes are flushed in order, in batches, when
+// BatchSize() writes are queued or FlushInterval() has elapsed, whichever is
+// first. Errors from the Store are reported via the OnWriteError callback.
+//
+// WriteBehind is safe for concurrent use if Base is. A WriteBehind must be
+// closed with Close when it is no longer used, and must not be used after.
+type WriteBehind[K, V any] struct {
+	Base kvmap.Interface[K, V]
+
+	store     Store[K, V]
+	onError   func(K, error)
+	onFlush   func(int)
+	batchSize int
+
+	mu      sync.Mutex
+	pending []write[K, V]
+
+	kick     chan struct{}
+	flushReq chan chan struct{}
+	closed   chan struct{}
+	stopped  chan struct{}
+}
+
+// NewWriteBehind returns a pointer to a new WriteBehind wrapping base and
+// writing to store, and starts its flushing goroutine. NewWriteBehind supports
+// the BatchSize() (default: 100), FlushInterval() (default: 1s),
+// OnWriteError() and OnFlush() Options; other Options are ignored.
+func NewWriteBehind[K, V any](base kvmap.Interface[K, V], store Store[K, V], opts ...Option) *WriteBehind[K, V] {
+	o, _ := initCacheOptions[K, V](opts)
+	if o.batchSize == 0 {
+		o.batchSize = defaultBatchSize
+	}
+	if o.flushInterval == 0 {
+		o.flushInterval = defaultFlushInterval
+	}
+	c := &WriteBehind[K, V]{
+		Base:      base,
+		store:     store,
+		onError:   initOnWriteError[K](o),
+		onFlush:   o.onFlush,
+		batchSize: o.batchSize,
+		kick:      make(chan struct{}, 1),
+		flushReq:  make(chan chan struct{}),
+		closed:    make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go c.run(o.flushInterval)
+	return c
+}
+
+func (c *WriteBehind[K, V]) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.kick:
+			c.flush()
+		case done := <-c.flushReq:
+			c.flush()
+			close(done)
+		case <-c.closed:
+			c.flush()
+			close(c.stopped)
+			return
+		}
+	}
+}
+
+// flush writes all pending writes to the Store. It is only called by run, so
+// batches reach the Store in order.
+func (c *WriteBehind[K, V]) flush() {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+	for _, w := range batch {
+		var err error
+		if w.remove {
+			err = c.store.Remove(w.key)
+		} else {
+			err = c.store.Store(w.key, w.val)
+		}
+		if err != nil {
+			c.onError(w.key, err)
+		}
+	}
+	if c.onFlush != nil {
+		c.onFlush(len(batch))
+	}
+}
+
+func (c *WriteBehind[K, V]) enqueue(w write[K, V]) {
+	c.mu.Lock()
+	c.pending = append(c.pending, w)
+	full := len(c.pending) >= c.batchSize
+	c.mu.Unlock()
+
+	if full {
+		select {
+		case c.kick <- struct{}{}:
+		default:
+			// A flush is already pending.
+		}
+	}
+}
+
+func (c *WriteBehind[K, V]) Put(key K, val V) {
+	c.Base.Put(key, val)
+	c.enqueue(write[K, V]{key: key, val: val})
+}
+
+func (c *WriteBehind[K, V]) Delete(key K) {
+	c.Base.Delete(key)
+	c.enqueue(write[K, V]{key: key, remove: true})
+}
+
+func (c *WriteBehind[K, V]) Get(key K) (V, bool) {
+	return c.Base.Get(key)
+}
+
+func (c *WriteBehind[K, V]) Has(key K) bool {
+	return c.Base.Has(key)
+}
+
+func (c *WriteBehind[K, V]) Len() int {
+	return c.Base.Len()
+}
+
+// Stats returns the Stats of Base, or zero Stats if Base doesn't report
+// them.
+func (c *WriteBehind[K, V]) Stats() Stats {
+	return statsOf(c.Base)
+}
+
+// Flush blocks until all writes queued before the call have been written to
+// the Store.
+func (c *WriteBehind[K, V]) Flush() {
+	done := make(chan struct{})
+	c.flushReq <- done
+	<-done
+}
+
+// Close flushes all queued writes and stops the flushing goroutine.
+func (c *WriteBehind[K, V]) Close() {
+	close(c.closed)
+	<-c.stopped
+}
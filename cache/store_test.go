@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+type testStore struct {
+	mu     sync.Mutex
+	data   map[string]int
+	writes int
+	fail   string
+}
+
+var errStoreFailed = errors.New("store failed")
+
+func (s *testStore) Store(key string, val int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key == s.fail {
+		return errStoreFailed
+	}
+	s.data[key] = val
+	s.writes++
+	return nil
+}
+
+func (s *testStore) Remove(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	s.writes++
+	return nil
+}
+
+func TestWriteThrough(t *testing.T) {
+	store := &testStore{data: map[string]int{}, fail: "bad"}
+	var failed []string
+	c := NewWriteThrough[string, int](New[string, int](LRU[string]()), store, OnWriteError(func(k string, err error) {
+		failed = append(failed, k)
+	}))
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("bad", 3)
+	c.Delete("a")
+
+	if v, ok := store.data["b"]; !ok || v != 2 || len(store.data) != 1 {
+		t.Errorf("Want store == map[b:2], Got %v", store.data)
+	}
+	if c.Has("bad") || c.Has("a") || !c.Has("b") {
+		t.Errorf("Want cache to hold only b, Got Has(a): %t, Has(b): %t, Has(bad): %t", c.Has("a"), c.Has("b"), c.Has("bad"))
+	}
+	if len(failed) != 1 || failed[0] != "bad" {
+		t.Errorf("Want OnWriteError called for [bad], Got %v", failed)
+	}
+}
+
+func TestStoreDecoratorStats(t *testing.T) {
+	base := New[string, int](LRU[string]())
+	store := &testStore{data: map[string]int{}}
+	wb := NewWriteBehind[string, int](base, store)
+	defer wb.Close()
+	c := NewWriteThrough[string, int](wb, store)
+
+	c.Put("a", 1)
+	c.Get("a")
+	c.Get("b")
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("Want Stats of the wrapped cache with 1 hit and 1 miss, Got %+v", s)
+	}
+	if s := NewWriteThrough[string, int](kvmap.NewMapWrapper[string, int](), store).Stats(); s != (Stats{}) {
+		t.Errorf("Want zero Stats for a Base without them, Got %+v", s)
+	}
+
+	tiered := Tiered[string, int](New[string, int](LRU[string]()), base)
+	tiered.Put("c", 3)
+	tiered.Get("c")
+	if l1, l2 := tiered.TierStats(); l1.Hits != 1 || l2.Hits != 1 || l2.Misses != 1 {
+		t.Errorf("Want TierStats with a hit in L1 and the earlier lookups in L2, Got %+v and %+v", l1, l2)
+	}
+}
+
+func TestWriteBehind(t *testing.T) {
+	store := &testStore{data: map[string]int{}}
+	var mu sync.Mutex
+	flushed := 0
+	c := NewWriteBehind[string, int](&kvmap.ConcurrentWrapper[string, int]{Base: kvmap.NewMapWrapper[string, int]()}, store,
+		BatchSize(1000), OnFlush(func(n int) {
+			mu.Lock()
+			flushed += n
+			mu.Unlock()
+		}))
+	defer c.Close()
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("a", 3)
+	c.Delete("b")
+	if v, ok := c.Get("a"); !ok || v != 3 || c.Has("b") {
+		t.Errorf("Want cache updated immediately, Got Get(a) == (%d, %t), Has(b) == %t", v, ok, c.Has("b"))
+	}
+
+	c.Flush()
+	store.mu.Lock()
+	if v, ok := store.data["a"]; !ok || v != 3 || len(store.data) != 1 || store.writes != 4 {
+		t.Errorf("Want store == map[a:3] after 4 writes, Got %v after %d writes", store.data, store.writes)
+	}
+	store.mu.Unlock()
+	mu.Lock()
+	if flushed != 4 {
+		t.Errorf("Want OnFlush to report 4 writes, Got %d", flushed)
+	}
+	mu.Unlock()
+}
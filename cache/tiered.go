@@ -0,0 +1,56 @@
+package cache
+
+import "github.org/jccarlson/collections/kvmap"
+
+// TieredCache composes two caches, typically a small, fast L1 cache in front
+// of a larger or shared L2 cache. Reads check L1 and then L2, promoting L2
+// hits into L1. Writes and deletes go to both tiers. TieredCache is safe for
+// concurrent use if both tiers are.
+//
+// TieredCache has no Stats method, as a Get which misses L1 and hits L2
+// counts as a lookup in both; TierStats reports the Stats of each tier.
+type TieredCache[K, V any] struct {
+	L1, L2 kvmap.Interface[K, V]
+}
+
+// Tiered returns a pointer to a new TieredCache with the given tiers. Any
+// kvmap.Interface can be used as a tier, including other TieredCaches.
+func Tiered[K, V any](l1, l2 kvmap.Interface[K, V]) *TieredCache[K, V] {
+	return &TieredCache[K, V]{L1: l1, L2: l2}
+}
+
+func (c *TieredCache[K, V]) Get(key K) (val V, ok bool) {
+	if val, ok = c.L1.Get(key); ok {
+		return val, true
+	}
+	if val, ok = c.L2.Get(key); ok {
+		c.L1.Put(key, val)
+	}
+	return val, ok
+}
+
+func (c *TieredCache[K, V]) Put(key K, val V) {
+	c.L2.Put(key, val)
+	c.L1.Put(key, val)
+}
+
+func (c *TieredCache[K, V]) Delete(key K) {
+	c.L1.Delete(key)
+	c.L2.Delete(key)
+}
+
+func (c *TieredCache[K, V]) Has(key K) bool {
+	return c.L1.Has(key) || c.L2.Has(key)
+}
+
+// Len returns the length of L2, which every write goes through. Entries
+// which L2 has evicted but L1 still holds are not counted.
+func (c *TieredCache[K, V]) Len() int {
+	return c.L2.Len()
+}
+
+// TierStats returns the Stats of L1 and L2. A tier which doesn't report Stats
+// has zero Stats.
+func (c *TieredCache[K, V]) TierStats() (l1, l2 Stats) {
+	return statsOf(c.L1), statsOf(c.L2)
+}
@@ -0,0 +1,149 @@
+package collections
+
+import "iter"
+
+// CircularNode is an element of a CircularList.
+type CircularNode[E any] struct {
+	// Value is the element stored in the node.
+	Value E
+
+	next, prev *CircularNode[E]
+	list       *CircularList[E]
+}
+
+// Next returns the node after n, wrapping around from the last node of the
+// list to the first, or nil if n has been removed from its list.
+func (n *CircularNode[E]) Next() *CircularNode[E] {
+	return n.next
+}
+
+// Prev returns the node before n, wrapping around from the first node of the
+// list to the last, or nil if n has been removed from its list.
+func (n *CircularNode[E]) Prev() *CircularNode[E] {
+	return n.prev
+}
+
+// CircularList is a doubly-linked list whose last node links back to its
+// first. Its head can be rotated in either direction, which makes it suited
+// to round-robin scheduling. The zero value is an empty CircularList ready to
+// use.
+type CircularList[E any] struct {
+	head *CircularNode[E]
+	size int
+}
+
+// insertBefore inserts a node holding e before at, or as the only node if the
+// list is empty, and returns it.
+func (l *CircularList[E]) insertBefore(e E, at *CircularNode[E]) *CircularNode[E] {
+	n := &CircularNode[E]{Value: e, list: l}
+	if at == nil {
+		n.next, n.prev = n, n
+		l.head = n
+	} else {
+		n.next, n.prev = at, at.prev
+		at.prev.next = n
+		at.prev = n
+	}
+	l.size++
+	return n
+}
+
+// PushBack inserts e as the last node of l, just before the head, and returns
+// its node.
+func (l *CircularList[E]) PushBack(e E) *CircularNode[E] {
+	return l.insertBefore(e, l.head)
+}
+
+// PushFront inserts e as the new head of l and returns its node.
+func (l *CircularList[E]) PushFront(e E) *CircularNode[E] {
+	n := l.insertBefore(e, l.head)
+	l.head = n
+	return n
+}
+
+// Front returns the head of l, or nil if l is empty.
+func (l *CircularList[E]) Front() *CircularNode[E] {
+	return l.head
+}
+
+// Rotate moves the head of l forward by n nodes, or backward if n is
+// negative.
+func (l *CircularList[E]) Rotate(n int) {
+	if l.size == 0 {
+		return
+	}
+	n %= l.size
+	for ; n > 0; n-- {
+		l.head = l.head.next
+	}
+	for ; n < 0; n++ {
+		l.head = l.head.prev
+	}
+}
+
+// Remove removes n from l and returns its value. If n is the head, the node
+// after it becomes the head. It panics if n is not in l.
+func (l *CircularList[E]) Remove(n *CircularNode[E]) E {
+	if n.list != l {
+		panic("CircularList.Remove called with a node not in the list")
+	}
+	if l.size == 1 {
+		l.head = nil
+	} else {
+		if l.head == n {
+			l.head = n.next
+		}
+		n.prev.next = n.next
+		n.next.prev = n.prev
+	}
+	n.next, n.prev, n.list = nil, nil, nil
+	l.size--
+	return n.Value
+}
+
+// Len returns the number of nodes in l.
+func (l *CircularList[E]) Len() int {
+	return l.size
+}
+
+// Nodes returns an iter.Seq over the nodes of l, making one lap starting at
+// the head. The node most recently yielded may be removed during iteration;
+// other modifications have undefined results.
+func (l *CircularList[E]) Nodes() iter.Seq[*CircularNode[E]] {
+	return func(yield func(*CircularNode[E]) bool) {
+		n := l.head
+		for i, size := 0, l.size; i < size && l.size > 0; i++ {
+			next := n.next
+			if !yield(n) {
+				return
+			}
+			n = next
+		}
+	}
+}
+
+// Cycle is like Nodes, but goes around l endlessly, stopping only when the
+// caller stops iterating or every node has been removed.
+func (l *CircularList[E]) Cycle() iter.Seq[*CircularNode[E]] {
+	return func(yield func(*CircularNode[E]) bool) {
+		for n := l.head; l.size > 0; {
+			next := n.next
+			if !yield(n) {
+				return
+			}
+			n = next
+		}
+	}
+}
+
+// All returns an iter.Seq over the values of l, making one lap starting at
+// the head.
+func (l *CircularList[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for n := range l.Nodes() {
+			if !yield(n.Value) {
+				return
+			}
+		}
+	}
+}
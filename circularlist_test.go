@@ -0,0 +1,71 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCircularList(t *testing.T) {
+	var l CircularList[int]
+	for i := 1; i <= 4; i++ {
+		l.PushBack(i)
+	}
+	l.PushFront(0)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Want [0 1 2 3 4], Got %v", got)
+	}
+
+	l.Rotate(2)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{2, 3, 4, 0, 1}) {
+		t.Errorf("Want [2 3 4 0 1] after Rotate(2), Got %v", got)
+	}
+	l.Rotate(-8)
+	if got := l.Front().Value; got != 4 {
+		t.Errorf("Want Front() == 4 after Rotate(-8), Got %d", got)
+	}
+	if got := l.Front().Prev().Value; got != 3 {
+		t.Errorf("Want Front().Prev() == 3, Got %d", got)
+	}
+
+	for n := range l.Nodes() {
+		if n.Value%2 == 0 {
+			l.Remove(n)
+		}
+	}
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Want [1 3] after removing even values, Got %v", got)
+	}
+}
+
+func TestCircularListJosephus(t *testing.T) {
+	// 7 people, every 3rd is eliminated: the order is 3 6 2 7 5 1 4.
+	var l CircularList[int]
+	for i := 1; i <= 7; i++ {
+		l.PushBack(i)
+	}
+	var order []int
+	count := 0
+	for n := range l.Cycle() {
+		if count++; count%3 == 0 {
+			order = append(order, l.Remove(n))
+		}
+	}
+	if want := []int{3, 6, 2, 7, 5, 1, 4}; !slices.Equal(order, want) {
+		t.Errorf("Want elimination order %v, Got %v", want, order)
+	}
+	if l.Len() != 0 || l.Front() != nil {
+		t.Errorf("Want empty list, Got Len() == %d", l.Len())
+	}
+}
+
+func TestCircularListRemoveForeignNodePanics(t *testing.T) {
+	var l1, l2 CircularList[int]
+	n := l1.PushBack(1)
+	l2.PushBack(1)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want Remove of a foreign node to panic, Got no panic")
+		}
+	}()
+	l2.Remove(n)
+}
@@ -0,0 +1,82 @@
+package collections
+
+// Cloner is implemented by types which can copy themselves, such that the copy
+// shares no mutable state with the original. DeepClone and the DeepClone
+// functions use it to copy elements when no clone function is given.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// DeepClone returns a copy of e made by clone, or if clone is nil, by e's
+// Clone method if E implements Cloner[E]. Otherwise it returns e itself,
+// which is only a deep copy if E holds no pointers.
+func DeepClone[E any](e E, clone func(E) E) E {
+	if clone != nil {
+		return clone(e)
+	}
+	if c, ok := any(e).(Cloner[E]); ok {
+		return c.Clone()
+	}
+	return e
+}
+
+// DeepCloneSlice returns a copy of s whose elements are copied by DeepClone
+// with clone. A nil s returns nil.
+func DeepCloneSlice[S ~[]E, E any](s S, clone func(E) E) S {
+	if s == nil {
+		return nil
+	}
+	out := make(S, len(s))
+	for i, e := range s {
+		out[i] = DeepClone(e, clone)
+	}
+	return out
+}
+
+// DeepCloneMap returns a copy of m whose values are copied by DeepClone with
+// clone. Keys are copied as they are, since a key must not be modified while
+// it is in a map anyway. A nil m returns nil.
+func DeepCloneMap[M ~map[K]V, K comparable, V any](m M, clone func(V) V) M {
+	if m == nil {
+		return nil
+	}
+	out := make(M, len(m))
+	for k, v := range m {
+		out[k] = DeepClone(v, clone)
+	}
+	return out
+}
+
+// DeepCloneDeque returns a copy of d whose elements are copied by DeepClone
+// with clone.
+func DeepCloneDeque[E any](d *Deque[E], clone func(E) E) *Deque[E] {
+	out := NewDeque[E](d.size)
+	for i := 0; i < d.size; i++ {
+		out.buf[i] = DeepClone(d.buf[d.index(i)], clone)
+	}
+	out.size = d.size
+	return out
+}
+
+// Clone returns a copy of d, copying elements which implement Cloner with
+// their Clone method, so that a Deque of Deques is copied at every level.
+func (d *Deque[E]) Clone() *Deque[E] {
+	return DeepCloneDeque(d, nil)
+}
+
+// DeepCloneLinkedList returns a copy of l whose elements are copied by
+// DeepClone with clone.
+func DeepCloneLinkedList[E any](l *LinkedList[E], clone func(E) E) *LinkedList[E] {
+	out := &LinkedList[E]{}
+	for e := range l.All() {
+		out.PushBack(DeepClone(e, clone))
+	}
+	return out
+}
+
+// Clone returns a copy of l, copying elements which implement Cloner with
+// their Clone method, so that a LinkedList of LinkedLists is copied at every
+// level.
+func (l *LinkedList[E]) Clone() *LinkedList[E] {
+	return DeepCloneLinkedList(l, nil)
+}
@@ -0,0 +1,78 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+type counter struct{ n int }
+
+func (c *counter) Clone() *counter { return &counter{c.n} }
+
+func TestDeepClone(t *testing.T) {
+	s := []*counter{{1}, {2}}
+	c := DeepCloneSlice(s, nil)
+	c[0].n = 10
+	if s[0].n != 1 {
+		t.Errorf("Want original element unchanged by Cloner copy, Got %d", s[0].n)
+	}
+
+	ints := [][]int{{1, 2}, {3}}
+	ci := DeepCloneSlice(ints, func(s []int) []int { return slices.Clone(s) })
+	ci[0][0] = 10
+	if ints[0][0] != 1 {
+		t.Errorf("Want original element unchanged by clone func copy, Got %d", ints[0][0])
+	}
+
+	m := map[string]*counter{"a": {1}}
+	cm := DeepCloneMap(m, nil)
+	cm["a"].n = 10
+	if m["a"].n != 1 {
+		t.Errorf("Want original map value unchanged, Got %d", m["a"].n)
+	}
+	if DeepCloneSlice([]int(nil), nil) != nil || DeepCloneMap(map[int]int(nil), nil) != nil {
+		t.Errorf("Want nil clones of nil slice and map, Got non-nil")
+	}
+}
+
+func TestDequeCloneNested(t *testing.T) {
+	d := NewDeque[*Deque[*counter]](0)
+	for i := 0; i < 3; i++ {
+		inner := NewDeque[*counter](0)
+		inner.AddLast(&counter{i})
+		d.AddFirst(inner)
+	}
+	c := d.Clone()
+	first, _ := c.PeekFirst()
+	e, _ := first.PeekFirst()
+	e.n = 10
+	first.AddLast(&counter{5})
+
+	orig, _ := d.PeekFirst()
+	if e, _ := orig.PeekFirst(); e.n != 2 || orig.Len() != 1 {
+		t.Errorf("Want original nested Deque [2], Got %d elements, first %d", orig.Len(), e.n)
+	}
+	if c.Len() != 3 {
+		t.Errorf("Want Len() == 3, Got %d", c.Len())
+	}
+}
+
+func TestLinkedListClone(t *testing.T) {
+	var l LinkedList[*counter]
+	for i := 0; i < 3; i++ {
+		l.PushBack(&counter{i})
+	}
+	c := l.Clone()
+	c.Front().Value().n = 10
+	c.PushBack(&counter{3})
+	if got := l.Front().Value().n; got != 0 || l.Len() != 3 {
+		t.Errorf("Want original list unchanged, Got first %d and Len() == %d", got, l.Len())
+	}
+	var got []int
+	for e := range c.All() {
+		got = append(got, e.n)
+	}
+	if want := []int{10, 1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+}
@@ -0,0 +1,79 @@
+package compare
+
+import "cmp"
+
+// Key returns an Ordering on T which orders values by the key that f projects
+// from them, using the '<' operator.
+func Key[T any, K cmp.Ordered](f func(T) K) Ordering[T] {
+	return func(t1, t2 T) bool {
+		return f(t1) < f(t2)
+	}
+}
+
+// KeyBy returns an Ordering on T which orders values by the key that f
+// projects from them, using o to order the keys.
+func KeyBy[T, K any](f func(T) K, o Ordering[K]) Ordering[T] {
+	return func(t1, t2 T) bool {
+		return o(f(t1), f(t2))
+	}
+}
+
+// Lexicographic returns an Ordering which orders values by the first of
+// orderings for which they are not equal.
+func Lexicographic[T any](orderings ...Ordering[T]) Ordering[T] {
+	return func(t1, t2 T) bool {
+		for _, o := range orderings {
+			if o(t1, t2) {
+				return true
+			}
+			if o(t2, t1) {
+				return false
+			}
+		}
+		return false
+	}
+}
+
+// OrderingBuilder builds a composite Ordering which sorts by several
+// criteria, e.g. to order people by last name, then by age, oldest first:
+//
+//	byName := compare.Key(func(p Person) string { return p.LastName })
+//	byAge := compare.Key(func(p Person) int { return p.Age })
+//	o := compare.NewOrderingBuilder[Person]().Asc(byName).Desc(byAge).Build()
+//
+// The zero value is an empty OrderingBuilder, ready to use.
+type OrderingBuilder[T any] struct {
+	orderings []Ordering[T]
+}
+
+// NewOrderingBuilder returns a pointer to a new, empty OrderingBuilder.
+func NewOrderingBuilder[T any]() *OrderingBuilder[T] {
+	return &OrderingBuilder[T]{}
+}
+
+// Asc adds o as the next criterion, and returns b.
+func (b *OrderingBuilder[T]) Asc(o Ordering[T]) *OrderingBuilder[T] {
+	b.orderings = append(b.orderings, o)
+	return b
+}
+
+// Desc adds the reverse of o as the next criterion, and returns b.
+func (b *OrderingBuilder[T]) Desc(o Ordering[T]) *OrderingBuilder[T] {
+	return b.Asc(Reverse(o))
+}
+
+// Build returns the Ordering which orders values by the criteria added to b,
+// in order. Values which are equal by every criterion are equal. Changes made
+// to b after Build do not affect the returned Ordering.
+func (b *OrderingBuilder[T]) Build() Ordering[T] {
+	return Lexicographic(append([]Ordering[T](nil), b.orderings...)...)
+}
+
+// BuildComparator returns a Comparator consistent with Build(): two values
+// are equal if they are equal by every criterion added to b.
+func (b *OrderingBuilder[T]) BuildComparator() Comparator[T] {
+	o := b.Build()
+	return func(t1, t2 T) bool {
+		return !o(t1, t2) && !o(t2, t1)
+	}
+}
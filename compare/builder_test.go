@@ -0,0 +1,57 @@
+package compare
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"testing"
+)
+
+type person struct {
+	first, last string
+	age         int
+}
+
+func TestOrderingBuilder(t *testing.T) {
+	people := []person{
+		{"ann", "smith", 30},
+		{"bob", "jones", 40},
+		{"cat", "smith", 50},
+		{"dan", "jones", 40},
+		{"eve", "adams", 20},
+	}
+
+	b := NewOrderingBuilder[person]().
+		Asc(Key(func(p person) string { return p.last })).
+		Desc(Key(func(p person) int { return p.age }))
+	less := b.Build()
+	// Added after Build, so it must not affect less.
+	b.Asc(KeyBy(func(p person) string { return p.first }, Reverse(Less[string])))
+
+	sort.SliceStable(people, func(i, j int) bool { return less(people[i], people[j]) })
+	got := ""
+	for _, p := range people {
+		got += p.first[:1]
+	}
+	if want := "ebdca"; got != want {
+		t.Errorf("Want order %q, Got %q", want, got)
+	}
+
+	eq := NewOrderingBuilder[person]().Asc(Key(func(p person) int { return p.age })).BuildComparator()
+	if !eq(people[1], people[2]) || eq(people[0], people[1]) {
+		t.Errorf("Want people compared by age only")
+	}
+}
+
+func TestCompareFunc(t *testing.T) {
+	o := CompareFunc(cmp.Compare[int])
+	if !o(1, 2) || o(2, 1) || o(2, 2) {
+		t.Errorf("Want CompareFunc(cmp.Compare) to order ints ascending")
+	}
+
+	s := []string{"b", "c", "a"}
+	slices.SortFunc(s, Reverse(Less[string]).Compare)
+	if !slices.Equal(s, []string{"c", "b", "a"}) {
+		t.Errorf(`Want SortFunc with Reverse(Less).Compare == [c b a], Got %v`, s)
+	}
+}
@@ -1,6 +1,6 @@
 package compare
 
-import "golang.org/x/exp/constraints"
+import "cmp"
 
 // An Ordering returns true if t1 comes strictly before t2.
 //
@@ -13,12 +13,36 @@ import "golang.org/x/exp/constraints"
 //     ordering purposes.
 type Ordering[T any] func(t1, t2 T) bool
 
-// Less is the standard Ordering for constraints.Ordered types, using the '<'
-// operator.
-func Less[T constraints.Ordered](t1, t2 T) bool {
+// Less is the standard Ordering for cmp.Ordered types, using the '<'
+// operator. cmp.Ordered permits the same types as constraints.Ordered from
+// golang.org/x/exp, so either constraint can be used with Less.
+func Less[T cmp.Ordered](t1, t2 T) bool {
 	return t1 < t2
 }
 
+// CompareFunc returns the Ordering equivalent to a cmp-style comparison
+// function, which returns a negative number if t1 < t2, a positive number if
+// t1 > t2 and 0 if they are equal, such as cmp.Compare or the functions
+// accepted by slices.SortFunc.
+func CompareFunc[T any](cmp func(t1, t2 T) int) Ordering[T] {
+	return func(t1, t2 T) bool {
+		return cmp(t1, t2) < 0
+	}
+}
+
+// Compare is the cmp-style comparison function equivalent to o, so that
+// Orderings can be used with the standard library, e.g.
+// slices.SortFunc(s, o.Compare).
+func (o Ordering[T]) Compare(t1, t2 T) int {
+	switch {
+	case o(t1, t2):
+		return -1
+	case o(t2, t1):
+		return 1
+	}
+	return 0
+}
+
 // Reverse returns the reverse Ordering of o.
 func Reverse[T any](o Ordering[T]) Ordering[T] {
 	return func(t1, t2 T) bool {
@@ -0,0 +1,181 @@
+package compare
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// threeWay compares two values of the same type, returning a negative number
+// if a comes before b, a positive number if b comes before a, and 0 if they
+// are equal for ordering purposes.
+type threeWay func(a, b reflect.Value) int
+
+// DeriveOrdering returns an Ordering for struct type T which compares the
+// exported fields of T lexicographically, in declaration order.
+//
+// Fields may be of boolean (false before true), integer, float (NaN before
+// all other values), or string type, or be arrays, slices or pointers to
+// supported types, or structs whose exported fields are supported. Arrays and
+// slices compare lexicographically, and nil pointers come before non-nil
+// ones. A field whose type T has a method Before(T) bool (see Orderable) is
+// compared using it. Types may be recursive, like a struct with a pointer to
+// its own type, but the values compared must not contain pointer cycles.
+//
+// A field can be controlled with the `compare` struct tag:
+//   - `compare:"-"` skips the field.
+//   - `compare:"desc"` reverses the order of the field.
+//
+// DeriveOrdering panics if T is not a struct type or contains an unsupported
+// field type. The reflection is done once, when DeriveOrdering is called.
+func DeriveOrdering[T any]() Ordering[T] {
+	c := deriveStruct[T]()
+	return func(t1, t2 T) bool {
+		return c(reflect.ValueOf(&t1).Elem(), reflect.ValueOf(&t2).Elem()) < 0
+	}
+}
+
+// DeriveComparator returns a Comparator for struct type T which is consistent
+// with DeriveOrdering[T](): two values are equal if neither comes before the
+// other. It supports the same field types and struct tags, and panics in the
+// same cases.
+func DeriveComparator[T any]() Comparator[T] {
+	c := deriveStruct[T]()
+	return func(t1, t2 T) bool {
+		return c(reflect.ValueOf(&t1).Elem(), reflect.ValueOf(&t2).Elem()) == 0
+	}
+}
+
+func deriveStruct[T any]() threeWay {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("cannot derive an Ordering for non-struct type %v", t))
+	}
+	return deriver{}.threeWay(t)
+}
+
+var boolType = reflect.TypeOf(true)
+
+// deriver caches the threeWays it derives by type, so that a recursive type
+// resolves to the threeWay being derived for it instead of being derived
+// again forever.
+type deriver map[reflect.Type]*threeWay
+
+func (d deriver) threeWay(t reflect.Type) threeWay {
+	if c, ok := d[t]; ok {
+		// *c may not be set yet if t is recursive, so look it up on each
+		// call.
+		return func(a, b reflect.Value) int { return (*c)(a, b) }
+	}
+	c := new(threeWay)
+	d[t] = c
+	*c = d.derive(t)
+	return *c
+}
+
+func (d deriver) derive(t reflect.Type) threeWay {
+	// Prefer the type's own Before method, if it has one.
+	if m, ok := t.MethodByName("Before"); ok && m.Type.NumIn() == 2 && m.Type.In(1) == t &&
+		m.Type.NumOut() == 1 && m.Type.Out(0) == boolType {
+		return func(a, b reflect.Value) int {
+			switch {
+			case m.Func.Call([]reflect.Value{a, b})[0].Bool():
+				return -1
+			case m.Func.Call([]reflect.Value{b, a})[0].Bool():
+				return 1
+			}
+			return 0
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(a, b reflect.Value) int {
+			switch x, y := a.Bool(), b.Bool(); {
+			case x == y:
+				return 0
+			case y:
+				return -1
+			}
+			return 1
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) int {
+			return cmp.Compare(a.Int(), b.Int())
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b reflect.Value) int {
+			return cmp.Compare(a.Uint(), b.Uint())
+		}
+
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) int {
+			return cmp.Compare(a.Float(), b.Float())
+		}
+
+	case reflect.String:
+		return func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		}
+
+	case reflect.Pointer:
+		elem := d.threeWay(t.Elem())
+		return func(a, b reflect.Value) int {
+			switch {
+			case a.IsNil() && b.IsNil():
+				return 0
+			case a.IsNil():
+				return -1
+			case b.IsNil():
+				return 1
+			}
+			return elem(a.Elem(), b.Elem())
+		}
+
+	case reflect.Array, reflect.Slice:
+		elem := d.threeWay(t.Elem())
+		return func(a, b reflect.Value) int {
+			for i := 0; i < a.Len() && i < b.Len(); i++ {
+				if c := elem(a.Index(i), b.Index(i)); c != 0 {
+					return c
+				}
+			}
+			return cmp.Compare(a.Len(), b.Len())
+		}
+
+	case reflect.Struct:
+		type field struct {
+			index int
+			cmp   threeWay
+			desc  bool
+		}
+		var fields []field
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("compare")
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			if tag != "" && tag != "desc" {
+				panic(fmt.Sprintf("invalid compare tag %q on field %v.%s", tag, t, f.Name))
+			}
+			fields = append(fields, field{index: i, cmp: d.threeWay(f.Type), desc: tag == "desc"})
+		}
+		return func(a, b reflect.Value) int {
+			for _, f := range fields {
+				c := f.cmp(a.Field(f.index), b.Field(f.index))
+				if f.desc {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			return 0
+		}
+	}
+	panic(fmt.Sprintf("cannot derive an Ordering for type %v", t))
+}
@@ -0,0 +1,99 @@
+package compare
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+type version struct {
+	Major, Minor int
+}
+
+type release struct {
+	Name    string `compare:"-"`
+	Version version
+	Date    time.Time `compare:"desc"`
+	Score   float64
+	Tags    []string
+	Parent  *version
+	hidden  int
+}
+
+func TestDeriveOrdering(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC) }
+	releases := []release{
+		{Name: "f", Version: version{2, 0}, Date: day(1)},
+		{Name: "e", Version: version{1, 2}, Date: day(1), Parent: &version{1, 1}},
+		{Name: "d", Version: version{1, 2}, Date: day(1), Parent: nil},
+		{Name: "c", Version: version{1, 2}, Date: day(2)},
+		{Name: "b", Version: version{1, 1}, Date: day(1), Score: 1, Tags: []string{"x", "y"}},
+		{Name: "a", Version: version{1, 1}, Date: day(1), Score: 1, Tags: []string{"x"}},
+		{Name: "g", Version: version{1, 1}, Date: day(1), Score: math.NaN()},
+	}
+	want := "gabcdef"
+
+	less := DeriveOrdering[release]()
+	sort.SliceStable(releases, func(i, j int) bool { return less(releases[i], releases[j]) })
+	got := ""
+	for _, r := range releases {
+		got += r.Name
+	}
+	if got != want {
+		t.Errorf("Want sorted order %q, Got %q", want, got)
+	}
+
+	eq := DeriveComparator[release]()
+	r1 := release{Name: "x", Version: version{1, 0}, hidden: 1}
+	r2 := release{Name: "y", Version: version{1, 0}, hidden: 2}
+	if !eq(r1, r2) || less(r1, r2) || less(r2, r1) {
+		t.Errorf("Want releases differing only in skipped and unexported fields to be equal")
+	}
+	if eq(releases[0], releases[1]) {
+		t.Errorf("Want %v != %v", releases[0], releases[1])
+	}
+}
+
+// tree is a recursive type, referring to itself through a pointer and a
+// slice.
+type tree struct {
+	Val      int
+	Next     *tree
+	Children []tree
+}
+
+func TestDeriveOrderingRecursiveType(t *testing.T) {
+	less, eq := DeriveOrdering[tree](), DeriveComparator[tree]()
+	list := func(vals ...int) tree {
+		var head *tree
+		for i := len(vals) - 1; i >= 0; i-- {
+			head = &tree{Val: vals[i], Next: head}
+		}
+		return *head
+	}
+	if !less(list(1, 2), list(1, 3)) || less(list(1, 3), list(1, 2)) {
+		t.Errorf("Want [1 2] before [1 3]")
+	}
+	if !less(list(1, 2), list(1, 2, 0)) {
+		t.Errorf("Want [1 2] before [1 2 0]")
+	}
+	a := tree{Val: 1, Children: []tree{list(2, 3), {Val: 4}}}
+	b := tree{Val: 1, Children: []tree{list(2, 3), {Val: 4}}}
+	if !eq(a, b) {
+		t.Errorf("Want equal trees to compare equal")
+	}
+	b.Children[0].Next.Val = 5
+	if eq(a, b) || !less(a, b) {
+		t.Errorf("Want a tree with a smaller descendant first")
+	}
+}
+
+func TestDeriveOrderingPanicsForUnsupportedFields(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want DeriveOrdering to panic for a map field, Got no panic")
+		}
+	}()
+	DeriveOrdering[struct{ M map[int]int }]()
+}
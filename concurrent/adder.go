@@ -0,0 +1,86 @@
+package concurrent
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// adderCell is a stripe of an Adder, padded to fill a cache line so that
+// cells updated by different CPUs don't share one.
+type adderCell struct {
+	n atomic.Int64
+	_ [56]byte
+}
+
+// Adder is a counter for sums updated by many goroutines at once, such as
+// hit counts or sizes. While updates don't contend, Add is a single atomic
+// add; once they do, the count is striped across cells, one per
+// GOMAXPROCS, and each Add updates a random cell, so concurrent Adds rarely
+// touch the same cache line. Sum is slower, adding up every cell. The zero
+// value is an Adder with a sum of 0, ready to use. An Adder must not be
+// copied after first use.
+type Adder struct {
+	base  atomic.Int64
+	cells atomic.Pointer[[]adderCell]
+}
+
+// Add adds delta to a.
+func (a *Adder) Add(delta int64) {
+	cells := a.cells.Load()
+	if cells == nil {
+		if old := a.base.Load(); a.base.CompareAndSwap(old, old+delta) {
+			return
+		}
+		// Another goroutine updated base at the same time, so start
+		// striping.
+		cells = a.stripe()
+	}
+	(*cells)[rand.Uint32()&uint32(len(*cells)-1)].n.Add(delta)
+}
+
+// stripe returns a's cells, allocating them if needed.
+func (a *Adder) stripe() *[]adderCell {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	cells := make([]adderCell, n)
+	if a.cells.CompareAndSwap(nil, &cells) {
+		return &cells
+	}
+	return a.cells.Load()
+}
+
+// Inc adds 1 to a.
+func (a *Adder) Inc() {
+	a.Add(1)
+}
+
+// Dec subtracts 1 from a.
+func (a *Adder) Dec() {
+	a.Add(-1)
+}
+
+// Sum returns the sum of a. Adds made during the call may or may not be
+// included, so while a is being updated, Sum is not an atomic snapshot.
+func (a *Adder) Sum() int64 {
+	sum := a.base.Load()
+	if cells := a.cells.Load(); cells != nil {
+		for i := range *cells {
+			sum += (*cells)[i].n.Load()
+		}
+	}
+	return sum
+}
+
+// Reset sets the sum of a to 0. Adds made during the call may or may not be
+// included in the sum afterwards.
+func (a *Adder) Reset() {
+	a.base.Store(0)
+	if cells := a.cells.Load(); cells != nil {
+		for i := range *cells {
+			(*cells)[i].n.Store(0)
+		}
+	}
+}
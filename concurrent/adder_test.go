@@ -0,0 +1,51 @@
+package concurrent
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAdder(t *testing.T) {
+	var a Adder
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				a.Inc()
+				if i%4 == 0 {
+					a.Dec()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := a.Sum(); got != 16*7500 {
+		t.Errorf("Want Sum() == %d, Got %d", 16*7500, got)
+	}
+	a.Reset()
+	a.Add(5)
+	if got := a.Sum(); got != 5 {
+		t.Errorf("Want Sum() == 5 after Reset and Add(5), Got %d", got)
+	}
+}
+
+func BenchmarkAdder(b *testing.B) {
+	var a Adder
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			a.Inc()
+		}
+	})
+}
+
+func BenchmarkAtomicInt64(b *testing.B) {
+	var n atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n.Add(1)
+		}
+	})
+}
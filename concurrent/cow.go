@@ -0,0 +1,187 @@
+package concurrent
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// COWSet is a copy-on-write set of comparable elements, for sets which are
+// read constantly but changed rarely, such as configuration. Reads load an
+// immutable snapshot with a single atomic operation and never block, while
+// each Add or Remove copies the set under a mutex and publishes the copy, so
+// writes cost O(n). The zero value is an empty COWSet ready to use. A COWSet
+// must not be copied after first use.
+type COWSet[E comparable] struct {
+	mu   sync.Mutex
+	snap atomic.Pointer[map[E]struct{}]
+}
+
+func (s *COWSet[E]) load() map[E]struct{} {
+	if p := s.snap.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// update publishes a copy of s modified by f, unless f returns false.
+func (s *COWSet[E]) update(f func(m map[E]struct{}) bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := maps.Clone(s.load())
+	if m == nil {
+		m = make(map[E]struct{})
+	}
+	if !f(m) {
+		return false
+	}
+	s.snap.Store(&m)
+	return true
+}
+
+// Add adds e to s, and returns true if e was not already in s.
+func (s *COWSet[E]) Add(e E) bool {
+	if s.Has(e) {
+		return false
+	}
+	return s.update(func(m map[E]struct{}) bool {
+		if _, ok := m[e]; ok {
+			return false
+		}
+		m[e] = struct{}{}
+		return true
+	})
+}
+
+// Remove removes e from s, and returns true if e was in s.
+func (s *COWSet[E]) Remove(e E) bool {
+	if !s.Has(e) {
+		return false
+	}
+	return s.update(func(m map[E]struct{}) bool {
+		if _, ok := m[e]; !ok {
+			return false
+		}
+		delete(m, e)
+		return true
+	})
+}
+
+// Has returns true if e is in s.
+func (s *COWSet[E]) Has(e E) bool {
+	_, ok := s.load()[e]
+	return ok
+}
+
+// Len returns the number of elements in s.
+func (s *COWSet[E]) Len() int {
+	return len(s.load())
+}
+
+// All returns an iter.Seq over the elements of s, in unspecified order. It
+// iterates over the snapshot of s taken when iteration starts, so s may be
+// modified during iteration without affecting it.
+func (s *COWSet[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for e := range s.load() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// COWList is a copy-on-write list, for lists which are read constantly but
+// changed rarely, such as subscriber lists. Reads load an immutable snapshot
+// with a single atomic operation and never block, while each modification
+// copies the list under a mutex and publishes the copy, so writes cost O(n).
+// The zero value is an empty COWList ready to use. A COWList must not be
+// copied after first use.
+type COWList[E any] struct {
+	mu   sync.Mutex
+	snap atomic.Pointer[[]E]
+}
+
+func (l *COWList[E]) load() []E {
+	if p := l.snap.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// update publishes the result of f applied to a copy of l with room for grow
+// more elements.
+func (l *COWList[E]) update(grow int, f func(s []E) []E) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := f(slices.Grow(slices.Clone(l.load()), grow))
+	l.snap.Store(&s)
+}
+
+// Append adds es to the end of l.
+func (l *COWList[E]) Append(es ...E) {
+	l.update(len(es), func(s []E) []E { return append(s, es...) })
+}
+
+// Insert inserts e at index i of l, which must be in [0, Len()].
+func (l *COWList[E]) Insert(i int, e E) {
+	l.update(1, func(s []E) []E { return slices.Insert(s, i, e) })
+}
+
+// Set replaces the element at index i of l with e.
+func (l *COWList[E]) Set(i int, e E) {
+	l.update(0, func(s []E) []E {
+		s[i] = e
+		return s
+	})
+}
+
+// RemoveAt removes the element at index i of l.
+func (l *COWList[E]) RemoveAt(i int) {
+	l.update(0, func(s []E) []E { return slices.Delete(s, i, i+1) })
+}
+
+// RemoveFunc removes every element of l for which del returns true, and
+// returns the number removed. del is called with l locked, so it must not
+// modify l.
+func (l *COWList[E]) RemoveFunc(del func(E) bool) int {
+	n := 0
+	l.update(0, func(s []E) []E {
+		before := len(s)
+		s = slices.DeleteFunc(s, del)
+		n = before - len(s)
+		return s
+	})
+	return n
+}
+
+// Get returns the element at index i of l.
+func (l *COWList[E]) Get(i int) E {
+	return l.load()[i]
+}
+
+// Len returns the number of elements in l.
+func (l *COWList[E]) Len() int {
+	return len(l.load())
+}
+
+// Snapshot returns the current elements of l. The returned slice is shared
+// with l and other readers, and must not be modified.
+func (l *COWList[E]) Snapshot() []E {
+	return slices.Clip(l.load())
+}
+
+// All returns an iter.Seq2 over the indexes and elements of l, in order. It
+// iterates over the snapshot of l taken when iteration starts, so l may be
+// modified during iteration without affecting it.
+func (l *COWList[E]) All() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i, e := range l.load() {
+			if !yield(i, e) {
+				return
+			}
+		}
+	}
+}
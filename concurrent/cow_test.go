@@ -0,0 +1,92 @@
+package concurrent
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCOWSet(t *testing.T) {
+	var s COWSet[string]
+	if !s.Add("a") || !s.Add("b") || s.Add("a") {
+		t.Errorf("Want Add to report whether the element was new")
+	}
+	snapshot := s.All()
+	if !s.Remove("a") || s.Remove("a") {
+		t.Errorf("Want Remove to report whether the element was present")
+	}
+	if s.Has("a") || !s.Has("b") || s.Len() != 1 {
+		t.Errorf("Want set {b}, Got Len() == %d", s.Len())
+	}
+	n := 0
+	for range snapshot {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("Want iteration over the set when it starts, Got %d elements", n)
+	}
+}
+
+func TestCOWList(t *testing.T) {
+	var l COWList[int]
+	l.Append(1, 2, 3)
+	snap := l.Snapshot()
+	l.Insert(0, 0)
+	l.Set(3, 30)
+	l.Append(4, 5)
+	l.RemoveAt(1)
+	if n := l.RemoveFunc(func(e int) bool { return e%2 == 1 }); n != 1 {
+		t.Errorf("Want RemoveFunc to remove 1 element, Got %d", n)
+	}
+	if got, want := l.Snapshot(), []int{0, 2, 30, 4}; !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+	if !slices.Equal(snap, []int{1, 2, 3}) {
+		t.Errorf("Want earlier snapshot unchanged, Got %v", snap)
+	}
+	if l.Get(2) != 30 || l.Len() != 4 {
+		t.Errorf("Want Get(2) == 30 and Len() == 4, Got %d and %d", l.Get(2), l.Len())
+	}
+
+	// All iterates over l as of when iteration starts, not when it is called.
+	all := l.All()
+	l.Append(6)
+	var got []int
+	for i, e := range all {
+		got = append(got, e)
+		if i == 0 {
+			l.Append(7)
+		}
+	}
+	if want := []int{0, 2, 30, 4, 6}; !slices.Equal(got, want) {
+		t.Errorf("Want All() to iterate over %v, Got %v", want, got)
+	}
+}
+
+func TestCOWListConcurrent(t *testing.T) {
+	var l COWList[int]
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Append(i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for _, e := range l.All() {
+					if e < 0 || e >= 100 {
+						t.Errorf("Want elements in [0, 100), Got %d", e)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if l.Len() != 400 {
+		t.Errorf("Want Len() == 400, Got %d", l.Len())
+	}
+}
@@ -0,0 +1,135 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// delayed is an element of a DelayQueue with its ready time, and the sequence
+// number of its Put to order elements with equal ready times.
+type delayed[E any] struct {
+	e     E
+	ready time.Time
+	seq   uint64
+}
+
+func delayedBefore[E any](d1, d2 delayed[E]) bool {
+	if c := d1.ready.Compare(d2.ready); c != 0 {
+		return c < 0
+	}
+	return d1.seq < d2.seq
+}
+
+// DelayQueue is a queue whose elements each have a ready time, and can only
+// be taken from the queue once it has passed. Elements are taken in order of
+// ready time, and in the order they were Put if their ready times are equal.
+// It is safe for concurrent use, and is the building block of retry and
+// scheduling loops: producers Put work to be done later, and consumers Take
+// it when it is due. The zero value is an empty DelayQueue ready to use. A
+// DelayQueue must not be copied after first use.
+type DelayQueue[E any] struct {
+	mu      sync.Mutex
+	heap    ds.BinaryHeap[delayed[E]]
+	nextSeq uint64
+	// changed is closed, and replaced, when the earliest ready time changes,
+	// to wake Takes waiting for it.
+	changed chan struct{}
+}
+
+// initLocked lazily initializes the zero value of q.
+func (q *DelayQueue[E]) initLocked() {
+	if q.changed == nil {
+		q.heap.Ordering = delayedBefore[E]
+		q.changed = make(chan struct{})
+	}
+}
+
+// Put adds e to q, to be ready at time ready.
+func (q *DelayQueue[E]) Put(e E, ready time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.initLocked()
+	q.heap.Push(delayed[E]{e, ready, q.nextSeq})
+	q.nextSeq++
+	if top, _ := q.heap.Peek(); top.seq == q.nextSeq-1 {
+		close(q.changed)
+		q.changed = make(chan struct{})
+	}
+}
+
+// PutAfter adds e to q, to be ready after delay d.
+func (q *DelayQueue[E]) PutAfter(e E, d time.Duration) {
+	q.Put(e, time.Now().Add(d))
+}
+
+// Poll removes and returns the earliest element of q if it is ready, without
+// blocking. ok is false if no element is ready.
+func (q *DelayQueue[E]) Poll() (e E, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok, _ = q.pollLocked()
+	return e, ok
+}
+
+// pollLocked removes and returns the earliest element of q if it is ready.
+// Otherwise it returns the time until the earliest element is ready, or -1 if
+// q is empty.
+func (q *DelayQueue[E]) pollLocked() (e E, ok bool, wait time.Duration) {
+	top, ok := q.heap.Peek()
+	if !ok {
+		return e, false, -1
+	}
+	if wait = time.Until(top.ready); wait > 0 {
+		return e, false, wait
+	}
+	q.heap.Pop()
+	return top.e, true, 0
+}
+
+// Take removes and returns the earliest element of q, waiting until q has an
+// element whose ready time has passed. If ctx is done first, Take returns
+// ctx.Err().
+func (q *DelayQueue[E]) Take(ctx context.Context) (e E, err error) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		q.mu.Lock()
+		q.initLocked()
+		e, ok, wait := q.pollLocked()
+		changed := q.changed
+		q.mu.Unlock()
+		if ok {
+			return e, nil
+		}
+
+		var ready <-chan time.Time
+		if wait >= 0 {
+			if timer == nil {
+				timer = time.NewTimer(wait)
+			} else {
+				timer.Reset(wait)
+			}
+			ready = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return e, ctx.Err()
+		case <-changed:
+		case <-ready:
+		}
+	}
+}
+
+// Len returns the number of elements in q, whether or not they are ready.
+func (q *DelayQueue[E]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.heap.Len()
+}
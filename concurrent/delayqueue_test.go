@@ -0,0 +1,65 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelayQueueOrder(t *testing.T) {
+	var q DelayQueue[string]
+	now := time.Now()
+	q.Put("c", now.Add(30*time.Millisecond))
+	q.Put("a", now.Add(-time.Second))
+	q.Put("b", now.Add(10*time.Millisecond))
+	q.Put("b2", now.Add(10*time.Millisecond))
+
+	if e, ok := q.Poll(); !ok || e != "a" {
+		t.Errorf(`Want Poll() == ("a", true), Got (%q, %t)`, e, ok)
+	}
+	if e, ok := q.Poll(); ok {
+		t.Errorf("Want Poll() to fail before b is ready, Got %q", e)
+	}
+	var got string
+	for q.Len() > 0 {
+		e, err := q.Take(context.Background())
+		if err != nil {
+			t.Fatalf("Want Take() error == nil, Got %v", err)
+		}
+		got += e
+	}
+	if got != "bb2c" {
+		t.Errorf("Want elements taken in ready order bb2c, Got %s", got)
+	}
+	if time.Since(now) < 30*time.Millisecond {
+		t.Errorf("Want Take to wait until c was ready, Got %v", time.Since(now))
+	}
+}
+
+func TestDelayQueueTakeWokenByEarlierPut(t *testing.T) {
+	var q DelayQueue[int]
+	q.PutAfter(1, time.Hour)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.PutAfter(2, 0)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if e, err := q.Take(ctx); e != 2 || err != nil {
+		t.Errorf("Want Take() == (2, nil), Got (%d, %v)", e, err)
+	}
+}
+
+func TestDelayQueueTakeCanceled(t *testing.T) {
+	var q DelayQueue[int]
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Want Take() on empty queue to return DeadlineExceeded, Got %v", err)
+	}
+	q.PutAfter(1, time.Hour)
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) || q.Len() != 1 {
+		t.Errorf("Want Take() of unready element to return DeadlineExceeded, Got %v", err)
+	}
+}
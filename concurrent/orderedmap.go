@@ -0,0 +1,262 @@
+package concurrent
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// maxSkipListLevel is the number of levels of an OrderedMap's skip list,
+// enough for 2^32 entries.
+const maxSkipListLevel = 32
+
+type skipListNode[K, V any] struct {
+	key K
+	val atomic.Pointer[V]
+
+	next []atomic.Pointer[skipListNode[K, V]]
+
+	// mu is held while linking or unlinking the node after another.
+	mu sync.Mutex
+	// marked is set once the node is being deleted, and fullyLinked once it
+	// is reachable at every level of next.
+	marked, fullyLinked atomic.Bool
+}
+
+// OrderedMap is a mapping of keys of type K to values of type V which is safe
+// for concurrent use and iterates over entries in key order. It is a lazy
+// skip list: Get and iteration take no locks, Put of an existing key swaps
+// its value atomically, and Puts of new keys and Deletes only lock the few
+// nodes adjacent to the key, so operations on different parts of the map
+// proceed in parallel.
+type OrderedMap[K, V any] struct {
+	ordering compare.Ordering[K]
+	head     *skipListNode[K, V]
+	size     atomic.Int64
+}
+
+// NewOrderedMap returns a pointer to a new, empty OrderedMap with cmp.Ordered
+// keys.
+func NewOrderedMap[K cmp.Ordered, V any]() *OrderedMap[K, V] {
+	return NewOrderedMapWithOrdering[K, V](compare.Less[K])
+}
+
+// NewOrderedMapWithOrdering returns a pointer to a new, empty OrderedMap using
+// ordering to order keys.
+func NewOrderedMapWithOrdering[K, V any](ordering compare.Ordering[K]) *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		ordering: ordering,
+		head:     &skipListNode[K, V]{next: make([]atomic.Pointer[skipListNode[K, V]], maxSkipListLevel)},
+	}
+}
+
+// find fills preds and succs with the last node before key and the first
+// node not before it at each level, and returns the highest level at which
+// succs holds key, or -1 if key is not in the list.
+func (m *OrderedMap[K, V]) find(key K, preds, succs *[maxSkipListLevel]*skipListNode[K, V]) int {
+	found := -1
+	pred := m.head
+	for l := maxSkipListLevel - 1; l >= 0; l-- {
+		curr := pred.next[l].Load()
+		for curr != nil && m.ordering(curr.key, key) {
+			pred, curr = curr, curr.next[l].Load()
+		}
+		if found == -1 && curr != nil && !m.ordering(key, curr.key) {
+			found = l
+		}
+		preds[l], succs[l] = pred, curr
+	}
+	return found
+}
+
+// ceiling returns the first node whose key is not before key, without
+// locking.
+func (m *OrderedMap[K, V]) ceiling(key K) *skipListNode[K, V] {
+	pred := m.head
+	var curr *skipListNode[K, V]
+	for l := maxSkipListLevel - 1; l >= 0; l-- {
+		curr = pred.next[l].Load()
+		for curr != nil && m.ordering(curr.key, key) {
+			pred, curr = curr, curr.next[l].Load()
+		}
+	}
+	return curr
+}
+
+// unlockPreds unlocks the distinct nodes of preds[:n]. Equal preds are
+// adjacent, since preds at higher levels are never after those below.
+func unlockPreds[K, V any](preds *[maxSkipListLevel]*skipListNode[K, V], n int) {
+	for l := 0; l < n; l++ {
+		if l == 0 || preds[l] != preds[l-1] {
+			preds[l].mu.Unlock()
+		}
+	}
+}
+
+// lockPreds locks the distinct nodes of preds[:n] and checks that each is
+// still live and followed at level l by succ(l). It returns the number of
+// levels locked, and whether they are all valid.
+func lockPreds[K, V any](preds *[maxSkipListLevel]*skipListNode[K, V], n int, succ func(l int) *skipListNode[K, V]) (locked int, valid bool) {
+	for l := 0; l < n; l++ {
+		pred := preds[l]
+		if l == 0 || pred != preds[l-1] {
+			pred.mu.Lock()
+		}
+		locked = l + 1
+		if pred.marked.Load() || pred.next[l].Load() != succ(l) {
+			return locked, false
+		}
+	}
+	return locked, true
+}
+
+// randomLevel returns the number of levels for a new node, which is n with
+// probability 2^-n.
+func randomLevel() int {
+	return min(bits.TrailingZeros64(rand.Uint64())+1, maxSkipListLevel)
+}
+
+func (m *OrderedMap[K, V]) Put(key K, val V) {
+	var preds, succs [maxSkipListLevel]*skipListNode[K, V]
+	levels := randomLevel()
+	for {
+		if found := m.find(key, &preds, &succs); found >= 0 {
+			n := succs[found]
+			if !n.marked.Load() {
+				for !n.fullyLinked.Load() {
+					// A concurrent Put is still linking n.
+					runtime.Gosched()
+				}
+				n.val.Store(&val)
+				return
+			}
+			// n is being deleted; retry once it is unlinked.
+			continue
+		}
+
+		// A new node must not be linked before a node being deleted, which
+		// would be unlinked along with it.
+		locked, valid := lockPreds(&preds, levels, func(l int) *skipListNode[K, V] {
+			if succs[l] != nil && succs[l].marked.Load() {
+				return nil
+			}
+			return succs[l]
+		})
+		if !valid {
+			unlockPreds(&preds, locked)
+			continue
+		}
+		n := &skipListNode[K, V]{key: key, next: make([]atomic.Pointer[skipListNode[K, V]], levels)}
+		n.val.Store(&val)
+		for l := 0; l < levels; l++ {
+			n.next[l].Store(succs[l])
+		}
+		for l := 0; l < levels; l++ {
+			preds[l].next[l].Store(n)
+		}
+		n.fullyLinked.Store(true)
+		unlockPreds(&preds, locked)
+		m.size.Add(1)
+		return
+	}
+}
+
+func (m *OrderedMap[K, V]) Get(key K) (val V, ok bool) {
+	n := m.ceiling(key)
+	if n == nil || m.ordering(key, n.key) || !n.fullyLinked.Load() || n.marked.Load() {
+		return val, false
+	}
+	return *n.val.Load(), true
+}
+
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *OrderedMap[K, V]) Delete(key K) {
+	var preds, succs [maxSkipListLevel]*skipListNode[K, V]
+	var victim *skipListNode[K, V]
+	for {
+		found := m.find(key, &preds, &succs)
+		if victim == nil {
+			if found < 0 {
+				return
+			}
+			n := succs[found]
+			if !n.fullyLinked.Load() || n.marked.Load() || len(n.next)-1 != found {
+				// n is not yet fully linked, or is being deleted by another
+				// goroutine. Either way, key is not in m for this Delete.
+				return
+			}
+			n.mu.Lock()
+			if n.marked.Load() {
+				n.mu.Unlock()
+				return
+			}
+			n.marked.Store(true)
+			victim = n
+		}
+
+		locked, valid := lockPreds(&preds, len(victim.next), func(int) *skipListNode[K, V] { return victim })
+		if !valid {
+			unlockPreds(&preds, locked)
+			continue
+		}
+		for l := len(victim.next) - 1; l >= 0; l-- {
+			preds[l].next[l].Store(victim.next[l].Load())
+		}
+		victim.mu.Unlock()
+		unlockPreds(&preds, locked)
+		m.size.Add(-1)
+		return
+	}
+}
+
+// Len returns the number of entries in m. While m is being modified, it may
+// not reflect every concurrent Put and Delete.
+func (m *OrderedMap[K, V]) Len() int {
+	return int(m.size.Load())
+}
+
+// walk yields the live entries from n onwards, until the first key not
+// before end if bounded is true.
+func (m *OrderedMap[K, V]) walk(n *skipListNode[K, V], end K, bounded bool, yield func(K, V) bool) {
+	for ; n != nil; n = n.next[0].Load() {
+		if bounded && !m.ordering(n.key, end) {
+			return
+		}
+		if n.marked.Load() || !n.fullyLinked.Load() {
+			continue
+		}
+		if !yield(n.key, *n.val.Load()) {
+			return
+		}
+	}
+}
+
+// All returns an iter.Seq2 over the keys and values of m in ascending key
+// order. Iteration takes no locks and is weakly consistent: it yields keys in
+// strictly ascending order, including every key in m for the whole
+// iteration, but may or may not yield keys Put or Deleted during it. m may be
+// modified during iteration.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var end K
+		m.walk(m.head.next[0].Load(), end, false, yield)
+	}
+}
+
+// Range returns an iter.Seq2 over the keys and values of m with keys in
+// [from, to), in ascending key order, with the consistency of All.
+func (m *OrderedMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.walk(m.ceiling(from), to, true, yield)
+	}
+}
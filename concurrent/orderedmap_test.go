@@ -0,0 +1,100 @@
+package concurrent
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		m.Put(k, "v")
+	}
+	m.Put(20, "twenty")
+	m.Delete(40)
+	m.Delete(45)
+
+	if v, ok := m.Get(20); !ok || v != "twenty" {
+		t.Errorf("Want Get(20) == (twenty, true), Got (%s, %t)", v, ok)
+	}
+	if m.Has(40) || m.Len() != 4 {
+		t.Errorf("Want 40 deleted leaving Len() == 4, Got Has(40) == %t, Len() == %d", m.Has(40), m.Len())
+	}
+	var keys []int
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 4 || keys[0] != 10 || keys[1] != 20 || keys[2] != 30 || keys[3] != 50 {
+		t.Errorf("Want All() keys [10 20 30 50], Got %v", keys)
+	}
+	keys = nil
+	for k := range m.Range(15, 50) {
+		keys = append(keys, k)
+	}
+	if len(keys) != 2 || keys[0] != 20 || keys[1] != 30 {
+		t.Errorf("Want Range(15, 50) keys [20 30], Got %v", keys)
+	}
+}
+
+func TestOrderedMapConcurrent(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	const goroutines, n = 8, 2000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each goroutine owns the keys congruent to g, and interleaves
+			// its writes with iterations over the whole map.
+			for i := g; i < n; i += goroutines {
+				m.Put(i, i)
+			}
+			for i := g; i < n; i += 2 * goroutines {
+				m.Delete(i)
+			}
+			last := -1
+			for k, v := range m.All() {
+				if k <= last || v != k {
+					t.Errorf("Want ascending keys with equal values, Got %d:%d after %d", k, v, last)
+				}
+				last = k
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.Len() != n/2 {
+		t.Errorf("Want Len() == %d, Got %d", n/2, m.Len())
+	}
+	for i := 0; i < n; i++ {
+		if want := i%(2*goroutines) >= goroutines; m.Has(i) != want {
+			t.Errorf("Want Has(%d) == %t, Got %t", i, want, !want)
+		}
+	}
+}
+
+func TestOrderedMapConcurrentSameKeys(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				if (i+g)%3 == 0 {
+					m.Delete(i % 16)
+				} else {
+					m.Put(i%16, g)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	n := 0
+	for range m.All() {
+		n++
+	}
+	if n != m.Len() {
+		t.Errorf("Want All() to yield Len() == %d entries, Got %d", m.Len(), n)
+	}
+}
@@ -0,0 +1,185 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"time"
+
+	"github.org/jccarlson/collections"
+)
+
+// ErrQueueClosed is returned by Take once a closed queue is empty.
+var ErrQueueClosed = errors.New("concurrent: queue closed")
+
+// RateLimitedQueue is a FIFO queue which releases elements at a limited rate,
+// by a token bucket: each element taken uses a token, tokens accrue at rate
+// per second, and up to burst tokens can be saved while the queue is idle.
+// Producers can Put freely, while consumers Take a throttled stream. It is
+// safe for concurrent use.
+type RateLimitedQueue[E any] struct {
+	mu    sync.Mutex
+	elems collections.Deque[E]
+	// rate is the number of tokens accrued per second.
+	rate  float64
+	burst float64
+	// tokens is the number of tokens held at time last.
+	tokens float64
+	last   time.Time
+	closed bool
+	// changed is closed, and replaced, when an element is Put or q is
+	// closed, to wake Takes waiting for one.
+	changed chan struct{}
+}
+
+// NewRateLimitedQueue returns a pointer to a new, empty RateLimitedQueue
+// releasing rate elements per second on average, and up to burst elements at
+// once after being idle. Its bucket starts full.
+func NewRateLimitedQueue[E any](rate float64, burst int) *RateLimitedQueue[E] {
+	if !(rate > 0) {
+		panic("RateLimitedQueue rate must be > 0")
+	}
+	if burst < 1 {
+		panic("RateLimitedQueue burst must be >= 1")
+	}
+	return &RateLimitedQueue[E]{
+		rate:    rate,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+		changed: make(chan struct{}),
+	}
+}
+
+// notifyLocked wakes waiting Takes.
+func (q *RateLimitedQueue[E]) notifyLocked() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// Put adds e to the back of q. It panics if q is closed.
+func (q *RateLimitedQueue[E]) Put(e E) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		panic("RateLimitedQueue.Put called after Close")
+	}
+	q.elems.AddLast(e)
+	q.notifyLocked()
+}
+
+// Close closes q to further Puts. Elements already in q can still be taken.
+func (q *RateLimitedQueue[E]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		q.notifyLocked()
+	}
+}
+
+// refillLocked adds the tokens accrued since q.last.
+func (q *RateLimitedQueue[E]) refillLocked(now time.Time) {
+	q.tokens = min(q.burst, q.tokens+now.Sub(q.last).Seconds()*q.rate)
+	q.last = now
+}
+
+// Take removes and returns the element at the front of q, waiting until q has
+// an element and a token to release it. If ctx is done first, Take returns
+// ctx.Err(), and once q is closed and empty, it returns ErrQueueClosed.
+func (q *RateLimitedQueue[E]) Take(ctx context.Context) (e E, err error) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		q.mu.Lock()
+		var wait time.Duration
+		if q.elems.Len() > 0 {
+			q.refillLocked(time.Now())
+			if q.tokens >= 1 {
+				q.tokens--
+				e, _ = q.elems.RemoveFirst()
+				q.mu.Unlock()
+				return e, nil
+			}
+			wait = time.Duration((1 - q.tokens) / q.rate * float64(time.Second))
+		} else if q.closed {
+			q.mu.Unlock()
+			return e, ErrQueueClosed
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		var ready <-chan time.Time
+		if wait > 0 {
+			if timer == nil {
+				timer = time.NewTimer(wait)
+			} else {
+				timer.Reset(wait)
+			}
+			ready = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return e, ctx.Err()
+		case <-changed:
+		case <-ready:
+		}
+	}
+}
+
+// All returns an iter.Seq over the elements taken from q, at q's rate, until
+// q is closed and empty or ctx is done.
+func (q *RateLimitedQueue[E]) All(ctx context.Context) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for {
+			e, err := q.Take(ctx)
+			if err != nil || !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Chan returns a channel receiving the elements taken from q, at q's rate.
+// The channel is closed once q is closed and empty or ctx is done, and ctx
+// must be done for its goroutine to exit if the receiver stops early. An
+// element taken but not yet received when ctx is done is put back at the
+// front of q, with its token, so it isn't lost.
+func (q *RateLimitedQueue[E]) Chan(ctx context.Context) <-chan E {
+	ch := make(chan E)
+	go func() {
+		defer close(ch)
+		for e := range q.All(ctx) {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				q.untake(e)
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// untake puts e, which was taken from q, back at the front of q, and refunds
+// the token used to take it.
+func (q *RateLimitedQueue[E]) untake(e E) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.refillLocked(time.Now())
+	q.tokens = min(q.burst, q.tokens+1)
+	q.elems.AddFirst(e)
+	q.notifyLocked()
+}
+
+// Len returns the number of elements waiting in q.
+func (q *RateLimitedQueue[E]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.elems.Len()
+}
@@ -0,0 +1,82 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedQueue(t *testing.T) {
+	q := NewRateLimitedQueue[int](100, 2)
+	for i := range 6 {
+		q.Put(i)
+	}
+	q.Close()
+
+	start := time.Now()
+	got := slices.Collect(q.All(context.Background()))
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Want [0 1 2 3 4 5], Got %v", got)
+	}
+	// The first 2 elements use the initial burst, and the other 4 wait 10ms
+	// each for a token.
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Errorf("Want 6 elements at 100/s with burst 2 to take >= 40ms, Got %v", d)
+	}
+	if _, err := q.Take(context.Background()); err != ErrQueueClosed {
+		t.Errorf("Want Take() on closed, empty queue to return ErrQueueClosed, Got %v", err)
+	}
+}
+
+func TestRateLimitedQueueChan(t *testing.T) {
+	q := NewRateLimitedQueue[string](1000, 1)
+	ch := q.Chan(context.Background())
+	q.Put("a")
+	q.Put("b")
+	q.Close()
+	var got []string
+	for e := range ch {
+		got = append(got, e)
+	}
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Want [a b], Got %v", got)
+	}
+}
+
+func TestRateLimitedQueueChanCanceledKeepsElement(t *testing.T) {
+	q := NewRateLimitedQueue[int](1000, 1)
+	q.Put(1)
+	q.Put(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := q.Chan(ctx)
+	// Wait until Chan has taken 1 and is blocked sending it.
+	for q.Len() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	for deadline := time.Now().Add(time.Second); q.Len() != 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("Want the channel closed once ctx is done, Got an element")
+	}
+	if e, err := q.Take(context.Background()); e != 1 || err != nil || q.Len() != 1 {
+		t.Errorf("Want the unreceived element put back at the front with Take() == (1, nil), Got (%d, %v)", e, err)
+	}
+}
+
+func TestRateLimitedQueueTakeCanceled(t *testing.T) {
+	q := NewRateLimitedQueue[int](0.001, 1)
+	q.Put(1)
+	q.Put(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if e, err := q.Take(ctx); e != 1 || err != nil {
+		t.Errorf("Want Take() == (1, nil) using the burst, Got (%d, %v)", e, err)
+	}
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) || q.Len() != 1 {
+		t.Errorf("Want Take() without a token to return DeadlineExceeded, Got %v", err)
+	}
+}
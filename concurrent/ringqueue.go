@@ -0,0 +1,179 @@
+package concurrent
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// cacheLinePad separates fields written by different goroutines, so that
+// they don't share a cache line.
+type cacheLinePad [64]byte
+
+// ringCap returns the power of 2 capacity of a ring queue holding at least
+// capacity elements.
+func ringCap(capacity int) uint64 {
+	if capacity < 1 {
+		panic("ring queue capacity must be >= 1")
+	}
+	return 1 << bits.Len(uint(capacity-1))
+}
+
+// SPSCQueue is a bounded FIFO queue for exactly one producer goroutine and
+// one consumer goroutine, backed by a ring buffer. TryPush and TryPop are
+// wait-free: each is a few loads and stores with no locks or retries, so a
+// latency-sensitive pipeline stage never blocks on the other. Only one
+// goroutine at a time may call TryPush, and only one may call TryPop.
+type SPSCQueue[E any] struct {
+	buf  []E
+	mask uint64
+	_    cacheLinePad
+
+	// head is the count of elements popped, written by the consumer.
+	head atomic.Uint64
+	// tailCache is the consumer's last read of tail.
+	tailCache uint64
+	_         cacheLinePad
+
+	// tail is the count of elements pushed, written by the producer.
+	tail atomic.Uint64
+	// headCache is the producer's last read of head.
+	headCache uint64
+	_         cacheLinePad
+}
+
+// NewSPSCQueue returns a pointer to a new, empty SPSCQueue holding at least
+// capacity elements, rounded up to a power of 2.
+func NewSPSCQueue[E any](capacity int) *SPSCQueue[E] {
+	c := ringCap(capacity)
+	return &SPSCQueue[E]{buf: make([]E, c), mask: c - 1}
+}
+
+// TryPush adds e to the back of q, and returns false if q is full. It must
+// only be called by the producer.
+func (q *SPSCQueue[E]) TryPush(e E) bool {
+	t := q.tail.Load()
+	if t-q.headCache == uint64(len(q.buf)) {
+		if q.headCache = q.head.Load(); t-q.headCache == uint64(len(q.buf)) {
+			return false
+		}
+	}
+	q.buf[t&q.mask] = e
+	q.tail.Store(t + 1)
+	return true
+}
+
+// TryPop removes and returns the element at the front of q. ok is false if q
+// is empty. It must only be called by the consumer.
+func (q *SPSCQueue[E]) TryPop() (e E, ok bool) {
+	h := q.head.Load()
+	if h == q.tailCache {
+		if q.tailCache = q.tail.Load(); h == q.tailCache {
+			return
+		}
+	}
+	var zero E
+	e, q.buf[h&q.mask] = q.buf[h&q.mask], zero
+	q.head.Store(h + 1)
+	return e, true
+}
+
+// Len returns the number of elements in q. While q is in use, it may be out
+// of date as soon as it returns.
+func (q *SPSCQueue[E]) Len() int {
+	h := q.head.Load()
+	return int(q.tail.Load() - h)
+}
+
+// Cap returns the number of elements q can hold.
+func (q *SPSCQueue[E]) Cap() int {
+	return len(q.buf)
+}
+
+// mpscCell is a slot of an MPSCQueue. seq is the push count at which the
+// slot is next free to push to, or that count plus one once it holds an
+// element ready to pop.
+type mpscCell[E any] struct {
+	seq atomic.Uint64
+	e   E
+}
+
+// MPSCQueue is a bounded FIFO queue for any number of producer goroutines and
+// one consumer goroutine, backed by a ring buffer of sequenced slots.
+// Producers claim slots with a compare-and-swap, so TryPush is lock-free,
+// retrying only when another producer claims the same slot first; TryPop is
+// wait-free. Only one goroutine at a time may call TryPop.
+type MPSCQueue[E any] struct {
+	cells []mpscCell[E]
+	mask  uint64
+	_     cacheLinePad
+
+	// tail is the count of slots claimed by producers.
+	tail atomic.Uint64
+	_    cacheLinePad
+
+	// head is the count of elements popped, written by the consumer.
+	head atomic.Uint64
+	_    cacheLinePad
+}
+
+// NewMPSCQueue returns a pointer to a new, empty MPSCQueue holding at least
+// capacity elements, rounded up to a power of 2 of at least 2.
+func NewMPSCQueue[E any](capacity int) *MPSCQueue[E] {
+	// With one slot, a pushed element's sequence number would equal the
+	// slot's next free one, so at least 2 are needed.
+	c := max(ringCap(capacity), 2)
+	q := &MPSCQueue[E]{cells: make([]mpscCell[E], c), mask: c - 1}
+	for i := range q.cells {
+		q.cells[i].seq.Store(uint64(i))
+	}
+	return q
+}
+
+// TryPush adds e to the back of q, and returns false if q is full. It may be
+// called by many goroutines at once.
+func (q *MPSCQueue[E]) TryPush(e E) bool {
+	for {
+		t := q.tail.Load()
+		c := &q.cells[t&q.mask]
+		switch s := c.seq.Load(); {
+		case s == t:
+			if q.tail.CompareAndSwap(t, t+1) {
+				c.e = e
+				c.seq.Store(t + 1)
+				return true
+			}
+		case s < t:
+			// The slot still holds the element pushed a lap ago.
+			return false
+		}
+		// Another producer claimed the slot first.
+	}
+}
+
+// TryPop removes and returns the element at the front of q. ok is false if q
+// is empty, or if the producer of the front element has claimed its slot but
+// not yet written it. It must only be called by the consumer.
+func (q *MPSCQueue[E]) TryPop() (e E, ok bool) {
+	h := q.head.Load()
+	c := &q.cells[h&q.mask]
+	if c.seq.Load() != h+1 {
+		return
+	}
+	var zero E
+	e, c.e = c.e, zero
+	c.seq.Store(h + uint64(len(q.cells)))
+	q.head.Store(h + 1)
+	return e, true
+}
+
+// Len returns the number of elements in q, counting those being pushed.
+// While q is in use, it may be out of date as soon as it returns.
+func (q *MPSCQueue[E]) Len() int {
+	h := q.head.Load()
+	return int(q.tail.Load() - h)
+}
+
+// Cap returns the number of elements q can hold.
+func (q *MPSCQueue[E]) Cap() int {
+	return len(q.cells)
+}
@@ -0,0 +1,165 @@
+package concurrent
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSPSCQueue(t *testing.T) {
+	q := NewSPSCQueue[int](3)
+	if q.Cap() != 4 {
+		t.Errorf("Want Cap() == 4, Got %d", q.Cap())
+	}
+	for i := range 4 {
+		if !q.TryPush(i) {
+			t.Errorf("Want TryPush(%d) == true, Got false", i)
+		}
+	}
+	if q.TryPush(4) || q.Len() != 4 {
+		t.Errorf("Want TryPush on full queue to fail, Got Len() == %d", q.Len())
+	}
+	if e, ok := q.TryPop(); !ok || e != 0 {
+		t.Errorf("Want TryPop() == (0, true), Got (%d, %t)", e, ok)
+	}
+
+	const n = 100000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 4; i < n; {
+			if q.TryPush(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	for want := 1; want < n; {
+		e, ok := q.TryPop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		if e != want {
+			t.Fatalf("Want TryPop() == %d, Got %d", want, e)
+		}
+		want++
+	}
+	<-done
+	if _, ok := q.TryPop(); ok {
+		t.Errorf("Want TryPop on empty queue to fail, Got ok")
+	}
+}
+
+func TestMPSCQueueCapacityOne(t *testing.T) {
+	q := NewMPSCQueue[int](1)
+	if q.Cap() != 2 {
+		t.Errorf("Want Cap() == 2, Got %d", q.Cap())
+	}
+	if !q.TryPush(1) || !q.TryPush(2) || q.TryPush(3) {
+		t.Errorf("Want 2 pushes to succeed and the third to fail")
+	}
+	for want := 1; want <= 2; want++ {
+		if e, ok := q.TryPop(); !ok || e != want {
+			t.Errorf("Want TryPop() == (%d, true), Got (%d, %t)", want, e, ok)
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("Want Len() == 0, Got %d", q.Len())
+	}
+}
+
+func TestMPSCQueue(t *testing.T) {
+	const producers, n = 4, 20000
+	q := NewMPSCQueue[int](64)
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; {
+				if q.TryPush(p*n + i) {
+					i++
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	// Elements of each producer must be popped in the order it pushed them.
+	next := make([]int, producers)
+	for got := 0; got < producers*n; {
+		e, ok := q.TryPop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		p, i := e/n, e%n
+		if i != next[p] {
+			t.Fatalf("Want element %d of producer %d, Got %d", next[p], p, i)
+		}
+		next[p]++
+		got++
+	}
+	wg.Wait()
+	if q.Len() != 0 {
+		t.Errorf("Want Len() == 0, Got %d", q.Len())
+	}
+}
+
+func BenchmarkSPSCQueue(b *testing.B) {
+	q := NewSPSCQueue[int](1024)
+	go func() {
+		for i := 0; i < b.N; {
+			if q.TryPush(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	for i := 0; i < b.N; {
+		if _, ok := q.TryPop(); ok {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+func BenchmarkMPSCQueue(b *testing.B) {
+	q := NewMPSCQueue[int](1024)
+	var pushed sync.WaitGroup
+	pushed.Add(1)
+	go func() {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for !q.TryPush(1) {
+					runtime.Gosched()
+				}
+			}
+		})
+		pushed.Done()
+	}()
+	for i := 0; i < b.N; {
+		if _, ok := q.TryPop(); ok {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+	pushed.Wait()
+}
+
+func BenchmarkChannelQueue(b *testing.B) {
+	ch := make(chan int, 1024)
+	go func() {
+		for i := 0; i < b.N; i++ {
+			ch <- i
+		}
+	}()
+	for i := 0; i < b.N; i++ {
+		<-ch
+	}
+}
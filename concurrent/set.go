@@ -0,0 +1,62 @@
+// Package concurrent provides containers which are safe for concurrent use by
+// many goroutines without serializing them through a single lock.
+package concurrent
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// Set is a set of comparable elements which is safe for concurrent use. Has
+// never blocks, and Add and Remove of different elements rarely contend, so
+// membership checks from many goroutines don't funnel through a mutex as they
+// would with a locked map. Set is built on sync.Map, and performs best when
+// elements are added once and checked many times. The zero value is an empty
+// Set ready to use. A Set must not be copied after first use.
+type Set[E comparable] struct {
+	m    sync.Map
+	size atomic.Int64
+}
+
+// Add adds e to s, and returns true if e was not already in s.
+func (s *Set[E]) Add(e E) bool {
+	if _, loaded := s.m.LoadOrStore(e, struct{}{}); loaded {
+		return false
+	}
+	s.size.Add(1)
+	return true
+}
+
+// Remove removes e from s, and returns true if e was in s.
+func (s *Set[E]) Remove(e E) bool {
+	if _, loaded := s.m.LoadAndDelete(e); !loaded {
+		return false
+	}
+	s.size.Add(-1)
+	return true
+}
+
+// Has returns true if e is in s.
+func (s *Set[E]) Has(e E) bool {
+	_, ok := s.m.Load(e)
+	return ok
+}
+
+// Len returns the number of elements in s. While s is being modified, it may
+// not reflect every concurrent Add and Remove.
+func (s *Set[E]) Len() int {
+	return int(s.size.Load())
+}
+
+// All returns an iter.Seq over the elements of s, in unspecified order.
+// Iteration is weakly consistent: it visits each element at most once, and
+// every element in s for the whole iteration, but may or may not visit
+// elements added or removed during it. s may be modified during iteration.
+func (s *Set[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		s.m.Range(func(e, _ any) bool {
+			return yield(e.(E))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package concurrent
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	var s Set[int]
+	var wg sync.WaitGroup
+	const goroutines, n = 8, 1000
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every goroutine adds every element; only one Add of each
+			// succeeds.
+			for i := 0; i < n; i++ {
+				s.Add(i)
+				if !s.Has(i) {
+					t.Errorf("Want Has(%d) == true after Add, Got false", i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if s.Len() != n {
+		t.Errorf("Want Len() == %d, Got %d", n, s.Len())
+	}
+
+	removed := make([]int, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i += 2 {
+				if s.Remove(i) {
+					removed[g]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	total := 0
+	for _, r := range removed {
+		total += r
+	}
+	if total != n/2 || s.Len() != n/2 {
+		t.Errorf("Want %d successful Removes leaving Len() == %[1]d, Got %d and %d", n/2, total, s.Len())
+	}
+
+	seen := 0
+	for e := range s.All() {
+		if e%2 == 0 {
+			t.Errorf("Want only odd elements, Got %d", e)
+		}
+		seen++
+	}
+	if seen != n/2 {
+		t.Errorf("Want All() to yield %d elements, Got %d", n/2, seen)
+	}
+	if s.Add(1) || !s.Add(0) {
+		t.Errorf("Want Add to report whether the element was new")
+	}
+}
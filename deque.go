@@ -0,0 +1,303 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+const minDequeCap = 8
+
+// Deque is a double-ended queue backed by a ring buffer whose capacity is a
+// power of 2. Elements can be added and removed at either end in amortized
+// O(1) time, and accessed by index in O(1) time. The zero value is an empty
+// Deque ready to use.
+type Deque[E any] struct {
+	buf []E
+	// head is the index in buf of the first element.
+	head int
+	// size is the number of elements in the Deque.
+	size int
+}
+
+// NewDeque returns a pointer to a new, empty Deque with room for at least
+// capacity elements before it must grow.
+func NewDeque[E any](capacity int) *Deque[E] {
+	if capacity < 0 {
+		panic("Deque capacity must be >= 0")
+	}
+	return &Deque[E]{buf: make([]E, dequeCapFor(capacity))}
+}
+
+// dequeCapFor returns the smallest valid buffer size holding n elements.
+func dequeCapFor(n int) int {
+	c := minDequeCap
+	for c < n {
+		c <<= 1
+	}
+	return c
+}
+
+// index returns the position in d.buf of the i'th element.
+func (d *Deque[E]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+// grow ensures d.buf has room for n more elements, reallocating at most once.
+func (d *Deque[E]) grow(n int) {
+	if d.size+n <= len(d.buf) {
+		return
+	}
+	buf := make([]E, dequeCapFor(d.size+n))
+	d.copyTo(buf, d.size)
+	d.buf, d.head = buf, 0
+}
+
+// copyTo copies the first n elements of d, in order, to the start of dst.
+func (d *Deque[E]) copyTo(dst []E, n int) {
+	if n == 0 {
+		return
+	}
+	if end := d.head + n; end <= len(d.buf) {
+		copy(dst, d.buf[d.head:end])
+		return
+	}
+	m := copy(dst, d.buf[d.head:])
+	copy(dst[m:], d.buf[:n-m])
+}
+
+// AddFirst inserts e at the front of d.
+func (d *Deque[E]) AddFirst(e E) {
+	d.grow(1)
+	d.head = (d.head - 1) & (len(d.buf) - 1)
+	d.buf[d.head] = e
+	d.size++
+}
+
+// AddLast inserts e at the back of d.
+func (d *Deque[E]) AddLast(e E) {
+	d.grow(1)
+	d.buf[d.index(d.size)] = e
+	d.size++
+}
+
+// RemoveFirst removes and returns the element at the front of d. ok is false
+// if d is empty.
+func (d *Deque[E]) RemoveFirst() (e E, ok bool) {
+	if d.size == 0 {
+		return
+	}
+	var zero E
+	e, d.buf[d.head] = d.buf[d.head], zero
+	d.head = d.index(1)
+	d.size--
+	return e, true
+}
+
+// RemoveLast removes and returns the element at the back of d. ok is false if
+// d is empty.
+func (d *Deque[E]) RemoveLast() (e E, ok bool) {
+	if d.size == 0 {
+		return
+	}
+	var zero E
+	i := d.index(d.size - 1)
+	e, d.buf[i] = d.buf[i], zero
+	d.size--
+	return e, true
+}
+
+// PeekFirst returns the element at the front of d without removing it. ok is
+// false if d is empty.
+func (d *Deque[E]) PeekFirst() (e E, ok bool) {
+	if d.size == 0 {
+		return
+	}
+	return d.buf[d.head], true
+}
+
+// PeekLast returns the element at the back of d without removing it. ok is
+// false if d is empty.
+func (d *Deque[E]) PeekLast() (e E, ok bool) {
+	if d.size == 0 {
+		return
+	}
+	return d.buf[d.index(d.size-1)], true
+}
+
+// At returns the i'th element of d, counting from the front. It panics if i
+// is out of range.
+func (d *Deque[E]) At(i int) E {
+	if i < 0 || i >= d.size {
+		panic("Deque index out of range")
+	}
+	return d.buf[d.index(i)]
+}
+
+// Set replaces the i'th element of d, counting from the front. It panics if
+// i is out of range.
+func (d *Deque[E]) Set(i int, e E) {
+	if i < 0 || i >= d.size {
+		panic("Deque index out of range")
+	}
+	d.buf[d.index(i)] = e
+}
+
+// Len returns the number of elements in d.
+func (d *Deque[E]) Len() int {
+	return d.size
+}
+
+// AddAllLast appends the elements of seq to the back of d, in order. d grows
+// at most once.
+func (d *Deque[E]) AddAllLast(seq iter.Seq[E]) {
+	es := slices.Collect(seq)
+	d.grow(len(es))
+	for i, e := range es {
+		d.buf[d.index(d.size+i)] = e
+	}
+	d.size += len(es)
+}
+
+// AddAllFirst inserts the elements of seq at the front of d, keeping their
+// order, so that the first element of seq becomes the first element of d. d
+// grows at most once.
+func (d *Deque[E]) AddAllFirst(seq iter.Seq[E]) {
+	es := slices.Collect(seq)
+	d.grow(len(es))
+	d.head = (d.head - len(es)) & (len(d.buf) - 1)
+	for i, e := range es {
+		d.buf[d.index(i)] = e
+	}
+	d.size += len(es)
+}
+
+// DrainTo removes up to n elements from the front of d and returns them in
+// order.
+func (d *Deque[E]) DrainTo(n int) []E {
+	n = min(n, d.size)
+	if n <= 0 {
+		return nil
+	}
+	out := make([]E, n)
+	d.copyTo(out, n)
+
+	var zero E
+	for i := 0; i < n; i++ {
+		d.buf[d.index(i)] = zero
+	}
+	d.head = d.index(n)
+	d.size -= n
+	return out
+}
+
+// RemoveIf removes every element of d for which pred returns true, keeping
+// the others in order, and returns the number removed. It makes one pass over
+// d, calling pred once per element from front to back.
+func (d *Deque[E]) RemoveIf(pred func(E) bool) int {
+	kept := 0
+	for i := 0; i < d.size; i++ {
+		e := d.buf[d.index(i)]
+		if pred(e) {
+			continue
+		}
+		if kept != i {
+			d.buf[d.index(kept)] = e
+		}
+		kept++
+	}
+
+	// Zero the vacated slots so that d doesn't retain removed elements.
+	var zero E
+	for i := kept; i < d.size; i++ {
+		d.buf[d.index(i)] = zero
+	}
+	removed := d.size - kept
+	d.size = kept
+	return removed
+}
+
+// linearize rearranges d's buffer in place so that its elements are
+// contiguous, and returns them as a slice aliasing the buffer.
+func (d *Deque[E]) linearize() []E {
+	if d.head+d.size > len(d.buf) {
+		// Rotate the buffer left by head, so the first element is at 0.
+		slices.Reverse(d.buf[:d.head])
+		slices.Reverse(d.buf[d.head:])
+		slices.Reverse(d.buf)
+		d.head = 0
+	}
+	return d.buf[d.head : d.head+d.size]
+}
+
+// Sort sorts the elements of d in place, from front to back, according to
+// ord. The sort is not guaranteed to be stable.
+func (d *Deque[E]) Sort(ord compare.Ordering[E]) {
+	slices.SortFunc(d.linearize(), ord.Compare)
+}
+
+// SortStable is like Sort, but keeps equal elements in their original order.
+func (d *Deque[E]) SortStable(ord compare.Ordering[E]) {
+	slices.SortStableFunc(d.linearize(), ord.Compare)
+}
+
+// BinarySearch searches for e in d, which must be sorted according to ord,
+// and returns the index of the first element not before e, along with
+// whether that element is equal to e (i.e. neither is before the other). If
+// e is not present, idx is where it would be inserted to keep d sorted.
+func (d *Deque[E]) BinarySearch(e E, ord compare.Ordering[E]) (idx int, found bool) {
+	lo, hi := 0, d.size
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if ord(d.buf[d.index(mid)], e) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < d.size && !ord(e, d.buf[d.index(lo)])
+}
+
+// All returns an iter.Seq over the elements of d from front to back. d must
+// not be modified during iteration.
+func (d *Deque[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iter.Seq over the elements of d from back to front. d
+// must not be modified during iteration.
+func (d *Deque[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := d.size - 1; i >= 0; i-- {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+type dequeIterator[E any] struct {
+	d *Deque[E]
+	i int
+}
+
+func (it *dequeIterator[E]) Next() (e E, ok bool) {
+	if it.i >= it.d.size {
+		return
+	}
+	e, ok = it.d.At(it.i), true
+	it.i++
+	return
+}
+
+// Iterator returns an Iterator over the elements of d from front to back.
+func (d *Deque[E]) Iterator() Iterator[E] {
+	return &dequeIterator[E]{d: d}
+}
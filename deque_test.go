@@ -0,0 +1,181 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	for i := 0; i < 10; i++ {
+		d.AddLast(i)
+		d.AddFirst(-i - 1)
+	}
+	if d.Len() != 20 {
+		t.Errorf("Want Len() == 20, Got %d", d.Len())
+	}
+	if got := slices.Collect(d.All()); got[0] != -10 || got[19] != 9 {
+		t.Errorf("Want elements from -10 to 9, Got %v", got)
+	}
+	for want := -10; want < 0; want++ {
+		if e, ok := d.RemoveFirst(); !ok || e != want {
+			t.Errorf("Want RemoveFirst() == (%d, true), Got (%d, %t)", want, e, ok)
+		}
+	}
+	for want := 9; want >= 0; want-- {
+		if e, ok := d.RemoveLast(); !ok || e != want {
+			t.Errorf("Want RemoveLast() == (%d, true), Got (%d, %t)", want, e, ok)
+		}
+	}
+	if _, ok := d.RemoveFirst(); ok {
+		t.Errorf("Want RemoveFirst() on empty Deque == (_, false), Got (_, true)")
+	}
+	if _, ok := d.PeekLast(); ok {
+		t.Errorf("Want PeekLast() on empty Deque == (_, false), Got (_, true)")
+	}
+}
+
+func TestDequeBatchOperations(t *testing.T) {
+	d := NewDeque[int](8)
+	// Move head away from 0 so batches wrap around the buffer.
+	for i := 0; i < 6; i++ {
+		d.AddLast(i)
+	}
+	d.DrainTo(5)
+
+	d.AddAllLast(slices.Values([]int{6, 7, 8, 9}))
+	d.AddAllFirst(slices.Values([]int{1, 2, 3, 4}))
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if got := slices.Collect(d.All()); !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+
+	if got := d.DrainTo(3); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Want DrainTo(3) == [1 2 3], Got %v", got)
+	}
+	if got := d.DrainTo(100); !slices.Equal(got, want[3:]) {
+		t.Errorf("Want DrainTo(100) == %v, Got %v", want[3:], got)
+	}
+	if d.Len() != 0 || d.DrainTo(1) != nil {
+		t.Errorf("Want empty Deque after draining, Got Len() == %d", d.Len())
+	}
+}
+
+func TestDequeAddAllGrowsOnce(t *testing.T) {
+	d := NewDeque[int](8)
+	d.AddAllLast(slices.Values(make([]int, 100)))
+	if len(d.buf) != 128 {
+		t.Errorf("Want buffer of 128 after adding 100, Got %d", len(d.buf))
+	}
+}
+
+func TestDequeSort(t *testing.T) {
+	type pair struct{ k, v int }
+	byKey := func(a, b pair) bool { return a.k < b.k }
+
+	for _, wrapped := range []bool{false, true} {
+		d := NewDeque[pair](8)
+		if wrapped {
+			// Leave the elements split across the end of the buffer.
+			for i := 0; i < 6; i++ {
+				d.AddLast(pair{})
+			}
+			d.DrainTo(6)
+		}
+		for i, k := range []int{3, 1, 2, 1, 3, 1} {
+			d.AddLast(pair{k, i})
+		}
+
+		d.SortStable(byKey)
+		want := []pair{{1, 1}, {1, 3}, {1, 5}, {2, 2}, {3, 0}, {3, 4}}
+		if got := slices.Collect(d.All()); !slices.Equal(got, want) {
+			t.Errorf("Want SortStable() == %v (wrapped: %t), Got %v", want, wrapped, got)
+		}
+
+		d.Sort(func(a, b pair) bool { return a.v > b.v })
+		for i := 0; i < d.Len(); i++ {
+			if got := d.At(i).v; got != 5-i {
+				t.Errorf("Want At(%d).v == %d after Sort(), Got %d", i, 5-i, got)
+			}
+		}
+	}
+}
+
+func TestDequeBinarySearch(t *testing.T) {
+	d := NewDeque[int](8)
+	for i := 0; i < 5; i++ {
+		d.AddLast(0)
+	}
+	d.DrainTo(5)
+	// Elements wrap around the end of the buffer.
+	d.AddAllLast(slices.Values([]int{10, 20, 20, 30, 40, 50}))
+
+	less := func(a, b int) bool { return a < b }
+	tcs := []struct {
+		e     int
+		idx   int
+		found bool
+	}{
+		{5, 0, false},
+		{10, 0, true},
+		{20, 1, true},
+		{25, 3, false},
+		{50, 5, true},
+		{60, 6, false},
+	}
+	for _, tc := range tcs {
+		if idx, found := d.BinarySearch(tc.e, less); idx != tc.idx || found != tc.found {
+			t.Errorf("Want BinarySearch(%d) == (%d, %t), Got (%d, %t)", tc.e, tc.idx, tc.found, idx, found)
+		}
+	}
+
+	var empty Deque[int]
+	if idx, found := empty.BinarySearch(1, less); idx != 0 || found {
+		t.Errorf("Want BinarySearch on empty Deque == (0, false), Got (%d, %t)", idx, found)
+	}
+}
+
+func TestDequeRemoveIf(t *testing.T) {
+	d := NewDeque[int](16)
+	// Start near the end of the buffer so the elements wrap around.
+	for i := 0; i < 12; i++ {
+		d.AddLast(-1)
+	}
+	d.DrainTo(12)
+	for i := 0; i < 10; i++ {
+		d.AddLast(i)
+	}
+
+	if n := d.RemoveIf(func(e int) bool { return e%3 == 0 }); n != 4 {
+		t.Errorf("Want RemoveIf to remove 4 multiples of 3, Got %d", n)
+	}
+	want := []int{1, 2, 4, 5, 7, 8}
+	if got := slices.Collect(d.All()); !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+	if n := d.RemoveIf(func(int) bool { return false }); n != 0 || d.Len() != 6 {
+		t.Errorf("Want RemoveIf of nothing to leave 6 elements, Got %d removed, Len() == %d", n, d.Len())
+	}
+}
+
+func TestDequeRemovalsClearSlots(t *testing.T) {
+	d := NewDeque[*int](8)
+	for i := 0; i < 8; i++ {
+		d.AddLast(new(int))
+	}
+	d.RemoveFirst()
+	d.RemoveLast()
+	d.DrainTo(2)
+	n := 0
+	d.RemoveIf(func(*int) bool { n++; return n%2 == 0 })
+
+	live := 0
+	for _, p := range d.buf {
+		if p != nil {
+			live++
+		}
+	}
+	if live != d.Len() {
+		t.Errorf("Want only the %d remaining elements referenced by the buffer, Got %d", d.Len(), live)
+	}
+}
@@ -0,0 +1,66 @@
+package collections
+
+import (
+	"context"
+	"time"
+)
+
+// DrainChannel receives a batch of up to max elements from ch, and returns
+// them in the order received. It returns early when maxWait has passed since
+// the call, ch is closed, or ctx is done, with the elements received so far,
+// which may be none. If maxWait is <= 0, it only receives the elements ch
+// already has buffered. It panics if max is < 1.
+func DrainChannel[E any](ctx context.Context, ch <-chan E, max int, maxWait time.Duration) []E {
+	var batch []E
+	drain(ctx, ch, max, maxWait, func(e E) { batch = append(batch, e) })
+	return batch
+}
+
+// DrainChannelInto is like DrainChannel, but appends the batch to the back of
+// d and returns the number of elements appended.
+func DrainChannelInto[E any](ctx context.Context, d *Deque[E], ch <-chan E, max int, maxWait time.Duration) int {
+	return drain(ctx, ch, max, maxWait, d.AddLast)
+}
+
+// drain passes each element of a DrainChannel batch to add, and returns the
+// size of the batch.
+func drain[E any](ctx context.Context, ch <-chan E, max int, maxWait time.Duration, add func(E)) int {
+	if max < 1 {
+		panic("DrainChannel max must be >= 1")
+	}
+	var expired <-chan time.Time
+	if maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	n := 0
+	for n < max {
+		if maxWait <= 0 {
+			select {
+			case e, ok := <-ch:
+				if !ok {
+					return n
+				}
+				add(e)
+				n++
+				continue
+			default:
+				return n
+			}
+		}
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return n
+			}
+			add(e)
+			n++
+		case <-expired:
+			return n
+		case <-ctx.Done():
+			return n
+		}
+	}
+	return n
+}
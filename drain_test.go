@@ -0,0 +1,55 @@
+package collections
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDrainChannel(t *testing.T) {
+	ch := make(chan int, 10)
+	for i := range 5 {
+		ch <- i
+	}
+	if got := DrainChannel(context.Background(), ch, 3, time.Second); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Want batch of max 3 [0 1 2], Got %v", got)
+	}
+
+	start := time.Now()
+	if got := DrainChannel(context.Background(), ch, 10, 20*time.Millisecond); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Want [3 4] after maxWait, Got %v", got)
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("Want DrainChannel to wait maxWait for a full batch, Got %v", d)
+	}
+
+	ch <- 5
+	if got := DrainChannel(context.Background(), ch, 10, 0); !slices.Equal(got, []int{5}) {
+		t.Errorf("Want only the buffered [5] with no maxWait, Got %v", got)
+	}
+
+	ch <- 6
+	close(ch)
+	if got := DrainChannel(context.Background(), ch, 10, time.Hour); !slices.Equal(got, []int{6}) {
+		t.Errorf("Want [6] before the channel closed, Got %v", got)
+	}
+}
+
+func TestDrainChannelInto(t *testing.T) {
+	ch := make(chan string)
+	go func() {
+		ch <- "a"
+		ch <- "b"
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	d := NewDeque[string](0)
+	d.AddLast("x")
+	if n := DrainChannelInto(ctx, d, ch, 10, time.Hour); n != 2 {
+		t.Errorf("Want 2 elements appended before ctx was done, Got %d", n)
+	}
+	if got := slices.Collect(d.All()); !slices.Equal(got, []string{"x", "a", "b"}) {
+		t.Errorf("Want [x a b], Got %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package collections
+
+import "iter"
+
+const minGapBufferCap = 16
+
+// GapBuffer is a sequence with a cursor, optimized for edits made near the
+// cursor. Elements are stored in a slice with a gap at the cursor, so
+// inserting and deleting at the cursor is amortized O(1), and moving the
+// cursor costs time proportional to the distance moved. The zero value is an
+// empty GapBuffer ready to use.
+type GapBuffer[E any] struct {
+	buf []E
+	// The gap occupies buf[gapStart:gapEnd]; gapStart is the cursor.
+	gapStart, gapEnd int
+}
+
+// Len returns the number of elements in b.
+func (b *GapBuffer[E]) Len() int {
+	return len(b.buf) - (b.gapEnd - b.gapStart)
+}
+
+// Cursor returns the position of b's cursor, in the range [0, b.Len()].
+func (b *GapBuffer[E]) Cursor() int {
+	return b.gapStart
+}
+
+// Seek moves b's cursor to just before the i'th element, or to the end of b
+// if i == b.Len(). It panics if i is out of range.
+func (b *GapBuffer[E]) Seek(i int) {
+	if i < 0 || i > b.Len() {
+		panic("GapBuffer cursor out of range")
+	}
+	var zero E
+	for b.gapStart > i {
+		b.gapStart--
+		b.gapEnd--
+		b.buf[b.gapEnd], b.buf[b.gapStart] = b.buf[b.gapStart], zero
+	}
+	for b.gapStart < i {
+		b.buf[b.gapStart], b.buf[b.gapEnd] = b.buf[b.gapEnd], zero
+		b.gapStart++
+		b.gapEnd++
+	}
+}
+
+// grow ensures the gap has room for n more elements.
+func (b *GapBuffer[E]) grow(n int) {
+	if b.gapEnd-b.gapStart >= n {
+		return
+	}
+	c := max(len(b.buf)*2, minGapBufferCap)
+	for c-b.Len() < n {
+		c *= 2
+	}
+	buf := make([]E, c)
+	copy(buf, b.buf[:b.gapStart])
+	tail := len(b.buf) - b.gapEnd
+	copy(buf[c-tail:], b.buf[b.gapEnd:])
+	b.buf, b.gapEnd = buf, c-tail
+}
+
+// Insert inserts es at b's cursor, leaving the cursor after them.
+func (b *GapBuffer[E]) Insert(es ...E) {
+	b.grow(len(es))
+	b.gapStart += copy(b.buf[b.gapStart:], es)
+}
+
+// DeleteBefore removes and returns the element before b's cursor, like a
+// backspace. ok is false if the cursor is at the start of b.
+func (b *GapBuffer[E]) DeleteBefore() (e E, ok bool) {
+	if b.gapStart == 0 {
+		return
+	}
+	var zero E
+	b.gapStart--
+	e, b.buf[b.gapStart] = b.buf[b.gapStart], zero
+	return e, true
+}
+
+// DeleteAfter removes and returns the element after b's cursor. ok is false
+// if the cursor is at the end of b.
+func (b *GapBuffer[E]) DeleteAfter() (e E, ok bool) {
+	if b.gapEnd == len(b.buf) {
+		return
+	}
+	var zero E
+	e, b.buf[b.gapEnd] = b.buf[b.gapEnd], zero
+	b.gapEnd++
+	return e, true
+}
+
+// At returns the i'th element of b. It panics if i is out of range.
+func (b *GapBuffer[E]) At(i int) E {
+	if i < 0 || i >= b.Len() {
+		panic("GapBuffer index out of range")
+	}
+	if i < b.gapStart {
+		return b.buf[i]
+	}
+	return b.buf[i+b.gapEnd-b.gapStart]
+}
+
+// All returns an iter.Seq over the elements of b in order. b must not be
+// modified during iteration.
+func (b *GapBuffer[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, e := range b.buf[:b.gapStart] {
+			if !yield(e) {
+				return
+			}
+		}
+		for _, e := range b.buf[b.gapEnd:] {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
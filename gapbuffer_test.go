@@ -0,0 +1,62 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGapBuffer(t *testing.T) {
+	var b GapBuffer[rune]
+	text := func() string { return string(slices.Collect(b.All())) }
+
+	b.Insert([]rune("hello world")...)
+	b.Seek(5)
+	b.Insert(',')
+	if got := text(); got != "hello, world" {
+		t.Errorf(`Want "hello, world", Got %q`, got)
+	}
+	if b.Cursor() != 6 {
+		t.Errorf("Want Cursor() == 6, Got %d", b.Cursor())
+	}
+
+	b.Seek(0)
+	if e, ok := b.DeleteBefore(); ok {
+		t.Errorf("Want DeleteBefore() at start == (_, false), Got (%q, true)", e)
+	}
+	if e, ok := b.DeleteAfter(); !ok || e != 'h' {
+		t.Errorf("Want DeleteAfter() == ('h', true), Got (%q, %t)", e, ok)
+	}
+	b.Insert('j')
+
+	b.Seek(b.Len())
+	if e, ok := b.DeleteBefore(); !ok || e != 'd' {
+		t.Errorf("Want DeleteBefore() == ('d', true), Got (%q, %t)", e, ok)
+	}
+	if _, ok := b.DeleteAfter(); ok {
+		t.Errorf("Want DeleteAfter() at end == (_, false), Got (_, true)")
+	}
+	if got := text(); got != "jello, worl" {
+		t.Errorf(`Want "jello, worl", Got %q`, got)
+	}
+	b.Seek(3)
+	for i, want := range "jello, worl" {
+		if got := b.At(i); got != want {
+			t.Errorf("Want At(%d) == %q, Got %q", i, want, got)
+		}
+	}
+}
+
+func TestGapBufferGrowth(t *testing.T) {
+	var b GapBuffer[int]
+	var want []int
+	for i := 0; i < 100; i++ {
+		// Insert each element at the middle of the buffer.
+		mid := b.Len() / 2
+		b.Seek(mid)
+		b.Insert(i)
+		want = slices.Insert(want, mid, i)
+	}
+	if got := slices.Collect(b.All()); !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+}
@@ -1,5 +1,5 @@
 module github.org/jccarlson/collections
 
-go 1.20
+go 1.23
 
 require golang.org/x/exp v0.0.0-20230321023759-10a507213a29
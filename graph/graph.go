@@ -0,0 +1,286 @@
+// Package graph provides a graph container, whose nodes and adjacency lists
+// are kept in the insertion-ordered maps of package kvmap, and traversals of
+// it as iter.Seqs.
+package graph
+
+import (
+	"iter"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+// node is a node of a Graph, with its value and the values of its edges.
+type node[N comparable, V, E any] struct {
+	value V
+	// out maps each successor of the node to the value of the edge to it. In
+	// an undirected Graph, it holds every neighbor.
+	out *kvmap.LinkedHashMap[N, E]
+	// in maps each predecessor of the node to the value of the edge from it.
+	// It is nil in an undirected Graph.
+	in *kvmap.LinkedHashMap[N, E]
+}
+
+// Graph is a directed or undirected graph of nodes of type N, each of which
+// has a value of type V, and whose edges have values of type E. V and E can
+// be struct{} if nodes or edges carry no data, or e.g. a weight. Nodes,
+// successors and predecessors iterate in the order they were added, so every
+// traversal of a Graph is deterministic. A Graph has at most one edge from a
+// node to another; self-loops are allowed.
+type Graph[N comparable, V, E any] struct {
+	directed bool
+	nodes    *kvmap.LinkedHashMap[N, *node[N, V, E]]
+	edges    int
+}
+
+// NewDirected returns a pointer to a new, empty directed Graph.
+func NewDirected[N comparable, V, E any]() *Graph[N, V, E] {
+	return &Graph[N, V, E]{directed: true, nodes: kvmap.NewComparableLinkedHashMap[N, *node[N, V, E]]()}
+}
+
+// NewUndirected returns a pointer to a new, empty undirected Graph, in which
+// an edge from one node to another is also an edge back, with the same
+// value.
+func NewUndirected[N comparable, V, E any]() *Graph[N, V, E] {
+	return &Graph[N, V, E]{nodes: kvmap.NewComparableLinkedHashMap[N, *node[N, V, E]]()}
+}
+
+// Directed returns true if g is directed.
+func (g *Graph[N, V, E]) Directed() bool {
+	return g.directed
+}
+
+// addNode returns the node n of g, adding it with a zero value if g doesn't
+// have it.
+func (g *Graph[N, V, E]) addNode(n N) *node[N, V, E] {
+	nd, ok := g.nodes.Get(n)
+	if !ok {
+		nd = &node[N, V, E]{out: kvmap.NewComparableLinkedHashMap[N, E]()}
+		if g.directed {
+			nd.in = kvmap.NewComparableLinkedHashMap[N, E]()
+		}
+		g.nodes.Put(n, nd)
+	}
+	return nd
+}
+
+// AddNode adds n to g with a zero value, and returns true if g didn't already
+// have it.
+func (g *Graph[N, V, E]) AddNode(n N) bool {
+	if g.nodes.Has(n) {
+		return false
+	}
+	g.addNode(n)
+	return true
+}
+
+// SetNode sets the value of n, adding n to g if g doesn't have it.
+func (g *Graph[N, V, E]) SetNode(n N, val V) {
+	g.addNode(n).value = val
+}
+
+// Node returns the value of n. ok is false if g doesn't have n.
+func (g *Graph[N, V, E]) Node(n N) (val V, ok bool) {
+	nd, ok := g.nodes.Get(n)
+	if !ok {
+		return
+	}
+	return nd.value, true
+}
+
+// HasNode returns true if g has n.
+func (g *Graph[N, V, E]) HasNode(n N) bool {
+	return g.nodes.Has(n)
+}
+
+// RemoveNode removes n and the edges to and from it from g, and returns true
+// if g had n.
+func (g *Graph[N, V, E]) RemoveNode(n N) bool {
+	nd, ok := g.nodes.DeleteAndGet(n)
+	if !ok {
+		return false
+	}
+	for m := range nd.out.All() {
+		if m == n {
+			continue
+		}
+		if g.directed {
+			g.mustNode(m).in.Delete(n)
+		} else {
+			g.mustNode(m).out.Delete(n)
+		}
+	}
+	g.edges -= nd.out.Len()
+	if g.directed {
+		for m := range nd.in.All() {
+			if m != n {
+				g.mustNode(m).out.Delete(n)
+				g.edges--
+			}
+		}
+	}
+	return true
+}
+
+// mustNode returns the node n of g, which must have it.
+func (g *Graph[N, V, E]) mustNode(n N) *node[N, V, E] {
+	nd, _ := g.nodes.Get(n)
+	return nd
+}
+
+// AddEdge adds an edge from one node to another with value val, adding the
+// nodes to g if g doesn't have them, or sets the value of the edge if g
+// already has it.
+func (g *Graph[N, V, E]) AddEdge(from, to N, val E) {
+	f, t := g.addNode(from), g.addNode(to)
+	if !f.out.Has(to) {
+		g.edges++
+	}
+	f.out.Put(to, val)
+	if g.directed {
+		t.in.Put(from, val)
+	} else {
+		t.out.Put(from, val)
+	}
+}
+
+// Edge returns the value of the edge from one node to another. ok is false if
+// g doesn't have the edge.
+func (g *Graph[N, V, E]) Edge(from, to N) (val E, ok bool) {
+	f, ok := g.nodes.Get(from)
+	if !ok {
+		return
+	}
+	return f.out.Get(to)
+}
+
+// HasEdge returns true if g has an edge from one node to another.
+func (g *Graph[N, V, E]) HasEdge(from, to N) bool {
+	f, ok := g.nodes.Get(from)
+	return ok && f.out.Has(to)
+}
+
+// RemoveEdge removes the edge from one node to another, and returns true if g
+// had it. The nodes remain in g.
+func (g *Graph[N, V, E]) RemoveEdge(from, to N) bool {
+	f, ok := g.nodes.Get(from)
+	if !ok || !f.out.Has(to) {
+		return false
+	}
+	f.out.Delete(to)
+	if g.directed {
+		g.mustNode(to).in.Delete(from)
+	} else {
+		g.mustNode(to).out.Delete(from)
+	}
+	g.edges--
+	return true
+}
+
+// NodeLen returns the number of nodes in g.
+func (g *Graph[N, V, E]) NodeLen() int {
+	return g.nodes.Len()
+}
+
+// EdgeLen returns the number of edges in g, counting an undirected edge once.
+func (g *Graph[N, V, E]) EdgeLen() int {
+	return g.edges
+}
+
+// Nodes returns an iter.Seq2 over the nodes of g and their values, in the
+// order they were added. g must not be modified during iteration.
+func (g *Graph[N, V, E]) Nodes() iter.Seq2[N, V] {
+	return func(yield func(N, V) bool) {
+		for n, nd := range g.nodes.All() {
+			if !yield(n, nd.value) {
+				return
+			}
+		}
+	}
+}
+
+// Edge is an edge of a Graph, with its value.
+type Edge[N, E any] struct {
+	From, To N
+	Value    E
+}
+
+// Edges returns an iter.Seq over the edges of g, grouped by the node they
+// are from in the order nodes were added. An undirected edge is yielded once,
+// from the node added first. g must not be modified during iteration.
+func (g *Graph[N, V, E]) Edges() iter.Seq[Edge[N, E]] {
+	return func(yield func(Edge[N, E]) bool) {
+		var done map[N]bool
+		if !g.directed {
+			done = make(map[N]bool, g.nodes.Len())
+		}
+		for n, nd := range g.nodes.All() {
+			for m, val := range nd.out.All() {
+				if done[m] {
+					continue
+				}
+				if !yield(Edge[N, E]{n, m, val}) {
+					return
+				}
+			}
+			if done != nil {
+				done[n] = true
+			}
+		}
+	}
+}
+
+// Successors returns an iter.Seq over the nodes which n has edges to, in the
+// order the edges were added, or over nothing if g doesn't have n. In an
+// undirected Graph, these are n's neighbors. g must not be modified during
+// iteration.
+func (g *Graph[N, V, E]) Successors(n N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if nd, ok := g.nodes.Get(n); ok {
+			for m := range nd.out.All() {
+				if !yield(m) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Predecessors returns an iter.Seq over the nodes which have edges to n, in
+// the order the edges were added, or over nothing if g doesn't have n. In an
+// undirected Graph, these are n's neighbors. g must not be modified during
+// iteration.
+func (g *Graph[N, V, E]) Predecessors(n N) iter.Seq[N] {
+	if !g.directed {
+		return g.Successors(n)
+	}
+	return func(yield func(N) bool) {
+		if nd, ok := g.nodes.Get(n); ok {
+			for m := range nd.in.All() {
+				if !yield(m) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// OutDegree returns the number of edges from n. In an undirected Graph, it
+// is the number of n's neighbors.
+func (g *Graph[N, V, E]) OutDegree(n N) int {
+	if nd, ok := g.nodes.Get(n); ok {
+		return nd.out.Len()
+	}
+	return 0
+}
+
+// InDegree returns the number of edges to n. In an undirected Graph, it is
+// the number of n's neighbors.
+func (g *Graph[N, V, E]) InDegree(n N) int {
+	if !g.directed {
+		return g.OutDegree(n)
+	}
+	if nd, ok := g.nodes.Get(n); ok {
+		return nd.in.Len()
+	}
+	return 0
+}
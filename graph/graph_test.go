@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDirectedGraph(t *testing.T) {
+	g := NewDirected[string, int, float64]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 2)
+	g.AddEdge("b", "c", 3)
+	g.AddEdge("c", "c", 4)
+	g.SetNode("d", 7)
+
+	if g.NodeLen() != 4 || g.EdgeLen() != 4 {
+		t.Errorf("Want 4 nodes and 4 edges, Got %d and %d", g.NodeLen(), g.EdgeLen())
+	}
+	if v, ok := g.Node("d"); !ok || v != 7 {
+		t.Errorf("Want Node(d) == (7, true), Got (%d, %t)", v, ok)
+	}
+	if w, ok := g.Edge("b", "c"); !ok || w != 3 {
+		t.Errorf("Want Edge(b, c) == (3, true), Got (%g, %t)", w, ok)
+	}
+	if g.HasEdge("c", "b") {
+		t.Errorf("Want no edge from c to b in a directed Graph, Got one")
+	}
+	if got := slices.Collect(g.Predecessors("c")); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("Want Predecessors(c) == [a b c], Got %v", got)
+	}
+	if g.OutDegree("a") != 2 || g.InDegree("c") != 3 {
+		t.Errorf("Want OutDegree(a) == 2 and InDegree(c) == 3, Got %d and %d", g.OutDegree("a"), g.InDegree("c"))
+	}
+
+	g.AddEdge("a", "b", 10)
+	if w, _ := g.Edge("a", "b"); w != 10 || g.EdgeLen() != 4 {
+		t.Errorf("Want AddEdge of an existing edge to set its value, Got %g and EdgeLen() == %d", w, g.EdgeLen())
+	}
+
+	if !g.RemoveNode("c") || g.EdgeLen() != 1 || g.HasEdge("a", "c") {
+		t.Errorf("Want RemoveNode(c) to remove its 3 edges, Got EdgeLen() == %d", g.EdgeLen())
+	}
+	if !g.RemoveEdge("a", "b") || g.RemoveEdge("a", "b") || g.EdgeLen() != 0 || g.InDegree("b") != 0 {
+		t.Errorf("Want RemoveEdge(a, b) to remove the last edge, Got EdgeLen() == %d", g.EdgeLen())
+	}
+}
+
+func TestUndirectedGraph(t *testing.T) {
+	g := NewUndirected[int, struct{}, string]()
+	g.AddEdge(1, 2, "x")
+	g.AddEdge(3, 1, "y")
+	g.AddEdge(2, 2, "z")
+
+	if w, ok := g.Edge(2, 1); !ok || w != "x" {
+		t.Errorf(`Want Edge(2, 1) == ("x", true), Got (%q, %t)`, w, ok)
+	}
+	var edges []Edge[int, string]
+	for e := range g.Edges() {
+		edges = append(edges, e)
+	}
+	want := []Edge[int, string]{{1, 2, "x"}, {1, 3, "y"}, {2, 2, "z"}}
+	if !slices.Equal(edges, want) || g.EdgeLen() != 3 {
+		t.Errorf("Want each edge once %v, Got %v", want, edges)
+	}
+	if !g.RemoveNode(1) || g.EdgeLen() != 1 || g.OutDegree(3) != 0 {
+		t.Errorf("Want RemoveNode(1) to leave only the self-loop, Got EdgeLen() == %d", g.EdgeLen())
+	}
+}
+
+func TestTraversals(t *testing.T) {
+	g := NewDirected[string, struct{}, struct{}]()
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}, {"b", "e"}} {
+		g.AddEdge(e[0], e[1], struct{}{})
+	}
+	g.AddNode("z")
+
+	if got := slices.Collect(g.BFS("a")); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("Want BFS(a) == [a b c d e], Got %v", got)
+	}
+	if got := slices.Collect(g.DFS("a")); !slices.Equal(got, []string{"a", "b", "d", "e", "c"}) {
+		t.Errorf("Want DFS(a) == [a b d e c], Got %v", got)
+	}
+	if got := slices.Collect(g.BFS("missing")); len(got) != 0 {
+		t.Errorf("Want BFS of a missing node to be empty, Got %v", got)
+	}
+
+	order, err := g.TopoSort()
+	if err != nil || !slices.Equal(order, []string{"a", "z", "b", "c", "d", "e"}) {
+		t.Errorf("Want TopoSort() == [a z b c d e], Got %v, %v", order, err)
+	}
+	g.AddEdge("e", "a", struct{}{})
+	if _, err := g.TopoSort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("Want TopoSort() of a cyclic Graph to return ErrCycle, Got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// CycleError is the error returned by TopoSort for a graph with a cycle.
+type CycleError[N any] struct {
+	// Cycle is the nodes of a cycle in the order of its edges: each node has
+	// an edge to the next, and the last has an edge to the first.
+	Cycle []N
+}
+
+func (e *CycleError[N]) Error() string {
+	return fmt.Sprintf("graph: cycle %v", e.Cycle)
+}
+
+// Is returns true for ErrCycle, so that errors.Is(err, ErrCycle) reports any
+// CycleError.
+func (e *CycleError[N]) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// TopoSort returns the nodes of the directed graph with the edges of edges,
+// each from the key to the value, in topological order, like
+// Graph.TopoSort. It returns a *CycleError if the edges form a cycle. Only
+// nodes with edges are sorted; to include isolated nodes, build a Graph.
+func TopoSort[N comparable](edges iter.Seq2[N, N]) ([]N, error) {
+	g := NewDirected[N, struct{}, struct{}]()
+	for from, to := range edges {
+		g.AddEdge(from, to, struct{}{})
+	}
+	return g.TopoSort()
+}
+
+// FindCycle returns the nodes of a cycle of a directed Graph g, in the order
+// of its edges, or nil if g has no cycle. A self-loop is a cycle of one node.
+// It panics if g is undirected.
+func (g *Graph[N, V, E]) FindCycle() []N {
+	if !g.directed {
+		panic("graph: FindCycle of an undirected Graph")
+	}
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[N]int, g.NodeLen())
+	// path is the current DFS path, and succs[i] the successors of path[i]
+	// yet to be visited.
+	var path []N
+	var succs [][]N
+	for root := range g.Nodes() {
+		if state[root] != unvisited {
+			continue
+		}
+		path, succs = append(path, root), append(succs, slices.Collect(g.Successors(root)))
+		state[root] = onPath
+		for len(path) > 0 {
+			top := len(path) - 1
+			if len(succs[top]) == 0 {
+				state[path[top]] = done
+				path, succs = path[:top], succs[:top]
+				continue
+			}
+			m := succs[top][0]
+			succs[top] = succs[top][1:]
+			switch state[m] {
+			case onPath:
+				return slices.Clone(path[slices.Index(path, m):])
+			case unvisited:
+				state[m] = onPath
+				path, succs = append(path, m), append(succs, slices.Collect(g.Successors(m)))
+			}
+		}
+	}
+	return nil
+}
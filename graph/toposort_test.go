@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func pairs(edges ...[2]string) func(func(string, string) bool) {
+	return func(yield func(string, string) bool) {
+		for _, e := range edges {
+			if !yield(e[0], e[1]) {
+				return
+			}
+		}
+	}
+}
+
+func TestTopoSort(t *testing.T) {
+	order, err := TopoSort(pairs([2]string{"shirt", "tie"}, [2]string{"tie", "jacket"}, [2]string{"pants", "shoes"}, [2]string{"pants", "jacket"}))
+	if want := []string{"shirt", "pants", "tie", "shoes", "jacket"}; err != nil || !slices.Equal(order, want) {
+		t.Errorf("Want %v, Got %v, %v", want, order, err)
+	}
+
+	ints, err := TopoSort(maps.All(map[int]int{1: 2}))
+	if err != nil || !slices.Equal(ints, []int{1, 2}) {
+		t.Errorf("Want [1 2], Got %v, %v", ints, err)
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	_, err := TopoSort(pairs([2]string{"a", "b"}, [2]string{"b", "c"}, [2]string{"c", "d"}, [2]string{"d", "b"}))
+	var cycleErr *CycleError[string]
+	if !errors.As(err, &cycleErr) || !errors.Is(err, ErrCycle) {
+		t.Fatalf("Want a *CycleError, Got %v", err)
+	}
+	if want := []string{"b", "c", "d"}; !slices.Equal(cycleErr.Cycle, want) {
+		t.Errorf("Want cycle %v, Got %v", want, cycleErr.Cycle)
+	}
+	if err.Error() != "graph: cycle [b c d]" {
+		t.Errorf("Want error message \"graph: cycle [b c d]\", Got %q", err)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	g := NewDirected[int, struct{}, struct{}]()
+	g.AddEdge(1, 2, struct{}{})
+	g.AddEdge(2, 3, struct{}{})
+	if c := g.FindCycle(); c != nil {
+		t.Errorf("Want no cycle, Got %v", c)
+	}
+	g.AddEdge(3, 3, struct{}{})
+	if c := g.FindCycle(); !slices.Equal(c, []int{3}) {
+		t.Errorf("Want self-loop cycle [3], Got %v", c)
+	}
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"errors"
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections"
+)
+
+// ErrCycle matches the *CycleError returned by TopoSort for a graph with a
+// cycle, with errors.Is.
+var ErrCycle = errors.New("graph: cycle")
+
+// BFS returns an iter.Seq over the nodes reachable from start by following
+// edges, in breadth-first order, starting with start itself. It yields
+// nothing if g doesn't have start. g must not be modified during iteration.
+func (g *Graph[N, V, E]) BFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+		seen := map[N]bool{start: true}
+		var queue collections.Deque[N]
+		queue.AddLast(start)
+		for n, ok := queue.RemoveFirst(); ok; n, ok = queue.RemoveFirst() {
+			if !yield(n) {
+				return
+			}
+			for m := range g.Successors(n) {
+				if !seen[m] {
+					seen[m] = true
+					queue.AddLast(m)
+				}
+			}
+		}
+	}
+}
+
+// DFS returns an iter.Seq over the nodes reachable from start by following
+// edges, in depth-first preorder, starting with start itself. It yields
+// nothing if g doesn't have start. g must not be modified during iteration.
+func (g *Graph[N, V, E]) DFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+		seen := make(map[N]bool)
+		stack := []N{start}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			if !yield(n) {
+				return
+			}
+			// Push successors in reverse, so the first is visited first.
+			base := len(stack)
+			for m := range g.Successors(n) {
+				if !seen[m] {
+					stack = append(stack, m)
+				}
+			}
+			slices.Reverse(stack[base:])
+		}
+	}
+}
+
+// TopoSort returns the nodes of a directed Graph g in topological order, in
+// which every node comes before the nodes it has edges to. The order is
+// deterministic, starting with the nodes which have no edges to them in the
+// order they were added. If g has a cycle, it returns a *CycleError holding
+// one of its cycles. It panics if g is undirected.
+func (g *Graph[N, V, E]) TopoSort() ([]N, error) {
+	if !g.directed {
+		panic("graph: TopoSort of an undirected Graph")
+	}
+	indeg := make(map[N]int, g.NodeLen())
+	var ready collections.Deque[N]
+	for n := range g.Nodes() {
+		if indeg[n] = g.InDegree(n); indeg[n] == 0 {
+			ready.AddLast(n)
+		}
+	}
+	order := make([]N, 0, g.NodeLen())
+	for n, ok := ready.RemoveFirst(); ok; n, ok = ready.RemoveFirst() {
+		order = append(order, n)
+		for m := range g.Successors(n) {
+			if indeg[m]--; indeg[m] == 0 {
+				ready.AddLast(m)
+			}
+		}
+	}
+	if len(order) < g.NodeLen() {
+		return nil, &CycleError[N]{g.FindCycle()}
+	}
+	return order, nil
+}
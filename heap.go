@@ -0,0 +1,187 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections/compare"
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// BinaryHeap is a priority queue of elements of type E. The top of the heap
+// is an element which no other element is ordered before, so with
+// compare.Less it is a min-heap.
+type BinaryHeap[E any] struct {
+	heap ds.BinaryHeap[E]
+	// handles[i] is the handle of the element at index i of heap, or nil if
+	// the element has none. handles is nil until the first handle is issued.
+	handles []*HeapHandle[E]
+}
+
+// HeapHandle refers to an element of a BinaryHeap, so that it can be updated
+// or removed after its priority changes.
+type HeapHandle[E any] struct {
+	heap *BinaryHeap[E]
+	// index is the element's index in heap, or -1 once it is removed.
+	index int
+}
+
+// Valid returns true if the element h refers to is still in its heap.
+func (h *HeapHandle[E]) Valid() bool {
+	return h.index >= 0
+}
+
+// Value returns the element h refers to. It panics if h is not Valid.
+func (h *HeapHandle[E]) Value() E {
+	if !h.Valid() {
+		panic("HeapHandle used after its element was removed")
+	}
+	return h.heap.heap.Elems()[h.index]
+}
+
+// NewBinaryHeap returns a pointer to a new, empty BinaryHeap ordered by ord.
+func NewBinaryHeap[E any](ord compare.Ordering[E]) *BinaryHeap[E] {
+	h := &BinaryHeap[E]{}
+	h.heap.Ordering = ord
+	return h
+}
+
+// NewHeapFromSlice returns a pointer to a new BinaryHeap ordered by ord and
+// holding elems, which are rearranged into heap order in place in O(n) time.
+// The heap takes ownership of elems: it uses elems as its storage without
+// copying, so the caller must not use the slice afterwards.
+func NewHeapFromSlice[E any](elems []E, ord compare.Ordering[E]) *BinaryHeap[E] {
+	h := NewBinaryHeap(ord)
+	h.heap.InitFrom(elems)
+	return h
+}
+
+func (h *BinaryHeap[E]) swapped(i, j int) {
+	h.handles[i], h.handles[j] = h.handles[j], h.handles[i]
+	if h.handles[i] != nil {
+		h.handles[i].index = i
+	}
+	if h.handles[j] != nil {
+		h.handles[j].index = j
+	}
+}
+
+// trackHandles starts tracking handles for the elements of h, if it isn't
+// already.
+func (h *BinaryHeap[E]) trackHandles() {
+	if h.handles == nil {
+		h.handles = make([]*HeapHandle[E], h.heap.Len(), h.heap.Len()+1)
+		h.heap.Swapped = h.swapped
+	}
+}
+
+// Push adds e to h, and returns a handle to it.
+func (h *BinaryHeap[E]) Push(e E) *HeapHandle[E] {
+	h.trackHandles()
+	handle := &HeapHandle[E]{heap: h, index: h.heap.Len()}
+	h.handles = append(h.handles, handle)
+	h.heap.Push(e)
+	return handle
+}
+
+// PushAll adds the elements of seq to h. If that is cheaper than pushing
+// each element, h is rebuilt in O(n) time instead. No handles are issued for
+// the added elements.
+func (h *BinaryHeap[E]) PushAll(seq iter.Seq[E]) {
+	es := slices.Collect(seq)
+	if h.handles != nil {
+		h.handles = append(h.handles, make([]*HeapHandle[E], len(es))...)
+	}
+	h.heap.PushAll(es)
+}
+
+// Meld moves all the elements of other into h, leaving other empty. Handles
+// to elements of other remain valid, and now refer to elements of h. Like
+// PushAll, Meld rebuilds h in O(n+m) time when that is cheaper.
+func (h *BinaryHeap[E]) Meld(other *BinaryHeap[E]) {
+	if other == h {
+		panic("BinaryHeap cannot be melded with itself")
+	}
+	if other.handles != nil {
+		h.trackHandles()
+		base := h.heap.Len()
+		for i, handle := range other.handles {
+			if handle != nil {
+				handle.heap, handle.index = h, base+i
+			}
+		}
+		h.handles = append(h.handles, other.handles...)
+		clear(other.handles)
+		other.handles = other.handles[:0]
+	} else if h.handles != nil {
+		h.handles = append(h.handles, make([]*HeapHandle[E], other.heap.Len())...)
+	}
+	h.heap.PushAll(other.heap.Elems())
+	other.heap.Clear()
+}
+
+// removeAt removes and returns the element at index i of h, invalidating its
+// handle, if any.
+func (h *BinaryHeap[E]) removeAt(i int) E {
+	e := h.heap.Remove(i)
+	if h.handles != nil {
+		n := len(h.handles) - 1
+		if handle := h.handles[n]; handle != nil {
+			handle.index = -1
+		}
+		h.handles[n] = nil
+		h.handles = h.handles[:n]
+	}
+	return e
+}
+
+// Pop removes and returns the top of h. ok is false if h is empty.
+func (h *BinaryHeap[E]) Pop() (e E, ok bool) {
+	if h.heap.Len() == 0 {
+		return
+	}
+	return h.removeAt(0), true
+}
+
+// Peek returns the top of h without removing it. ok is false if h is empty.
+func (h *BinaryHeap[E]) Peek() (e E, ok bool) {
+	return h.heap.Peek()
+}
+
+// Len returns the number of elements in h.
+func (h *BinaryHeap[E]) Len() int {
+	return h.heap.Len()
+}
+
+// checkHandle panics if handle does not refer to an element of h.
+func (h *BinaryHeap[E]) checkHandle(handle *HeapHandle[E]) {
+	if handle.heap != h {
+		panic("HeapHandle used with a heap it does not belong to")
+	}
+	if !handle.Valid() {
+		panic("HeapHandle used after its element was removed")
+	}
+}
+
+// Fix restores heap order after the priority of the element referred to by
+// handle has changed, e.g. by mutating the value a pointer element points
+// to.
+func (h *BinaryHeap[E]) Fix(handle *HeapHandle[E]) {
+	h.checkHandle(handle)
+	h.heap.Fix(handle.index)
+}
+
+// Update replaces the element referred to by handle with e, and restores
+// heap order.
+func (h *BinaryHeap[E]) Update(handle *HeapHandle[E], e E) {
+	h.checkHandle(handle)
+	h.heap.Elems()[handle.index] = e
+	h.heap.Fix(handle.index)
+}
+
+// Remove removes and returns the element referred to by handle, which is no
+// longer Valid afterwards.
+func (h *BinaryHeap[E]) Remove(handle *HeapHandle[E]) E {
+	h.checkHandle(handle)
+	return h.removeAt(handle.index)
+}
@@ -0,0 +1,191 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+func TestBinaryHeap(t *testing.T) {
+	h := NewBinaryHeap(compare.Reverse(compare.Less[int]))
+	for _, e := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		h.Push(e)
+	}
+	if top, ok := h.Peek(); !ok || top != 9 {
+		t.Errorf("Want Peek() == (9, true), Got (%d, %t)", top, ok)
+	}
+	var got []int
+	for e, ok := h.Pop(); ok; e, ok = h.Pop() {
+		got = append(got, e)
+	}
+	if want := []int{9, 6, 5, 4, 3, 2, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+}
+
+func TestBoundedPriorityQueue(t *testing.T) {
+	q := NewBoundedPriorityQueue(3, compare.Less[int])
+	admitted := []bool{}
+	for _, e := range []int{5, 1, 8, 3, 9, 2, 7} {
+		admitted = append(admitted, q.Offer(e))
+	}
+	if want := []bool{true, true, true, true, true, false, true}; !slices.Equal(admitted, want) {
+		t.Errorf("Want Offer() results %v, Got %v", want, admitted)
+	}
+	if q.Len() != 3 || q.Cap() != 3 {
+		t.Errorf("Want Len() == Cap() == 3, Got %d and %d", q.Len(), q.Cap())
+	}
+	if got := q.Sorted(); !slices.Equal(got, []int{9, 8, 7}) {
+		t.Errorf("Want Sorted() == [9 8 7], Got %v", got)
+	}
+	if e, ok := q.Poll(); !ok || e != 7 {
+		t.Errorf("Want Poll() == (7, true), Got (%d, %t)", e, ok)
+	}
+	// Now that there's room, lower-priority elements are admitted again.
+	if !q.Offer(1) {
+		t.Errorf("Want Offer(1) == true with room in the queue, Got false")
+	}
+	if e, ok := q.Peek(); !ok || e != 1 {
+		t.Errorf("Want Peek() == (1, true), Got (%d, %t)", e, ok)
+	}
+}
+
+func TestBinaryHeapHandles(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	h := NewBinaryHeap(func(a, b *task) bool { return a.priority < b.priority })
+	handles := map[string]*HeapHandle[*task]{}
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		handles[name] = h.Push(&task{name, i})
+	}
+
+	// Mutate priorities in place, then Fix.
+	handles["e"].Value().priority = -1
+	h.Fix(handles["e"])
+	h.Update(handles["a"], &task{"a", 10})
+	if removed := h.Remove(handles["c"]); removed.name != "c" {
+		t.Errorf("Want Remove() to return c, Got %s", removed.name)
+	}
+	if handles["c"].Valid() {
+		t.Errorf("Want removed handle to be invalid, Got Valid() == true")
+	}
+
+	var got []string
+	for e, ok := h.Pop(); ok; e, ok = h.Pop() {
+		got = append(got, e.name)
+	}
+	if want := []string{"e", "b", "d", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+	for name, handle := range handles {
+		if handle.Valid() {
+			t.Errorf("Want handle %s invalid after popping everything, Got Valid() == true", name)
+		}
+	}
+}
+
+func TestBinaryHeapHandlePanics(t *testing.T) {
+	h1, h2 := NewBinaryHeap(compare.Less[int]), NewBinaryHeap(compare.Less[int])
+	handle := h1.Push(1)
+	for name, f := range map[string]func(){
+		"foreign heap": func() { h2.Fix(handle) },
+		"removed":      func() { h1.Pop(); h1.Remove(handle) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Want panic using %s handle, Got no panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestBinaryHeapPushAllAndMeld(t *testing.T) {
+	for _, sizes := range [][2]int{{1000, 10}, {10, 1000}, {0, 100}, {100, 0}} {
+		h1, h2 := NewBinaryHeap(compare.Less[int]), NewBinaryHeap(compare.Less[int])
+		var want []int
+		handles := map[*HeapHandle[int]]int{}
+		for i := 0; i < sizes[0]; i++ {
+			e := (i * 7919) % 1009
+			handles[h1.Push(e)] = e
+			want = append(want, e)
+		}
+		h1.PushAll(slices.Values([]int{5, 3}))
+		want = append(want, 5, 3)
+		for i := 0; i < sizes[1]; i++ {
+			e := (i * 104729) % 997
+			handles[h2.Push(e)] = e
+			want = append(want, e)
+		}
+
+		h1.Meld(h2)
+		if h2.Len() != 0 {
+			t.Errorf("Want other heap empty after Meld, Got Len() == %d", h2.Len())
+		}
+		for handle, e := range handles {
+			if got := handle.Value(); handle.heap != h1 || got != e {
+				t.Fatalf("Want handle to refer to %d in the melded heap, Got %d", e, got)
+			}
+		}
+
+		slices.Sort(want)
+		var got []int
+		for e, ok := h1.Pop(); ok; e, ok = h1.Pop() {
+			got = append(got, e)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Want heaps of sizes %v to pop in sorted order after Meld", sizes)
+		}
+	}
+}
+
+func TestNewHeapFromSlice(t *testing.T) {
+	elems := make([]int, 100, 200)
+	for i := range elems {
+		elems[i] = (i * 37) % 101
+	}
+	want := slices.Sorted(slices.Values(elems))
+
+	h := NewHeapFromSlice(elems, compare.Less[int])
+	if top, _ := h.Peek(); &elems[0] != &h.heap.Elems()[0] || top != elems[0] {
+		t.Errorf("Want heap to use elems as its storage without copying")
+	}
+	// Handles work for elements pushed after construction.
+	handle := h.Push(-1)
+	h.Update(handle, 1000)
+	want = append(want, 1000)
+
+	var got []int
+	for e, ok := h.Pop(); ok; e, ok = h.Pop() {
+		got = append(got, e)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+}
+
+func TestStablePriorityQueue(t *testing.T) {
+	type task struct {
+		prio int
+		name string
+	}
+	q := NewStablePriorityQueue(func(t1, t2 task) bool { return t1.prio < t2.prio })
+	for i := 0; i < 20; i++ {
+		q.Push(task{i % 3, string(rune('a' + i))})
+	}
+	if top, ok := q.Peek(); !ok || top.name != "a" {
+		t.Errorf("Want Peek() == a, Got (%v, %t)", top, ok)
+	}
+	var got string
+	for e, ok := q.Pop(); ok; e, ok = q.Pop() {
+		got += e.name
+	}
+	if want := "adgjmpsbehknqtcfilor"; got != want || q.Len() != 0 {
+		t.Errorf("Want FIFO order within priorities %q, Got %q", want, got)
+	}
+}
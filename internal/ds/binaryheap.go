@@ -1,9 +1,158 @@
 package ds
 
-import "github.org/jccarlson/collections/compare"
+import (
+	"math/bits"
 
+	"github.org/jccarlson/collections/compare"
+)
+
+// BinaryHeap is a binary heap of elements of type E, stored in a slice. The
+// top of the heap is an element which no other element is ordered before.
 type BinaryHeap[E any] struct {
-	tree   []E
-	before compare.Ordering[E]
-	size   int
+	Ordering compare.Ordering[E]
+	// Swapped, if non-nil, is called after the elements at indexes i and j
+	// are swapped, so that callers can track the positions of elements.
+	Swapped func(i, j int)
+
+	tree []E
+}
+
+func (h *BinaryHeap[E]) less(i, j int) bool {
+	return h.Ordering(h.tree[i], h.tree[j])
+}
+
+func (h *BinaryHeap[E]) swap(i, j int) {
+	h.tree[i], h.tree[j] = h.tree[j], h.tree[i]
+	if h.Swapped != nil {
+		h.Swapped(i, j)
+	}
+}
+
+// up moves the element at i towards the root until it is in heap order.
+func (h *BinaryHeap[E]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the element at i towards the leaves until it is in heap order,
+// and returns true if it moved.
+func (h *BinaryHeap[E]) down(i int) bool {
+	start, n := i, len(h.tree)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && h.less(right, child) {
+			child = right
+		}
+		if !h.less(child, i) {
+			break
+		}
+		h.swap(i, child)
+		i = child
+	}
+	return i > start
+}
+
+func (h *BinaryHeap[E]) Push(elem E) {
+	h.tree = append(h.tree, elem)
+	h.up(len(h.tree) - 1)
+}
+
+// PushAll adds es to the heap. If that is cheaper than pushing each element,
+// the whole heap is rebuilt in O(n) time instead.
+func (h *BinaryHeap[E]) PushAll(es []E) {
+	n := len(h.tree)
+	h.tree = append(h.tree, es...)
+	if total := len(h.tree); len(es)*bits.Len(uint(total)) > 2*total {
+		h.Init()
+		return
+	}
+	for i := n; i < len(h.tree); i++ {
+		h.up(i)
+	}
+}
+
+// Init establishes heap order over all the elements of the heap in O(n) time.
+func (h *BinaryHeap[E]) Init() {
+	for i := len(h.tree)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+// InitFrom replaces the elements of the heap with elems, which the heap takes
+// ownership of, and establishes heap order in place in O(n) time.
+func (h *BinaryHeap[E]) InitFrom(elems []E) {
+	h.tree = elems
+	h.Init()
+}
+
+// Clear removes all elements from the heap.
+func (h *BinaryHeap[E]) Clear() {
+	clear(h.tree)
+	h.tree = h.tree[:0]
+}
+
+// Pop removes and returns the top of the heap.
+func (h *BinaryHeap[E]) Pop() (elem E, ok bool) {
+	if len(h.tree) == 0 {
+		return
+	}
+	return h.Remove(0), true
+}
+
+// Peek returns the top of the heap without removing it.
+func (h *BinaryHeap[E]) Peek() (elem E, ok bool) {
+	if len(h.tree) == 0 {
+		return
+	}
+	return h.tree[0], true
+}
+
+// Remove removes and returns the element at index i of the heap. The removed
+// element is first swapped to the last index.
+func (h *BinaryHeap[E]) Remove(i int) E {
+	n := len(h.tree) - 1
+	h.swap(i, n)
+	var zero E
+	elem := h.tree[n]
+	h.tree[n] = zero
+	h.tree = h.tree[:n]
+	if i != n {
+		h.Fix(i)
+	}
+	return elem
+}
+
+// Fix restores heap order after the element at index i has changed.
+func (h *BinaryHeap[E]) Fix(i int) {
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+// Replace replaces the top of the heap with elem, and returns the old top.
+// It is more efficient than a Pop followed by a Push. The heap must not be
+// empty.
+func (h *BinaryHeap[E]) Replace(elem E) E {
+	top := h.tree[0]
+	h.tree[0] = elem
+	h.down(0)
+	return top
+}
+
+// Elems returns the elements of the heap in heap order, aliasing its storage.
+func (h *BinaryHeap[E]) Elems() []E {
+	return h.tree
+}
+
+func (h *BinaryHeap[E]) Len() int {
+	return len(h.tree)
 }
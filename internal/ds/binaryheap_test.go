@@ -0,0 +1,57 @@
+package ds
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+func TestBinaryHeap(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	h := &BinaryHeap[int]{Ordering: compare.Less[int]}
+	var want []int
+	for i := 0; i < 1000; i++ {
+		n := rnd.Intn(100)
+		h.Push(n)
+		want = append(want, n)
+	}
+	// Remove some elements from the middle of the heap.
+	for i := 0; i < 100; i++ {
+		removed := h.Remove(rnd.Intn(h.Len()))
+		want = slices.Delete(want, slices.Index(want, removed), slices.Index(want, removed)+1)
+	}
+	slices.Sort(want)
+
+	if top, ok := h.Peek(); !ok || top != want[0] {
+		t.Errorf("Want Peek() == (%d, true), Got (%d, %t)", want[0], top, ok)
+	}
+	for _, w := range want {
+		if got, ok := h.Pop(); !ok || got != w {
+			t.Fatalf("Want Pop() == (%d, true), Got (%d, %t)", w, got, ok)
+		}
+	}
+	if _, ok := h.Pop(); ok {
+		t.Errorf("Want Pop() on empty heap == (_, false), Got (_, true)")
+	}
+}
+
+func TestBinaryHeapPushAll(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000} {
+		h := &BinaryHeap[int]{Ordering: compare.Less[int]}
+		h.Push(500)
+		es := make([]int, n)
+		for i := range es {
+			es[i] = (i * 31) % 997
+		}
+		h.PushAll(es)
+		want := append(slices.Clone(es), 500)
+		slices.Sort(want)
+		for _, w := range want {
+			if got, _ := h.Pop(); got != w {
+				t.Fatalf("Want Pop() == %d after PushAll of %d elements, Got %d", w, n, got)
+			}
+		}
+	}
+}
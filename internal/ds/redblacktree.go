@@ -20,6 +20,16 @@ type TreeNode[E any] struct {
 	child  [2]*TreeNode[E]
 
 	black bool
+	// size is the number of nodes in the subtree rooted at this node.
+	size int
+}
+
+// subtreeSize returns the number of nodes in the subtree rooted at n.
+func subtreeSize[E any](n *TreeNode[E]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
 }
 
 func (n *TreeNode[E]) isRed() bool {
@@ -62,41 +72,119 @@ func (n *TreeNode[E]) Walk(d Direction) *TreeNode[E] {
 type RedBlackTree[E any] struct {
 	Ordering compare.Ordering[E]
 
+	// MaxFree is the maximum number of deleted nodes kept on a freelist to be
+	// reused by subsequent Puts. If it is 0, deleted nodes are left to the
+	// garbage collector.
+	MaxFree int
+
 	root        *TreeNode[E]
 	first, last *TreeNode[E]
 	size        int
+
+	// free is a list of deleted nodes, linked through their parent pointers.
+	free  *TreeNode[E]
+	nfree int
+}
+
+// newNode returns a red node holding elem, reusing a node from the freelist if
+// there is one.
+func (m *RedBlackTree[E]) newNode(elem E) *TreeNode[E] {
+	if n := m.free; n != nil {
+		m.free, n.parent = n.parent, nil
+		m.nfree--
+		n.Elem, n.size = elem, 1
+		return n
+	}
+	return &TreeNode[E]{Elem: elem, size: 1}
+}
+
+// freeNode adds n, which has been removed from the tree, to the freelist if
+// it is not full.
+func (m *RedBlackTree[E]) freeNode(n *TreeNode[E]) {
+	if m.nfree >= m.MaxFree {
+		return
+	}
+	// Clear n so that it doesn't retain its element or other nodes.
+	*n = TreeNode[E]{parent: m.free}
+	m.free = n
+	m.nfree++
+}
+
+// ResetFreelist releases the nodes on the freelist to the garbage collector.
+func (m *RedBlackTree[E]) ResetFreelist() {
+	m.free, m.nfree = nil, 0
 }
 
 func (m *RedBlackTree[E]) Put(elem E) {
-	node := &TreeNode[E]{Elem: elem}
-	m.putRecursive(&m.root, node, nil)
+	var parent *TreeNode[E]
+	dir := Left
+	for n := m.root; n != nil; {
+		switch {
+		case m.Ordering(elem, n.Elem):
+			parent, dir, n = n, Left, n.child[Left]
+		case m.Ordering(n.Elem, elem):
+			parent, dir, n = n, Right, n.child[Right]
+		default:
+			n.Elem = elem
+			return
+		}
+	}
+
+	node := m.newNode(elem)
+	node.parent = parent
+	if parent == nil {
+		m.root = node
+	} else {
+		parent.child[dir] = node
+	}
 	if m.first == nil || m.Ordering(node.Elem, m.first.Elem) {
 		m.first = node
 	}
 	if m.last == nil || m.Ordering(m.last.Elem, node.Elem) {
 		m.last = node
 	}
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
+	m.insertionRebalance(node)
+	m.size++
 }
 
-func (m *RedBlackTree[E]) putRecursive(root **TreeNode[E], e *TreeNode[E], parent *TreeNode[E]) {
-	if *root == nil {
-		*root = e
-		e.parent = parent
-		m.insertionRebalance(e)
-		m.size++
-		return
+// Build replaces the elements of m with elems, which must be in strictly
+// increasing order, in O(n) time. The nodes are arranged in a perfectly
+// balanced tree, with the nodes of an incomplete bottom level painted red.
+func (m *RedBlackTree[E]) Build(elems []E) {
+	// Nodes deeper than fullDepth, the depth of the deepest complete level,
+	// are red.
+	fullDepth := -1
+	for n := len(elems) + 1; n > 1; n >>= 1 {
+		fullDepth++
 	}
-	if m.Ordering(e.Elem, (*root).Elem) {
-		m.putRecursive(&(*root).child[Left], e, *root)
-		return
-
+	var build func(parent *TreeNode[E], elems []E, depth int) *TreeNode[E]
+	build = func(parent *TreeNode[E], elems []E, depth int) *TreeNode[E] {
+		if len(elems) == 0 {
+			return nil
+		}
+		mid := len(elems) / 2
+		n := m.newNode(elems[mid])
+		n.parent, n.black = parent, depth <= fullDepth
+		n.child[Left] = build(n, elems[:mid], depth+1)
+		n.child[Right] = build(n, elems[mid+1:], depth+1)
+		n.size = len(elems)
+		return n
 	}
-	if m.Ordering((*root).Elem, e.Elem) {
-		m.putRecursive(&(*root).child[Right], e, *root)
-		return
 
+	m.root, m.size = build(nil, elems, 0), len(elems)
+	m.first, m.last = nil, nil
+	if m.root != nil {
+		m.first, m.last = m.root, m.root
+		for m.first.child[Left] != nil {
+			m.first = m.first.child[Left]
+		}
+		for m.last.child[Right] != nil {
+			m.last = m.last.child[Right]
+		}
 	}
-	(*root).Elem = e.Elem
 }
 
 func (m *RedBlackTree[E]) insertionRebalance(e *TreeNode[E]) {
@@ -176,104 +264,121 @@ func (m *RedBlackTree[E]) rotate(e *TreeNode[E], dir Direction) {
 	}
 	(*rootPtr).child[dir] = e
 	(*rootPtr).child[dir].parent = (*rootPtr)
+
+	// The new root spans the same nodes e did.
+	(*rootPtr).size = e.size
+	e.size = 1 + subtreeSize(e.child[Left]) + subtreeSize(e.child[Right])
 }
 
-func (m *RedBlackTree[E]) Get(elem E) (E, bool) {
-	return getRecursive(m.root, elem, m.Ordering)
+// find returns the node holding an element equal to elem, or nil if there is
+// none.
+func (m *RedBlackTree[E]) find(elem E) *TreeNode[E] {
+	for n := m.root; n != nil; {
+		switch {
+		case m.Ordering(elem, n.Elem):
+			n = n.child[Left]
+		case m.Ordering(n.Elem, elem):
+			n = n.child[Right]
+		default:
+			return n
+		}
+	}
+	return nil
 }
 
-func (m *RedBlackTree[E]) Has(elem E) bool {
-	_, ok := getRecursive(m.root, elem, m.Ordering)
-	return ok
+// Find returns the node holding elem, or nil if elem is not in m.
+func (m *RedBlackTree[E]) Find(elem E) *TreeNode[E] {
+	return m.find(elem)
 }
 
-func getRecursive[E any](root *TreeNode[E], elem E, before compare.Ordering[E]) (value E, ok bool) {
-	if root == nil {
-		return
-	}
-	if before(elem, root.Elem) {
-		return getRecursive(root.child[Left], elem, before)
+func (m *RedBlackTree[E]) Get(elem E) (value E, ok bool) {
+	if n := m.find(elem); n != nil {
+		return n.Elem, true
 	}
-	if before(root.Elem, elem) {
-		return getRecursive(root.child[Right], elem, before)
-	}
-	return root.Elem, true
+	return
 }
 
-func (m *RedBlackTree[E]) Delete(elem E) {
-	m.deleteRecursive(&m.root, elem)
+func (m *RedBlackTree[E]) Has(elem E) bool {
+	return m.find(elem) != nil
 }
 
-func (m *RedBlackTree[E]) deleteRecursive(root **TreeNode[E], elem E) {
-	if *root == nil {
-		// elem not in the tree, delete nothing.
-		return
+func (m *RedBlackTree[E]) Delete(elem E) {
+	if n := m.find(elem); n != nil {
+		m.DeleteNode(n)
 	}
-	before := m.Ordering
-	if before(elem, (*root).Elem) {
-		m.deleteRecursive(&(*root).child[Left], elem)
-		return
+}
 
+// DeleteNode removes the element held by n, a node of m, and returns the node
+// now holding the next element in order, or nil if n held the last. The next
+// element may be moved into n, so DeleteNode invalidates the node which held
+// it; other nodes of m are unaffected.
+func (m *RedBlackTree[E]) DeleteNode(n *TreeNode[E]) *TreeNode[E] {
+	next := n.Walk(Right)
+
+	// Update first and last pointers if needed. The first and last nodes
+	// have at most one child, so they are always the node removed below.
+	if n == m.first {
+		m.first = n.Walk(Right)
 	}
-	if before((*root).Elem, elem) {
-		m.deleteRecursive(&(*root).child[Right], elem)
-		return
-
+	if n == m.last {
+		m.last = n.Walk(Left)
 	}
-	// Found the node, but if it has 2 non-nil children, we need to swap it
-	// with it's in-order successor.
 
-	if (*root).child[Left] != nil && (*root).child[Right] != nil {
-		t := &(*root).child[Right]
-		for (*t).child[Left] != nil {
-			t = &(*t).child[Left]
+	// If n has 2 non-nil children, we need to swap it with it's in-order
+	// successor, and remove the successor's node instead.
+	if n.child[Left] != nil && n.child[Right] != nil {
+		s := n.child[Right]
+		for s.child[Left] != nil {
+			s = s.child[Left]
 		}
-		(*root).Elem = (*t).Elem
-		root = t
+		n.Elem = s.Elem
+		if s == m.last {
+			m.last = n
+		}
+		n, next = s, n
 	}
 
-	// root now references the parent's child pointer to the node to be
-	// deleted. *root has at most 1 non-nil child.
-
-	if (*root).isRed() || ((*root).parent == nil && (*root).child[Left] == nil && (*root).child[Right] == nil) {
-		// *root can simply be deleted if:
-		//     - *root is red (guaranteed to have no children).
-		//     - *root is the actual root and has no children.
-		*root = nil
-		m.size--
-		return
+	// n now has at most 1 non-nil child.
+	m.removeNode(n)
+	for p := n.parent; p != nil; p = p.parent {
+		p.size--
 	}
+	m.freeNode(n)
+	m.size--
+	return next
+}
 
-	// *root is black, with at most one child.
-	// If *root has one child, it must be red, so replace *root with the child
-	// and paint the child black.
-	if (*root).child[Right] != nil {
-		(*root).child[Right].parent = (*root).parent
-		*root = (*root).child[Right]
-		(*root).black = true
-		m.size--
-		return
+// removeNode removes n, which has at most one child, from the tree and
+// rebalances it. n.parent is left pointing to n's last parent.
+func (m *RedBlackTree[E]) removeNode(n *TreeNode[E]) {
+	slot := &m.root
+	if n.parent != nil {
+		slot = &n.parent.child[childDir(n)]
 	}
-	if (*root).child[Left] != nil {
-		(*root).child[Left].parent = (*root).parent
-		*root = (*root).child[Left]
-		(*root).black = true
-		m.size--
+
+	if n.isRed() || (n.parent == nil && n.child[Left] == nil && n.child[Right] == nil) {
+		// n can simply be deleted if:
+		//     - n is red (guaranteed to have no children).
+		//     - n is the actual root and has no children.
+		*slot = nil
 		return
 	}
 
-	// *root is black, with no children, and is not the root of the tree.
-	m.balanceBlackLeafForDeletion(*root)
-
-	// Update first and last pointers if needed.
-	if m.first == *root {
-		m.first = (*root).Walk(Right)
-	}
-	if m.last == *root {
-		m.last = (*root).Walk(Left)
+	// n is black, with at most one child. If n has one child, it must be
+	// red, so replace n with the child and paint the child black.
+	for _, child := range n.child {
+		if child != nil {
+			child.parent = n.parent
+			*slot = child
+			child.black = true
+			return
+		}
 	}
-	*root = nil
-	m.size--
+
+	// n is black, with no children, and is not the root of the tree. The
+	// rebalancing rotations never change n's parent, so slot remains valid.
+	m.balanceBlackLeafForDeletion(n)
+	*slot = nil
 }
 
 // balanceBlackLeafFOrDeletion iterates up and modifies m so that n's black
@@ -404,5 +509,68 @@ func (m *RedBlackTree[E]) First() *TreeNode[E] {
 }
 
 func (m *RedBlackTree[E]) Last() *TreeNode[E] {
-	return m.first
+	return m.last
+}
+
+// Ceiling returns the first node whose element is not before elem, or nil if
+// every element in the tree is before elem.
+func (m *RedBlackTree[E]) Ceiling(elem E) *TreeNode[E] {
+	var ceil *TreeNode[E]
+	for n := m.root; n != nil; {
+		if m.Ordering(n.Elem, elem) {
+			n = n.child[Right]
+		} else {
+			ceil, n = n, n.child[Left]
+		}
+	}
+	return ceil
+}
+
+// Floor returns the last node whose element is not after elem, or nil if
+// every element in the tree is after elem.
+func (m *RedBlackTree[E]) Floor(elem E) *TreeNode[E] {
+	var floor *TreeNode[E]
+	for n := m.root; n != nil; {
+		if m.Ordering(elem, n.Elem) {
+			n = n.child[Left]
+		} else {
+			floor, n = n, n.child[Right]
+		}
+	}
+	return floor
+}
+
+// Rank returns the number of elements in the tree before elem, in O(log n)
+// time.
+func (m *RedBlackTree[E]) Rank(elem E) int {
+	rank := 0
+	for n := m.root; n != nil; {
+		if m.Ordering(n.Elem, elem) {
+			rank += subtreeSize(n.child[Left]) + 1
+			n = n.child[Right]
+		} else {
+			n = n.child[Left]
+		}
+	}
+	return rank
+}
+
+// Select returns the node with rank elements before it, or nil if rank is
+// out of range, in O(log n) time.
+func (m *RedBlackTree[E]) Select(rank int) *TreeNode[E] {
+	if rank < 0 || rank >= m.size {
+		return nil
+	}
+	for n := m.root; n != nil; {
+		switch l := subtreeSize(n.child[Left]); {
+		case rank < l:
+			n = n.child[Left]
+		case rank == l:
+			return n
+		default:
+			rank -= l + 1
+			n = n.child[Right]
+		}
+	}
+	return nil
 }
@@ -41,6 +41,10 @@ func validateTree(n *TreeNode[int]) (blackHeight int, err error) {
 		}
 	}
 
+	if want := 1 + subtreeSize(n.child[Left]) + subtreeSize(n.child[Right]); n.size != want {
+		return 0, fmt.Errorf("Node @ %p with elem: %v has size %v, want %v", n, n.Elem, n.size, want)
+	}
+
 	// Validate subtrees.
 	bhLeft, err := validateTree(n.child[Left])
 	if err != nil {
@@ -68,13 +72,13 @@ func TestAllBlackPerfectTreeDelete(t *testing.T) {
 	// Manually construct a perfect binary tree with all black nodes. By
 	// definition, this is a valid red-black tree.
 	rbTree := &RedBlackTree[int]{Ordering: compare.Less[int]}
-	rbTree.root = &TreeNode[int]{Elem: 4, black: true}
-	rbTree.root.child[Left] = &TreeNode[int]{Elem: 2, parent: rbTree.root, black: true}
-	rbTree.root.child[Left].child[Left] = &TreeNode[int]{Elem: 1, parent: rbTree.root.child[Left], black: true}
-	rbTree.root.child[Left].child[Right] = &TreeNode[int]{Elem: 3, parent: rbTree.root.child[Left], black: true}
-	rbTree.root.child[Right] = &TreeNode[int]{Elem: 6, parent: rbTree.root, black: true}
-	rbTree.root.child[Right].child[Left] = &TreeNode[int]{Elem: 5, parent: rbTree.root.child[Right], black: true}
-	rbTree.root.child[Right].child[Right] = &TreeNode[int]{Elem: 7, parent: rbTree.root.child[Right], black: true}
+	rbTree.root = &TreeNode[int]{Elem: 4, black: true, size: 7}
+	rbTree.root.child[Left] = &TreeNode[int]{Elem: 2, parent: rbTree.root, black: true, size: 3}
+	rbTree.root.child[Left].child[Left] = &TreeNode[int]{Elem: 1, parent: rbTree.root.child[Left], black: true, size: 1}
+	rbTree.root.child[Left].child[Right] = &TreeNode[int]{Elem: 3, parent: rbTree.root.child[Left], black: true, size: 1}
+	rbTree.root.child[Right] = &TreeNode[int]{Elem: 6, parent: rbTree.root, black: true, size: 3}
+	rbTree.root.child[Right].child[Left] = &TreeNode[int]{Elem: 5, parent: rbTree.root.child[Right], black: true, size: 1}
+	rbTree.root.child[Right].child[Right] = &TreeNode[int]{Elem: 7, parent: rbTree.root.child[Right], black: true, size: 1}
 
 	rbTree.first, rbTree.last = rbTree.root.child[Left].child[Left], rbTree.root.child[Right].child[Right]
 
@@ -154,3 +158,194 @@ func TestRedBlackConstraints(t *testing.T) {
 		}
 	})
 }
+
+func TestFirstLastCeilingFloor(t *testing.T) {
+	rbTree := &RedBlackTree[int]{Ordering: compare.Less[int]}
+	rng := rand.New(rand.NewSource(0xF00D))
+	present := map[int]bool{}
+
+	for i := 0; i < 2000; i++ {
+		e := rng.Intn(200)
+		if rng.Intn(3) == 0 {
+			rbTree.Delete(e)
+			delete(present, e)
+		} else {
+			rbTree.Put(e)
+			present[e] = true
+		}
+
+		min, max := -1, -1
+		for k := range present {
+			if min == -1 || k < min {
+				min = k
+			}
+			if k > max {
+				max = k
+			}
+		}
+		if len(present) == 0 {
+			if rbTree.First() != nil || rbTree.Last() != nil {
+				t.Fatalf("Want First() == Last() == nil for empty tree")
+			}
+			continue
+		}
+		if f, l := rbTree.First(), rbTree.Last(); f == nil || l == nil || f.Elem != min || l.Elem != max {
+			t.Fatalf("Want First().Elem == %d and Last().Elem == %d, Got %v and %v", min, max, f, l)
+		}
+
+		q := rng.Intn(220) - 10
+		wantCeil, wantFloor := -1, -1
+		for k := range present {
+			if k >= q && (wantCeil == -1 || k < wantCeil) {
+				wantCeil = k
+			}
+			if k <= q && (wantFloor == -1 || k > wantFloor) {
+				wantFloor = k
+			}
+		}
+		if c := rbTree.Ceiling(q); (c == nil && wantCeil != -1) || (c != nil && c.Elem != wantCeil) {
+			t.Fatalf("Want Ceiling(%d) == %d, Got %v", q, wantCeil, c)
+		}
+		if f := rbTree.Floor(q); (f == nil && wantFloor != -1) || (f != nil && f.Elem != wantFloor) {
+			t.Fatalf("Want Floor(%d) == %d, Got %v", q, wantFloor, f)
+		}
+	}
+}
+
+func TestFreelistReusesNodes(t *testing.T) {
+	rbTree := &RedBlackTree[int]{Ordering: compare.Less[int], MaxFree: 2}
+	for i := 0; i < 10; i++ {
+		rbTree.Put(i)
+	}
+	for i := 0; i < 5; i++ {
+		rbTree.Delete(i)
+	}
+	if rbTree.nfree != 2 {
+		t.Errorf("Want 2 nodes on the freelist, Got %d", rbTree.nfree)
+	}
+	for n := rbTree.free; n != nil; n = n.parent {
+		if n.Elem != 0 || n.child[Left] != nil || n.child[Right] != nil {
+			t.Errorf("Want freed node to be cleared, Got %+v", n)
+		}
+	}
+
+	rbTree.Put(100)
+	rbTree.Put(101)
+	rbTree.Put(102)
+	if rbTree.nfree != 0 || rbTree.free != nil {
+		t.Errorf("Want freelist emptied by Puts, Got %d nodes", rbTree.nfree)
+	}
+	if _, err := validateTree(rbTree.root); err != nil {
+		t.Error(err.Error())
+	}
+	if rbTree.Len() != 8 {
+		t.Errorf("Want Len() == 8, Got %d", rbTree.Len())
+	}
+}
+
+func TestBuild(t *testing.T) {
+	for n := 0; n < 70; n++ {
+		rbTree := &RedBlackTree[int]{Ordering: compare.Less[int]}
+		elems := make([]int, n)
+		for i := range elems {
+			elems[i] = i * 2
+		}
+		rbTree.Build(elems)
+		if _, err := validateTree(rbTree.root); err != nil {
+			t.Fatalf("Build(%d elems): %v", n, err)
+		}
+		i := 0
+		for tn := rbTree.First(); tn != nil; tn = tn.Walk(Right) {
+			if tn.Elem != i*2 {
+				t.Fatalf("Build(%d elems): Want elem %d in order, Got %d", n, i*2, tn.Elem)
+			}
+			i++
+		}
+		if i != n || rbTree.Len() != n {
+			t.Fatalf("Build(%d elems): Want %[1]d elems, Got %d with Len() == %d", n, i, rbTree.Len())
+		}
+		if n > 0 && rbTree.Last().Elem != (n-1)*2 {
+			t.Fatalf("Build(%d elems): Want Last() == %d, Got %d", n, (n-1)*2, rbTree.Last().Elem)
+		}
+
+		// The tree remains valid under further modification.
+		rbTree.Put(-1)
+		rbTree.Delete(0)
+		if _, err := validateTree(rbTree.root); err != nil {
+			t.Fatalf("Build(%d elems) then Put and Delete: %v", n, err)
+		}
+	}
+}
+
+const benchmarkTreeSize = 1 << 21
+
+func newBenchmarkTree(maxFree int) (*RedBlackTree[int], []int) {
+	rng := rand.New(rand.NewSource(0xBE7C4))
+	rbTree := &RedBlackTree[int]{Ordering: compare.Less[int], MaxFree: maxFree}
+	elems := rng.Perm(benchmarkTreeSize)
+	for _, e := range elems {
+		rbTree.Put(e)
+	}
+	return rbTree, elems
+}
+
+func BenchmarkRedBlackTreeGet(b *testing.B) {
+	rbTree, elems := newBenchmarkTree(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rbTree.Get(elems[i%len(elems)])
+	}
+}
+
+func BenchmarkRedBlackTreePutDelete(b *testing.B) {
+	for _, maxFree := range []int{0, 1024} {
+		b.Run(fmt.Sprintf("MaxFree=%d", maxFree), func(b *testing.B) {
+			rbTree, elems := newBenchmarkTree(maxFree)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				e := elems[i%len(elems)]
+				rbTree.Delete(e)
+				rbTree.Put(e)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	rbTree := &RedBlackTree[int]{Ordering: compare.Less[int]}
+	rng := rand.New(rand.NewSource(0x5EED))
+	present := make([]bool, 500)
+	for i := 0; i < 2000; i++ {
+		e := rng.Intn(len(present))
+		if rng.Intn(3) == 0 {
+			rbTree.Delete(e)
+			present[e] = false
+		} else {
+			rbTree.Put(e)
+			present[e] = true
+		}
+	}
+	if _, err := validateTree(rbTree.root); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	want := 0
+	for e := range present {
+		if got := rbTree.Rank(e); got != want {
+			t.Fatalf("Want Rank(%d) == %d, Got %d", e, want, got)
+		}
+		if present[e] {
+			if n := rbTree.Select(want); n == nil || n.Elem != e {
+				t.Fatalf("Want Select(%d) to return the node of %d, Got %v", want, e, n)
+			}
+			want++
+		}
+	}
+	if rbTree.Select(-1) != nil || rbTree.Select(rbTree.Len()) != nil {
+		t.Errorf("Want Select of an out of range rank == nil")
+	}
+	if got := rbTree.Rank(len(present)); got != rbTree.Len() {
+		t.Errorf("Want Rank past the end == Len() == %d, Got %d", rbTree.Len(), got)
+	}
+}
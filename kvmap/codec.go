@@ -0,0 +1,42 @@
+package kvmap
+
+import (
+	"encoding/json"
+)
+
+// Codec converts values of type T to and from bytes, for maps which store
+// their entries outside the Go heap. Encode must be deterministic: values
+// which are equal as map keys must encode to equal bytes.
+type Codec[T any] struct {
+	Encode func(T) ([]byte, error)
+	Decode func([]byte) (T, error)
+}
+
+// TextCodec returns a Codec which encodes values with EncodeKey and decodes
+// them with DecodeKey.
+func TextCodec[T any]() Codec[T] {
+	return Codec[T]{
+		Encode: func(v T) ([]byte, error) {
+			s, err := EncodeKey(v)
+			return []byte(s), err
+		},
+		Decode: func(b []byte) (T, error) {
+			return DecodeKey[T](string(b))
+		},
+	}
+}
+
+// JSONCodec returns a Codec which encodes values with encoding/json. It
+// should not be used for keys whose JSON encoding is not deterministic, such
+// as maps with non-string keys.
+func JSONCodec[T any]() Codec[T] {
+	return Codec[T]{
+		Encode: func(v T) ([]byte, error) {
+			return json.Marshal(v)
+		},
+		Decode: func(b []byte) (v T, err error) {
+			err = json.Unmarshal(b, &v)
+			return v, err
+		},
+	}
+}
@@ -1,29 +1,107 @@
 package kvmap
 
 import (
+	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 // ConcurrentWrapper wraps any kvmap.Interface so that its operations are
 // thread-safe.
+//
+// If Clone is set, the wrapper runs in read-copy-update mode: writes are
+// applied to Base under the lock, and every PublishEvery writes a copy of
+// Base made with Clone is published as an immutable snapshot. Get, Has and
+// Len read the latest snapshot without acquiring any lock, so they never
+// wait for writers, but they don't observe writes made since the last
+// publication. This suits maps which are read far more often than written;
+// raising PublishEvery trades staleness for fewer copies.
 type ConcurrentWrapper[K, V any] struct {
 	Base Interface[K, V]
+
+	// Clone returns a copy of m which shares no mutable state with it. If it
+	// is nil, reads take a read lock on Base instead.
+	Clone func(m Interface[K, V]) Interface[K, V]
+	// PublishEvery is the number of writes batched into each published
+	// snapshot in read-copy-update mode. Values below 1 publish after every
+	// write.
+	PublishEvery int
+
 	lock sync.RWMutex
+
+	snapshot atomic.Pointer[Interface[K, V]]
+	// unpublished is the number of writes since the last snapshot.
+	unpublished int
+}
+
+// publishLocked publishes a copy of Base as the snapshot read by Get, Has
+// and Len. m.lock must be held.
+func (m *ConcurrentWrapper[K, V]) publishLocked() {
+	s := m.Clone(m.Base)
+	m.snapshot.Store(&s)
+	m.unpublished = 0
+}
+
+// wroteLocked records a write to Base, publishing a snapshot if enough
+// writes have been batched. m.lock must be held for writing.
+func (m *ConcurrentWrapper[K, V]) wroteLocked() {
+	if m.Clone == nil {
+		return
+	}
+	if m.unpublished++; m.unpublished >= m.PublishEvery {
+		m.publishLocked()
+	}
+}
+
+// Publish makes every write so far visible to readers in read-copy-update
+// mode. It does nothing if Clone is nil.
+func (m *ConcurrentWrapper[K, V]) Publish() {
+	if m.Clone == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.publishLocked()
+}
+
+// readSnapshot returns the latest snapshot in read-copy-update mode,
+// publishing the first one if needed, or nil if Clone is nil.
+func (m *ConcurrentWrapper[K, V]) readSnapshot() Interface[K, V] {
+	if m.Clone == nil {
+		return nil
+	}
+	if s := m.snapshot.Load(); s != nil {
+		return *s
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.snapshot.Load() == nil {
+		m.publishLocked()
+	}
+	return *m.snapshot.Load()
 }
 
 func (m *ConcurrentWrapper[K, V]) Put(key K, value V) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	m.Base.Put(key, value)
+	m.wroteLocked()
 }
 
 func (m *ConcurrentWrapper[K, V]) Get(key K) (value V, ok bool) {
+	if s := m.readSnapshot(); s != nil {
+		return s.Get(key)
+	}
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 	return m.Base.Get(key)
 }
 
 func (m *ConcurrentWrapper[K, V]) Has(key K) bool {
+	if s := m.readSnapshot(); s != nil {
+		return s.Has(key)
+	}
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 	return m.Base.Has(key)
@@ -33,10 +111,118 @@ func (m *ConcurrentWrapper[K, V]) Delete(key K) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	m.Base.Delete(key)
+	m.wroteLocked()
+}
+
+// DeleteAndGet removes key from the map and returns its value, or false if key
+// was not in it, as one atomic operation.
+func (m *ConcurrentWrapper[K, V]) DeleteAndGet(key K) (V, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	val, ok := DeleteAndGet(m.Base, key)
+	m.wroteLocked()
+	return val, ok
 }
 
 func (m *ConcurrentWrapper[K, V]) Len() int {
+	if s := m.readSnapshot(); s != nil {
+		return s.Len()
+	}
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 	return m.Base.Len()
-}
\ No newline at end of file
+}
+
+// Stats returns a snapshot of Base's hash table statistics, read under m's
+// lock, or from the latest snapshot in read-copy-update mode, so that it is
+// safe to call concurrently with writers. If Base has no Stats method, only
+// Len is set.
+func (m *ConcurrentWrapper[K, V]) Stats() HashMapStats {
+	if s := m.readSnapshot(); s != nil {
+		return statsOf(s)
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return statsOf(m.Base)
+}
+
+func statsOf[K, V any](m Interface[K, V]) HashMapStats {
+	if s, ok := m.(interface{ Stats() HashMapStats }); ok {
+		return s.Stats()
+	}
+	return HashMapStats{Len: m.Len()}
+}
+
+// MutableView is the access to a map's entries given to the function passed
+// to UpdateBatch. It is only valid during the call, and only for the keys
+// passed to UpdateBatch.
+type MutableView[K, V any] interface {
+	Put(K, V)
+	Get(K) (V, bool)
+	Delete(K)
+	Has(K) bool
+}
+
+// concurrentView is the MutableView of a ConcurrentWrapper's Base, limited to
+// the keys passed to UpdateBatch.
+type concurrentView[K, V any] struct {
+	base Interface[K, V]
+	keys []K
+	// set holds keys if K is a comparable type which isn't an interface, so
+	// that using it as a map key can't panic.
+	set map[any]struct{}
+}
+
+func newConcurrentView[K, V any](base Interface[K, V], keys []K) *concurrentView[K, V] {
+	v := &concurrentView[K, V]{base: base, keys: keys}
+	if t := reflect.TypeFor[K](); t.Comparable() && t.Kind() != reflect.Interface {
+		v.set = make(map[any]struct{}, len(keys))
+		for _, k := range keys {
+			v.set[k] = struct{}{}
+		}
+	}
+	return v
+}
+
+func (v *concurrentView[K, V]) check(key K) {
+	if v.set != nil {
+		if _, ok := v.set[key]; ok {
+			return
+		}
+	} else if slices.ContainsFunc(v.keys, func(k K) bool { return reflect.DeepEqual(k, key) }) {
+		return
+	}
+	panic("MutableView used with a key not passed to UpdateBatch")
+}
+
+func (v *concurrentView[K, V]) Put(key K, val V) {
+	v.check(key)
+	v.base.Put(key, val)
+}
+
+func (v *concurrentView[K, V]) Get(key K) (V, bool) {
+	v.check(key)
+	return v.base.Get(key)
+}
+
+func (v *concurrentView[K, V]) Delete(key K) {
+	v.check(key)
+	v.base.Delete(key)
+}
+
+func (v *concurrentView[K, V]) Has(key K) bool {
+	v.check(key)
+	return v.base.Has(key)
+}
+
+// UpdateBatch calls f with a view of m's Base while holding m's lock, so that
+// f's reads and writes of keys are applied atomically with respect to other
+// goroutines. In read-copy-update mode, the batch counts as one write. f must
+// only use the view with keys; the view panics if used with another key.
+// Keys of types which aren't comparable are matched with reflect.DeepEqual.
+func (m *ConcurrentWrapper[K, V]) UpdateBatch(keys []K, f func(view MutableView[K, V])) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	f(newConcurrentView(m.Base, keys))
+	m.wroteLocked()
+}
@@ -0,0 +1,91 @@
+package kvmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func cloneOrderedMap(m Interface[int, int]) Interface[int, int] {
+	c := NewOrderedMap[int, int]()
+	for k, v := range m.(*OrderedMap[int, int]).All() {
+		c.Put(k, v)
+	}
+	return c
+}
+
+func TestConcurrentWrapperReadCopyUpdate(t *testing.T) {
+	m := &ConcurrentWrapper[int, int]{Base: NewOrderedMap[int, int](), Clone: cloneOrderedMap, PublishEvery: 3}
+
+	m.Put(1, 1)
+	m.Put(2, 2)
+	if m.Len() != 2 {
+		t.Errorf("Want the first read to publish 2 entries, Got Len() == %d", m.Len())
+	}
+	m.Put(3, 3)
+	m.Delete(1)
+	if !m.Has(1) || m.Has(3) {
+		t.Errorf("Want unpublished writes invisible to readers, Got Has(1) == %t, Has(3) == %t", m.Has(1), m.Has(3))
+	}
+	m.Put(4, 4)
+	if m.Has(1) || !m.Has(4) || m.Len() != 3 {
+		t.Errorf("Want 3 batched writes published, Got Has(1) == %t, Has(4) == %t, Len() == %d", m.Has(1), m.Has(4), m.Len())
+	}
+	m.Put(5, 5)
+	m.Publish()
+	if v, ok := m.Get(5); !ok || v != 5 {
+		t.Errorf("Want Get(5) == (5, true) after Publish, Got (%d, %t)", v, ok)
+	}
+}
+
+func TestConcurrentWrapperReadCopyUpdateConcurrent(t *testing.T) {
+	m := &ConcurrentWrapper[int, int]{Base: NewOrderedMap[int, int](), Clone: cloneOrderedMap, PublishEvery: 16}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				m.Put(w*250+i, i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if v, ok := m.Get(w*250 + i%250); ok && v != i%250 {
+					t.Errorf("Want Get(%d) == %d, Got %d", w*250+i%250, i%250, v)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	m.Publish()
+	if m.Len() != 1000 {
+		t.Errorf("Want Len() == 1000 after Publish, Got %d", m.Len())
+	}
+}
+
+func TestConcurrentWrapperStats(t *testing.T) {
+	m := &ConcurrentWrapper[int, int]{Base: NewComparableLinkedHashMap[int, int](Capacity(16))}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.Put(i, i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		m.Stats()
+	}
+	wg.Wait()
+	if st := m.Stats(); st.Len != 100 || st.Capacity < 100 {
+		t.Errorf("Want Stats() of the LinkedHashMap with Len 100, Got %+v", st)
+	}
+
+	o := &ConcurrentWrapper[int, int]{Base: NewOrderedMap[int, int]()}
+	o.Put(1, 1)
+	if st := o.Stats(); st != (HashMapStats{Len: 1}) {
+		t.Errorf("Want Stats() == {Len: 1} for a map without Stats, Got %+v", st)
+	}
+}
@@ -0,0 +1,88 @@
+package kvmap
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the entries of m to w as CSV records of the form key,value,
+// in m's iteration order. keyFmt and valFmt convert keys and values to text;
+// if either is nil, EncodeKey is used instead.
+func WriteCSV[K, V any](w io.Writer, m IterableMap[K, V], keyFmt func(K) (string, error), valFmt func(V) (string, error)) error {
+	return writeDelimited(csv.NewWriter(w), m, keyFmt, valFmt)
+}
+
+// WriteTSV is like WriteCSV, but separates keys and values with a tab.
+func WriteTSV[K, V any](w io.Writer, m IterableMap[K, V], keyFmt func(K) (string, error), valFmt func(V) (string, error)) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	return writeDelimited(cw, m, keyFmt, valFmt)
+}
+
+func writeDelimited[K, V any](cw *csv.Writer, m IterableMap[K, V], keyFmt func(K) (string, error), valFmt func(V) (string, error)) error {
+	if keyFmt == nil {
+		keyFmt = EncodeKey[K]
+	}
+	if valFmt == nil {
+		valFmt = EncodeKey[V]
+	}
+	it := m.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		k, err := keyFmt(e.Key())
+		if err != nil {
+			return err
+		}
+		v, err := valFmt(e.Value())
+		if err != nil {
+			return err
+		}
+		if err := cw.Write([]string{k, v}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads CSV records of the form key,value from r, and Puts them into
+// m in order. keyParse and valParse convert text to keys and values; if
+// either is nil, DecodeKey is used instead. Records with other than two
+// fields are an error. Entries read before an error remain in m.
+func ReadCSV[K, V any](r io.Reader, m Interface[K, V], keyParse func(string) (K, error), valParse func(string) (V, error)) error {
+	return readDelimited(csv.NewReader(r), m, keyParse, valParse)
+}
+
+// ReadTSV is like ReadCSV, but expects keys and values separated by a tab.
+func ReadTSV[K, V any](r io.Reader, m Interface[K, V], keyParse func(string) (K, error), valParse func(string) (V, error)) error {
+	cr := csv.NewReader(r)
+	cr.Comma = '\t'
+	return readDelimited(cr, m, keyParse, valParse)
+}
+
+func readDelimited[K, V any](cr *csv.Reader, m Interface[K, V], keyParse func(string) (K, error), valParse func(string) (V, error)) error {
+	if keyParse == nil {
+		keyParse = DecodeKey[K]
+	}
+	if valParse == nil {
+		valParse = DecodeKey[V]
+	}
+	cr.FieldsPerRecord = 2
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		k, err := keyParse(rec[0])
+		if err != nil {
+			return err
+		}
+		v, err := valParse(rec[1])
+		if err != nil {
+			return err
+		}
+		m.Put(k, v)
+	}
+}
@@ -0,0 +1,65 @@
+package kvmap
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	m := NewComparableLinkedHashMap[string, float64]()
+	m.Put("b", 2.5)
+	m.Put("a, with comma", 1)
+
+	var sb strings.Builder
+	if err := WriteCSV[string, float64](&sb, m, nil, nil); err != nil {
+		t.Fatalf("Want WriteCSV() to succeed, Got error: %v", err)
+	}
+	if want := "b,2.5\n\"a, with comma\",1\n"; sb.String() != want {
+		t.Errorf("Want %q, Got %q", want, sb.String())
+	}
+
+	got := NewComparableLinkedHashMap[string, float64]()
+	if err := ReadCSV[string, float64](strings.NewReader(sb.String()), got, nil, nil); err != nil {
+		t.Fatalf("Want ReadCSV() to succeed, Got error: %v", err)
+	}
+	if got.String() != m.String() {
+		t.Errorf("Want %v, Got %v", m, got)
+	}
+}
+
+func TestTSVCustomFormats(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	m.Put(2, 255)
+	m.Put(1, 16)
+
+	hex := func(v int) (string, error) { return strconv.FormatInt(int64(v), 16), nil }
+	var sb strings.Builder
+	if err := WriteTSV[int, int](&sb, m, nil, hex); err != nil {
+		t.Fatalf("Want WriteTSV() to succeed, Got error: %v", err)
+	}
+	if want := "1\t10\n2\tff\n"; sb.String() != want {
+		t.Errorf("Want %q, Got %q", want, sb.String())
+	}
+
+	got := NewOrderedMap[int, int]()
+	parseHex := func(s string) (int, error) {
+		n, err := strconv.ParseInt(s, 16, 0)
+		return int(n), err
+	}
+	if err := ReadTSV[int, int](strings.NewReader(sb.String()), got, nil, parseHex); err != nil {
+		t.Fatalf("Want ReadTSV() to succeed, Got error: %v", err)
+	}
+	if v, ok := got.Get(2); !ok || v != 255 {
+		t.Errorf("Want Get(2) == (255, true), Got (%d, %t)", v, ok)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	for _, data := range []string{"1,2,3\n", "x,2\n", "1,y\n"} {
+		m := NewOrderedMap[int, int]()
+		if err := ReadCSV[int, int](strings.NewReader(data), m, nil, nil); err == nil {
+			t.Errorf("Want ReadCSV(%q) to fail, Got nil error", data)
+		}
+	}
+}
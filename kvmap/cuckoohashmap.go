@@ -0,0 +1,412 @@
+package kvmap
+
+import (
+	"iter"
+	"math/rand/v2"
+
+	"github.org/jccarlson/collections"
+	"github.org/jccarlson/collections/compare"
+)
+
+// cuckooMaxStash is the number of entries which may be kept in a
+// CuckooHashMap's stash when no slot can be found for them. It bounds the work
+// done by lookups which miss both tables.
+const cuckooMaxStash = 4
+
+// cuckooDefaultLoadFactor is the default LoadFactor of a CuckooHashMap.
+// Insertions into two tables with one slot per bucket start failing with high
+// probability once the tables are half full.
+const cuckooDefaultLoadFactor = 0.45
+
+// hashSlot holds a key-value pair in an open-addressed hash table such as
+// CuckooHashMap's. It satisfies the Entry interface.
+type hashSlot[K, V any] struct {
+	key   K
+	value V
+
+	hashCache uint64
+	used      bool
+}
+
+func (s *hashSlot[K, V]) Key() K {
+	return s.key
+}
+
+func (s *hashSlot[K, V]) Value() V {
+	return s.value
+}
+
+func (s *hashSlot[K, V]) SetValue(v V) {
+	s.value = v
+}
+
+// NewComparableCuckooHashMap returns a pointer to a new CuckooHashMap with
+// comparable keys, and uses the == operator to compare keys.
+func NewComparableCuckooHashMap[K comparable, V any](opts ...Option) *CuckooHashMap[K, V] {
+	return must(NewComparableCuckooHashMapE[K, V](opts...))
+}
+
+// NewComparableCuckooHashMapE is like NewComparableCuckooHashMap, but returns
+// an error wrapping ErrInvalidOption instead of panicking if opts are invalid.
+func NewComparableCuckooHashMapE[K comparable, V any](opts ...Option) (*CuckooHashMap[K, V], error) {
+	return NewCuckooHashMapWithHasherE[K, V](ComparableMapHasher[K](), compare.Equal[K], opts...)
+}
+
+// NewHashableKeyCuckooHashMap returns a pointer to a new CuckooHashMap with
+// HashableKey keys.
+func NewHashableKeyCuckooHashMap[K HashableKey[K], V any](opts ...Option) *CuckooHashMap[K, V] {
+	return must(NewHashableKeyCuckooHashMapE[K, V](opts...))
+}
+
+// NewHashableKeyCuckooHashMapE is like NewHashableKeyCuckooHashMap, but
+// returns an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewHashableKeyCuckooHashMapE[K HashableKey[K], V any](opts ...Option) (*CuckooHashMap[K, V], error) {
+	return NewCuckooHashMapWithHasherE[K, V](HashableKeyMapHasher[K](), compare.EqualableComparator[K], opts...)
+}
+
+// NewCuckooHashMapWithHasher returns a pointer to a new CuckooHashMap which
+// hashes keys with hasher and compares them with equal. equal must be
+// consistent with hasher: keys which are equal must have equal hashes.
+func NewCuckooHashMapWithHasher[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) *CuckooHashMap[K, V] {
+	return must(NewCuckooHashMapWithHasherE[K, V](hasher, equal, opts...))
+}
+
+// NewCuckooHashMapWithHasherE is like NewCuckooHashMapWithHasher, but returns
+// an error wrapping ErrInvalidOption instead of panicking if opts are invalid.
+func NewCuckooHashMapWithHasherE[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) (*CuckooHashMap[K, V], error) {
+	o := kvMapOpts{capacity: defaultCap, loadFactor: cuckooDefaultLoadFactor}
+	if err := applyOptions(&o, opts); err != nil {
+		return nil, err
+	}
+	// Each table holds half the capacity, rounded up to a power of 2.
+	n := minCap
+	for n*2 < o.capacity {
+		n <<= 1
+	}
+	maxCap := o.maxCapacity / 2
+	if o.maxCapacity > 0 {
+		maxCap = max(maxCap, minCap)
+		n = min(n, maxCap)
+	}
+	return &CuckooHashMap[K, V]{
+		comparator:   equal,
+		hasher:       hasher,
+		loadFactor:   o.loadFactor,
+		growthFactor: o.growthFactor,
+		maxCap:       maxCap,
+		cap:          n,
+	}, nil
+}
+
+// CuckooHashMap is a hash map which can store keys and values of any type,
+// using cuckoo hashing: every key has exactly one candidate slot in each of
+// two tables, plus a small stash for keys which fit in neither. Get, Has and
+// Delete therefore examine at most 2+4 slots, giving worst-case O(1) lookups
+// at the cost of slower insertions and a lower load factor than
+// LinkedHashMap. Iteration order is unspecified.
+//
+// CuckooHashMap supports the Capacity() (default: 32), LoadFactor() (default:
+// 0.45), GrowthFactor() (default: 2) and MaxCapacity() (default: unbounded)
+// Options; other Options are ignored. Load factors above 0.5 cause frequent
+// rebuilds of the table. Once a CuckooHashMap reaches its MaxCapacity, keys
+// which fit in neither table are kept in the stash however many there are,
+// so lookups slow down rather than Puts failing below the load factor.
+type CuckooHashMap[K, V any] struct {
+	comparator compare.Comparator[K]
+	hasher     MapHasher[K]
+
+	loadFactor   float32
+	growthFactor float64
+	// maxCap is the number of slots each table may grow to, or 0 if
+	// unbounded.
+	maxCap int
+
+	// tables are the two hash tables, each with cap slots. A key with hash h
+	// may be stored in tables[0] at a slot derived from the low 32 bits of h,
+	// or in tables[1] at a slot derived from the high 32 bits.
+	tables [2][]hashSlot[K, V]
+	stash  []hashSlot[K, V]
+	cap    int
+	size   int
+
+	longestProbe, rehashes, reseeds int
+}
+
+// index returns the slot in tables[t] for keys with hash h. Capacities need
+// not be powers of 2, so each half of h is mapped onto the table by
+// multiplication rather than masking.
+func (m *CuckooHashMap[K, V]) index(t int, h uint64) int {
+	if t == 1 {
+		h >>= 32
+	}
+	return int(uint64(uint32(h)) * uint64(m.cap) >> 32)
+}
+
+// canGrow returns true if m's tables may grow beyond cap slots each.
+func (m *CuckooHashMap[K, V]) canGrow(cap int) bool {
+	return m.maxCap == 0 || cap < m.maxCap
+}
+
+// find returns the slot holding key, or nil if key is not in m.
+func (m *CuckooHashMap[K, V]) find(key *K) *hashSlot[K, V] {
+	if m.size == 0 {
+		return nil
+	}
+	h := m.hasher.Hash(key)
+	for t := range m.tables {
+		if s := &m.tables[t][m.index(t, h)]; s.used && s.hashCache == h && m.comparator(s.key, *key) {
+			return s
+		}
+	}
+	for i := range m.stash {
+		if s := &m.stash[i]; s.hashCache == h && m.comparator(s.key, *key) {
+			return s
+		}
+	}
+	return nil
+}
+
+// maxKicks returns the number of displacements an insertion makes before
+// giving up on the tables.
+func (m *CuckooHashMap[K, V]) maxKicks() int {
+	kicks := 16
+	for n := m.cap; n > 1; n >>= 1 {
+		kicks++
+	}
+	return kicks
+}
+
+// place inserts s into the tables by random walk, displacing existing keys to
+// their alternate slots. If no slot is found, it returns the key left without
+// a slot, which may not be s, and false.
+func (m *CuckooHashMap[K, V]) place(s hashSlot[K, V]) (hashSlot[K, V], bool) {
+	maxKicks := m.maxKicks()
+	for kicks := 0; ; kicks++ {
+		for t := range m.tables {
+			if slot := &m.tables[t][m.index(t, s.hashCache)]; !slot.used {
+				*slot = s
+				m.longestProbe = max(m.longestProbe, kicks)
+				return s, true
+			}
+		}
+		if kicks == maxKicks {
+			return s, false
+		}
+		// Evicting from a random table rather than alternating avoids cycling
+		// between the same few slots.
+		t := rand.IntN(2)
+		slot := &m.tables[t][m.index(t, s.hashCache)]
+		s, *slot = *slot, s
+	}
+}
+
+// insert adds s, which must not already be in m, to the tables or stash,
+// rebuilding the tables if neither has room. It returns ErrMaxCapacity if the
+// tables would exceed the load factor and can't grow.
+func (m *CuckooHashMap[K, V]) insert(s hashSlot[K, V]) error {
+	if float32(m.size+1) > m.loadFactor*float32(2*m.cap) {
+		if !m.canGrow(m.cap) {
+			return ErrMaxCapacity
+		}
+		m.rebuild(grownCapacity(m.cap, m.growthFactor, m.maxCap), false /*reseed=*/)
+	}
+	m.size++
+	s, ok := m.place(s)
+	if ok {
+		return nil
+	}
+	m.stash = append(m.stash, s)
+	if len(m.stash) > cuckooMaxStash && m.canGrow(m.cap) {
+		m.rebuild(m.cap, true /*reseed=*/)
+	}
+	return nil
+}
+
+// rebuild reinserts every entry of m into new tables of cap slots each,
+// optionally with a freshly seeded hasher. If the entries don't fit, the
+// hasher is re-seeded and the capacity grown until they do.
+func (m *CuckooHashMap[K, V]) rebuild(cap int, reseed bool) {
+	old := make([]hashSlot[K, V], 0, m.size)
+	for t := range m.tables {
+		for _, s := range m.tables[t] {
+			if s.used {
+				old = append(old, s)
+			}
+		}
+	}
+	old = append(old, m.stash...)
+
+	for {
+		m.rehashes++
+		if reseed {
+			m.hasher.Reseed()
+			m.reseeds++
+			for i := range old {
+				old[i].hashCache = m.hasher.Hash(&old[i].key)
+			}
+		}
+		m.cap, m.longestProbe = cap, 0
+		m.tables = [2][]hashSlot[K, V]{make([]hashSlot[K, V], cap), make([]hashSlot[K, V], cap)}
+		m.stash = nil
+		if m.placeAll(old) {
+			return
+		}
+		cap = grownCapacity(cap, m.growthFactor, m.maxCap)
+		reseed = true
+	}
+}
+
+// placeAll inserts entries into m's empty tables and stash, returning false
+// if they don't all fit. The stash is unbounded once the tables can't grow.
+func (m *CuckooHashMap[K, V]) placeAll(entries []hashSlot[K, V]) bool {
+	for _, s := range entries {
+		s, ok := m.place(s)
+		if ok {
+			continue
+		}
+		if len(m.stash) >= cuckooMaxStash && m.canGrow(m.cap) {
+			return false
+		}
+		m.stash = append(m.stash, s)
+	}
+	return true
+}
+
+// Put maps key to val. It panics with ErrMaxCapacity if key is new and m
+// can't grow to make room for it.
+func (m *CuckooHashMap[K, V]) Put(key K, val V) {
+	if err := m.TryPut(key, val); err != nil {
+		panic(err)
+	}
+}
+
+// TryPut is like Put, but returns ErrMaxCapacity rather than panicking, in
+// which case m is unchanged.
+func (m *CuckooHashMap[K, V]) TryPut(key K, val V) error {
+	if s := m.find(&key); s != nil {
+		s.value = val
+		return nil
+	}
+	if m.tables[0] == nil {
+		m.tables = [2][]hashSlot[K, V]{make([]hashSlot[K, V], m.cap), make([]hashSlot[K, V], m.cap)}
+	}
+	return m.insert(hashSlot[K, V]{key: key, value: val, hashCache: m.hasher.Hash(&key), used: true})
+}
+
+func (m *CuckooHashMap[K, V]) Get(key K) (val V, ok bool) {
+	if s := m.find(&key); s != nil {
+		return s.value, true
+	}
+	return
+}
+
+func (m *CuckooHashMap[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *CuckooHashMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	s := m.find(&key)
+	if s == nil {
+		return
+	}
+	val, ok = s.value, true
+	*s = hashSlot[K, V]{}
+	m.size--
+	for i := range m.stash {
+		if !m.stash[i].used {
+			m.stash = append(m.stash[:i], m.stash[i+1:]...)
+			break
+		}
+	}
+	// A slot has been freed, which may make room for a stashed entry.
+	for i := 0; i < len(m.stash); i++ {
+		if m.placeExact(m.stash[i]) {
+			m.stash = append(m.stash[:i], m.stash[i+1:]...)
+			i--
+		}
+	}
+	return
+}
+
+// placeExact inserts s into one of its own slots, without displacing other
+// entries, returning false if both are occupied.
+func (m *CuckooHashMap[K, V]) placeExact(s hashSlot[K, V]) bool {
+	for t := range m.tables {
+		if slot := &m.tables[t][m.index(t, s.hashCache)]; !slot.used {
+			*slot = s
+			return true
+		}
+	}
+	return false
+}
+
+func (m *CuckooHashMap[K, V]) Has(key K) bool {
+	return m.find(&key) != nil
+}
+
+func (m *CuckooHashMap[K, V]) Len() int {
+	return m.size
+}
+
+// Stats returns a snapshot of m's hash table statistics. Capacity counts the
+// slots of both tables, and LongestProbe is the longest chain of
+// displacements made by an insertion since the tables were last rebuilt.
+func (m *CuckooHashMap[K, V]) Stats() HashMapStats {
+	return HashMapStats{
+		Len:          m.size,
+		Capacity:     2 * m.cap,
+		LongestProbe: m.longestProbe,
+		Rehashes:     m.rehashes,
+		Reseeds:      m.reseeds,
+	}
+}
+
+func (m *CuckooHashMap[K, V]) String() string {
+	return IterableMapToString[K, V](m)
+}
+
+func (m *CuckooHashMap[K, V]) GoString() string {
+	return IterableMapToGoString[K, V](m)
+}
+
+// Iterator returns an Iterator over the entries of m, in unspecified order.
+// m must not be modified during iteration, except through Entry.SetValue.
+func (m *CuckooHashMap[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	return &hashSlotIterator[K, V]{tables: [][]hashSlot[K, V]{m.tables[0], m.tables[1], m.stash}}
+}
+
+// All returns an iter.Seq2 over the keys and values of m, in unspecified
+// order. m must not be modified during iteration.
+func (m *CuckooHashMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, tbl := range [][]hashSlot[K, V]{m.tables[0], m.tables[1], m.stash} {
+			for i := range tbl {
+				if s := &tbl[i]; s.used && !yield(s.key, s.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// hashSlotIterator iterates over the used slots of one or more tables.
+type hashSlotIterator[K, V any] struct {
+	tables [][]hashSlot[K, V]
+	// i is the index of the next slot to visit in tables[0].
+	i int
+}
+
+func (i *hashSlotIterator[K, V]) Next() (entry Entry[K, V], ok bool) {
+	for ; len(i.tables) > 0; i.tables, i.i = i.tables[1:], 0 {
+		for tbl := i.tables[0]; i.i < len(tbl); i.i++ {
+			if s := &tbl[i.i]; s.used {
+				i.i++
+				return s, true
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,120 @@
+package kvmap
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestCuckooHashMapManyKeys(t *testing.T) {
+	m := NewComparableCuckooHashMap[int, int]()
+	for i := 0; i < 10000; i++ {
+		m.Put(i, i*2)
+	}
+	for i := 0; i < 10000; i += 2 {
+		m.Delete(i)
+	}
+	if m.Len() != 5000 {
+		t.Errorf("Want Len() == 5000, Got %d", m.Len())
+	}
+	for i := 0; i < 10000; i++ {
+		v, ok := m.Get(i)
+		if want := i%2 == 1; ok != want || (ok && v != i*2) {
+			t.Errorf("Want Get(%d) == (%d, %t), Got (%d, %t)", i, i*2, want, v, ok)
+		}
+	}
+
+	n := 0
+	for k, v := range m.All() {
+		if k%2 != 1 || v != k*2 {
+			t.Errorf("Want only odd keys with doubled values, Got %d:%d", k, v)
+		}
+		n++
+	}
+	if n != 5000 {
+		t.Errorf("Want All() to yield 5000 entries, Got %d", n)
+	}
+}
+
+func TestCuckooHashMapStashAndReseed(t *testing.T) {
+	m := NewComparableCuckooHashMap[int, int](Capacity(64))
+	// Until the first re-seed, every key hashes identically, so only two keys
+	// fit in the tables and the rest must be stashed.
+	m.hasher = CustomMapHasher[int](func(k *int) []byte {
+		if m.reseeds == 0 {
+			return []byte{0}
+		}
+		return binary.LittleEndian.AppendUint64(nil, uint64(*k))
+	})
+
+	for i := 0; i < 2+cuckooMaxStash; i++ {
+		m.Put(i, i)
+	}
+	if len(m.stash) != cuckooMaxStash || m.Stats().Reseeds != 0 {
+		t.Errorf("Want a full stash and no reseeds, Got stash of %d and %d reseeds", len(m.stash), m.Stats().Reseeds)
+	}
+	// Deleting a key in the tables frees a slot for a stashed key.
+	m.Delete(m.tables[0][0].key)
+	if len(m.stash) != cuckooMaxStash-1 {
+		t.Errorf("Want a stashed key moved into the freed slot, Got stash of %d", len(m.stash))
+	}
+
+	for i := 10; i < 20; i++ {
+		m.Put(i, i)
+	}
+	if got := m.Stats().Reseeds; got != 1 {
+		t.Errorf("Want Stats().Reseeds == 1, Got %d", got)
+	}
+	if m.Len() != 15 {
+		t.Errorf("Want Len() == 15, Got %d", m.Len())
+	}
+	for k := range m.All() {
+		if v, ok := m.Get(k); !ok || v != k {
+			t.Errorf("Want Get(%d) == (%[1]d, true), Got (%d, %t)", k, v, ok)
+		}
+	}
+}
+
+func benchmarkMapGet(b *testing.B, m Interface[int, int]) {
+	const n = 1 << 16
+	for i := 0; i < n; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i & (2*n - 1))
+	}
+}
+
+func benchmarkMapPut(b *testing.B, newMap func() Interface[int, int]) {
+	const n = 1 << 12
+	for i := 0; i < b.N; i += n {
+		m := newMap()
+		for j := 0; j < n; j++ {
+			m.Put(j, j)
+		}
+	}
+}
+
+func BenchmarkCuckooHashMapGet(b *testing.B) {
+	benchmarkMapGet(b, NewComparableCuckooHashMap[int, int]())
+}
+
+func BenchmarkLinkedHashMapGet(b *testing.B) {
+	benchmarkMapGet(b, NewComparableLinkedHashMap[int, int]())
+}
+
+func BenchmarkMapWrapperGet(b *testing.B) {
+	benchmarkMapGet(b, NewMapWrapper[int, int]())
+}
+
+func BenchmarkCuckooHashMapPut(b *testing.B) {
+	benchmarkMapPut(b, func() Interface[int, int] { return NewComparableCuckooHashMap[int, int]() })
+}
+
+func BenchmarkLinkedHashMapPut(b *testing.B) {
+	benchmarkMapPut(b, func() Interface[int, int] { return NewComparableLinkedHashMap[int, int]() })
+}
+
+func BenchmarkMapWrapperPut(b *testing.B) {
+	benchmarkMapPut(b, func() Interface[int, int] { return NewMapWrapper[int, int]() })
+}
@@ -0,0 +1,132 @@
+package kvmap
+
+import (
+	"github.org/jccarlson/collections/compare"
+)
+
+// ValueChange describes how the value of a key differs between two maps. For
+// added keys only New is set, and for removed keys only Old is set.
+type ValueChange[K, V any] struct {
+	Key      K
+	Old, New V
+}
+
+// MapDiff is the difference between two maps, as computed by Diff.
+type MapDiff[K, V any] struct {
+	// Added holds the entries whose keys are only in the new map.
+	Added []ValueChange[K, V]
+	// Removed holds the entries whose keys are only in the old map.
+	Removed []ValueChange[K, V]
+	// Changed holds the keys in both maps whose values are not equal.
+	Changed []ValueChange[K, V]
+}
+
+// IsEmpty returns true if d describes no differences.
+func (d MapDiff[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff computes the entries added, removed and changed between old and new,
+// using valueEq to determine whether the values of a key present in both maps
+// have changed. Each list is in the iteration order of the map it is taken
+// from.
+func Diff[K, V any](old, new IterableMap[K, V], valueEq compare.Comparator[V]) MapDiff[K, V] {
+	var d MapDiff[K, V]
+	ForEach(old, func(key K, oldVal V) {
+		newVal, ok := new.Get(key)
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, ValueChange[K, V]{Key: key, Old: oldVal})
+		case !valueEq(oldVal, newVal):
+			d.Changed = append(d.Changed, ValueChange[K, V]{Key: key, Old: oldVal, New: newVal})
+		}
+	})
+	ForEach(new, func(key K, newVal V) {
+		if !old.Has(key) {
+			d.Added = append(d.Added, ValueChange[K, V]{Key: key, New: newVal})
+		}
+	})
+	return d
+}
+
+// ApplyDiff applies d to m, deleting the Removed keys and putting the New
+// values of the Added and Changed keys. Applying Diff(old, new, eq) to a map
+// equal to old makes it equal to new.
+func ApplyDiff[K, V any](m Interface[K, V], d MapDiff[K, V]) {
+	for _, c := range d.Removed {
+		m.Delete(c.Key)
+	}
+	for _, c := range d.Added {
+		m.Put(c.Key, c.New)
+	}
+	for _, c := range d.Changed {
+		m.Put(c.Key, c.New)
+	}
+}
+
+// MergeValue is the value of a key in one of the maps of a three-way merge.
+// Present is false if the key is not in that map.
+type MergeValue[V any] struct {
+	Value   V
+	Present bool
+}
+
+// A ConflictResolver returns the merged value of key when mine and theirs
+// have both changed it from base in different ways. Returning a MergeValue
+// with Present == false deletes the key.
+type ConflictResolver[K, V any] func(key K, base, mine, theirs MergeValue[V]) MergeValue[V]
+
+// Merge3 performs a three-way merge of the changes made to base in mine and
+// in theirs, and returns the MapDiff which must be applied to mine to produce
+// the merged map. Keys changed in only one of mine or theirs take that
+// change, keys changed identically in both keep it, and keys changed
+// differently in both are decided by resolve.
+//
+// ApplyDiff(mine, Merge3(base, mine, theirs, valueEq, resolve)) merges the
+// changes from theirs into mine.
+func Merge3[K, V any](base, mine, theirs IterableMap[K, V], valueEq compare.Comparator[V], resolve ConflictResolver[K, V]) MapDiff[K, V] {
+	lookup := func(m IterableMap[K, V], key K) MergeValue[V] {
+		v, ok := m.Get(key)
+		return MergeValue[V]{v, ok}
+	}
+	eq := func(a, b MergeValue[V]) bool {
+		return a.Present == b.Present && (!a.Present || valueEq(a.Value, b.Value))
+	}
+
+	var d MapDiff[K, V]
+	merge := func(key K, m MergeValue[V]) {
+		b, t := lookup(base, key), lookup(theirs, key)
+		var merged MergeValue[V]
+		switch {
+		case eq(m, t), eq(b, t):
+			// Both sides agree, or only mine changed.
+			return
+		case eq(b, m):
+			// Only theirs changed.
+			merged = t
+		default:
+			merged = resolve(key, b, m, t)
+		}
+
+		switch {
+		case eq(merged, m):
+		case !merged.Present:
+			d.Removed = append(d.Removed, ValueChange[K, V]{Key: key, Old: m.Value})
+		case !m.Present:
+			d.Added = append(d.Added, ValueChange[K, V]{Key: key, New: merged.Value})
+		default:
+			d.Changed = append(d.Changed, ValueChange[K, V]{Key: key, Old: m.Value, New: merged.Value})
+		}
+	}
+
+	ForEach(mine, func(key K, val V) {
+		merge(key, MergeValue[V]{val, true})
+	})
+	// Keys which are in neither mine nor theirs need no changes to mine.
+	ForEach(theirs, func(key K, _ V) {
+		if !mine.Has(key) {
+			merge(key, MergeValue[V]{})
+		}
+	})
+	return d
+}
@@ -0,0 +1,90 @@
+package kvmap
+
+import (
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+func TestDiff(t *testing.T) {
+	old := NewOrderedMap[string, int]()
+	new := NewOrderedMap[string, int]()
+	for k, v := range map[string]int{"a": 1, "b": 2, "c": 3, "d": 4} {
+		old.Put(k, v)
+	}
+	for k, v := range map[string]int{"b": 2, "c": 30, "d": 40, "e": 5} {
+		new.Put(k, v)
+	}
+
+	d := Diff[string, int](old, new, compare.Equal[int])
+	want := MapDiff[string, int]{
+		Added:   []ValueChange[string, int]{{Key: "e", New: 5}},
+		Removed: []ValueChange[string, int]{{Key: "a", Old: 1}},
+		Changed: []ValueChange[string, int]{{Key: "c", Old: 3, New: 30}, {Key: "d", Old: 4, New: 40}},
+	}
+	for _, tc := range []struct {
+		name      string
+		got, want []ValueChange[string, int]
+	}{
+		{"Added", d.Added, want.Added},
+		{"Removed", d.Removed, want.Removed},
+		{"Changed", d.Changed, want.Changed},
+	} {
+		if len(tc.got) != len(tc.want) {
+			t.Errorf("Want %s == %v, Got %v", tc.name, tc.want, tc.got)
+			continue
+		}
+		for i := range tc.got {
+			if tc.got[i] != tc.want[i] {
+				t.Errorf("Want %s == %v, Got %v", tc.name, tc.want, tc.got)
+				break
+			}
+		}
+	}
+
+	if d := Diff[string, int](old, old, compare.Equal[int]); !d.IsEmpty() {
+		t.Errorf("Want Diff(old, old).IsEmpty() == true, Got %+v", d)
+	}
+}
+
+func TestApplyDiff(t *testing.T) {
+	old := NewMapWrapper[string, int]()
+	new := NewMapWrapper[string, int]()
+	old.Put("a", 1)
+	old.Put("b", 2)
+	new.Put("b", 20)
+	new.Put("c", 3)
+
+	ApplyDiff[string, int](old, Diff[string, int](old, new, compare.Equal[int]))
+	if d := Diff[string, int](old, new, compare.Equal[int]); !d.IsEmpty() {
+		t.Errorf("Want old == new after ApplyDiff, Got remaining diff %+v", d)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	fill := func(kvs map[string]int) MapWrapper[string, int] {
+		m := NewMapWrapper[string, int]()
+		for k, v := range kvs {
+			m.Put(k, v)
+		}
+		return m
+	}
+	base := fill(map[string]int{"same": 1, "mine": 1, "theirs": 1, "both": 1, "conflict": 1, "deleted": 1, "gone": 1})
+	mine := fill(map[string]int{"same": 1, "mine": 2, "theirs": 1, "both": 2, "conflict": 2, "deleted": 1, "new": 1})
+	theirs := fill(map[string]int{"same": 1, "mine": 1, "theirs": 3, "both": 2, "conflict": 3, "added": 3})
+
+	var conflicts []string
+	d := Merge3[string, int](base, mine, theirs, compare.Equal[int], func(key string, b, m, t MergeValue[int]) MergeValue[int] {
+		conflicts = append(conflicts, key)
+		return MergeValue[int]{m.Value + t.Value, true}
+	})
+	ApplyDiff[string, int](mine, d)
+
+	want := fill(map[string]int{"same": 1, "mine": 2, "theirs": 3, "both": 2, "conflict": 5, "new": 1, "added": 3})
+	if d := Diff[string, int](mine, want, compare.Equal[int]); !d.IsEmpty() {
+		t.Errorf("Want merged map %v, Got %v (diff: %+v)", want, mine, d)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "conflict" {
+		t.Errorf("Want resolver called for [conflict], Got %v", conflicts)
+	}
+}
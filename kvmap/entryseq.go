@@ -0,0 +1,36 @@
+package kvmap
+
+import "iter"
+
+// EntrySeq returns an iter.Seq over the entries of m, in m's iteration order.
+// It works for any IterableMap, adapting m's Iterator unless m has an
+// Entries method returning an iter.Seq[Entry[K, V]]. m must not be modified
+// during iteration, except through Entry.SetValue.
+func EntrySeq[K, V any](m IterableMap[K, V]) iter.Seq[Entry[K, V]] {
+	if es, ok := m.(interface{ Entries() iter.Seq[Entry[K, V]] }); ok {
+		return es.Entries()
+	}
+	return func(yield func(Entry[K, V]) bool) {
+		it := m.Iterator()
+		// Iterators built on iter.Pull must be closed to release their
+		// goroutine.
+		if c, ok := it.(interface{ Close() }); ok {
+			defer c.Close()
+		}
+		for e, ok := it.Next(); ok; e, ok = it.Next() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// SetValues replaces the value of every entry of m with f(key, value), in
+// place through Entry.SetValue, without looking up any key or changing m's
+// iteration order. It panics if m's entries don't support SetValue, as for a
+// MapSnapshot.
+func SetValues[K, V any](m IterableMap[K, V], f func(key K, val V) V) {
+	for e := range EntrySeq(m) {
+		e.SetValue(f(e.Key(), e.Value()))
+	}
+}
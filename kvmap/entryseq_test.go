@@ -0,0 +1,39 @@
+package kvmap
+
+import (
+	"testing"
+)
+
+func TestEntrySeqAndSetValues(t *testing.T) {
+	for name, m := range map[string]IterableMap[int, int]{
+		"OrderedMap":       NewOrderedMap[int, int](),
+		"LinkedHashMap":    NewComparableLinkedHashMap[int, int](),
+		"HopscotchHashMap": NewComparableHopscotchHashMap[int, int](),
+		"CuckooHashMap":    NewComparableCuckooHashMap[int, int](),
+		"MapWrapper":       NewMapWrapper[int, int](),
+		"IntMap":           NewIntMap[int](),
+	} {
+		for i := 0; i < 50; i++ {
+			m.Put(i, i)
+		}
+		SetValues(m, func(k, v int) int { return k + v*10 })
+		for i := 0; i < 50; i++ {
+			if v, ok := m.Get(i); !ok || v != i*11 {
+				t.Errorf("%s: Want Get(%d) == (%d, true), Got (%d, %t)", name, i, i*11, v, ok)
+			}
+		}
+
+		n := 0
+		for e := range EntrySeq(m) {
+			if e.Value() != e.Key()*11 {
+				t.Errorf("%s: Want entry %d:%d, Got %d:%d", name, e.Key(), e.Key()*11, e.Key(), e.Value())
+			}
+			if n++; n == 10 {
+				break
+			}
+		}
+		if n != 10 {
+			t.Errorf("%s: Want iteration stopped after 10 entries, Got %d", name, n)
+		}
+	}
+}
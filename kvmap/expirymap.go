@@ -0,0 +1,131 @@
+package kvmap
+
+import (
+	"time"
+
+	"github.org/jccarlson/collections"
+)
+
+type expiryMapEntry[K, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+	// handle is the entry's position in the expiry heap, or nil if the entry
+	// never expires.
+	handle *collections.HeapHandle[*expiryMapEntry[K, V]]
+}
+
+// ExpiryMap is a map whose entries can carry an expiry time, combining a hash
+// map for lookup by key with a heap ordered by expiry. Expired entries are
+// not removed automatically; instead PeekNext and PopExpired find the entry
+// which expires soonest in O(1) and O(log n) time, which suits timer queues
+// and session stores that expire entries from a single loop.
+type ExpiryMap[K comparable, V any] struct {
+	entries map[K]*expiryMapEntry[K, V]
+	heap    *collections.BinaryHeap[*expiryMapEntry[K, V]]
+}
+
+// NewExpiryMap returns a pointer to a new, empty ExpiryMap.
+func NewExpiryMap[K comparable, V any]() *ExpiryMap[K, V] {
+	return &ExpiryMap[K, V]{
+		entries: make(map[K]*expiryMapEntry[K, V]),
+		heap: collections.NewBinaryHeap(func(e1, e2 *expiryMapEntry[K, V]) bool {
+			return e1.expires.Before(e2.expires)
+		}),
+	}
+}
+
+// PutWithExpiry sets the value for key, which expires at expires. A zero
+// expires means the entry never expires.
+func (m *ExpiryMap[K, V]) PutWithExpiry(key K, val V, expires time.Time) {
+	e, ok := m.entries[key]
+	if !ok {
+		e = &expiryMapEntry[K, V]{key: key}
+		m.entries[key] = e
+	}
+	e.value, e.expires = val, expires
+	switch {
+	case expires.IsZero() && e.handle != nil:
+		m.heap.Remove(e.handle)
+		e.handle = nil
+	case expires.IsZero():
+	case e.handle != nil:
+		m.heap.Fix(e.handle)
+	default:
+		e.handle = m.heap.Push(e)
+	}
+}
+
+// Put sets the value for key, which never expires.
+func (m *ExpiryMap[K, V]) Put(key K, val V) {
+	m.PutWithExpiry(key, val, time.Time{})
+}
+
+// Get returns the value for key, even if it has expired.
+func (m *ExpiryMap[K, V]) Get(key K) (val V, ok bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	return e.value, true
+}
+
+// Expiry returns the expiry time of key, or the zero Time if it never
+// expires. ok is false if key is not in m.
+func (m *ExpiryMap[K, V]) Expiry(key K) (expires time.Time, ok bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	return e.expires, true
+}
+
+func (m *ExpiryMap[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *ExpiryMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	delete(m.entries, key)
+	if e.handle != nil {
+		m.heap.Remove(e.handle)
+	}
+	return e.value, true
+}
+
+func (m *ExpiryMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+func (m *ExpiryMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// PeekNext returns the key of the entry which expires soonest, and its expiry
+// time. ok is false if no entry in m expires.
+func (m *ExpiryMap[K, V]) PeekNext() (key K, expires time.Time, ok bool) {
+	e, ok := m.heap.Peek()
+	if !ok {
+		return
+	}
+	return e.key, e.expires, true
+}
+
+// PopExpired removes and returns the entry which expires soonest, if it
+// expired at or before now. ok is false if no entry has expired; callers can
+// call PopExpired repeatedly to remove every expired entry.
+func (m *ExpiryMap[K, V]) PopExpired(now time.Time) (key K, val V, ok bool) {
+	e, ok := m.heap.Peek()
+	if !ok || e.expires.After(now) {
+		return key, val, false
+	}
+	m.heap.Pop()
+	delete(m.entries, e.key)
+	return e.key, e.value, true
+}
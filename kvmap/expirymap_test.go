@@ -0,0 +1,65 @@
+package kvmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiryMap(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+
+	m := NewExpiryMap[string, int]()
+	m.PutWithExpiry("a", 1, at(30))
+	m.PutWithExpiry("b", 2, at(10))
+	m.PutWithExpiry("c", 3, at(20))
+	m.Put("forever", 4)
+
+	if k, exp, ok := m.PeekNext(); !ok || k != "b" || !exp.Equal(at(10)) {
+		t.Errorf("Want PeekNext() == (b, +10s, true), Got (%s, %v, %t)", k, exp, ok)
+	}
+
+	// Extend b, make c permanent, and delete a.
+	m.PutWithExpiry("b", 20, at(40))
+	m.Put("c", 30)
+	m.Delete("a")
+	if exp, ok := m.Expiry("c"); !ok || !exp.IsZero() {
+		t.Errorf("Want Expiry(c) == (zero, true), Got (%v, %t)", exp, ok)
+	}
+
+	if _, _, ok := m.PopExpired(at(39)); ok {
+		t.Errorf("Want nothing expired at +39s, Got an entry")
+	}
+	if k, v, ok := m.PopExpired(at(40)); !ok || k != "b" || v != 20 {
+		t.Errorf("Want PopExpired(+40s) == (b, 20, true), Got (%s, %d, %t)", k, v, ok)
+	}
+	if _, _, ok := m.PeekNext(); ok {
+		t.Errorf("Want PeekNext() == (_, _, false) with no expiring entries, Got true")
+	}
+	if m.Len() != 2 || !m.Has("c") || !m.Has("forever") {
+		t.Errorf("Want c and forever to remain, Got Len() == %d", m.Len())
+	}
+}
+
+func TestExpiryMapPopsInOrder(t *testing.T) {
+	t0 := time.Now()
+	m := NewExpiryMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m.PutWithExpiry(i, i, t0.Add(time.Duration((i*37)%100)*time.Millisecond))
+	}
+	var last time.Time
+	for n := 0; ; n++ {
+		k, _, ok := m.PopExpired(t0.Add(time.Second))
+		if !ok {
+			if n != 100 {
+				t.Errorf("Want 100 expired entries, Got %d", n)
+			}
+			break
+		}
+		exp := t0.Add(time.Duration((k*37)%100) * time.Millisecond)
+		if exp.Before(last) {
+			t.Fatalf("Want entries popped in expiry order, Got %v after %v", exp, last)
+		}
+		last = exp
+	}
+}
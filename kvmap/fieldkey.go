@@ -0,0 +1,40 @@
+package kvmap
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// HashableKeyFromFields returns a MapHasher and an equality Comparator for
+// keys of type T which consider only the selected fields. Each function in
+// fields returns a byte-slice representation of one field of a key; two keys
+// are equal if every field function returns equal bytes for both. This lets
+// a map key on a subset of a struct's fields without defining a HashableKey
+// type. The results can be passed to NewLinkedHashMapWithHasher.
+func HashableKeyFromFields[T any](fields ...func(*T) []byte) (MapHasher[T], compare.Comparator[T]) {
+	if len(fields) == 0 {
+		panic("HashableKeyFromFields requires at least one field")
+	}
+	hasher := CustomMapHasher(func(key *T) []byte {
+		var b []byte
+		for _, f := range fields {
+			fb := f(key)
+			// Length-prefix each field, so that e.g. "ab","c" and "a","bc"
+			// hash differently.
+			b = binary.AppendUvarint(b, uint64(len(fb)))
+			b = append(b, fb...)
+		}
+		return b
+	})
+	equal := func(k1, k2 T) bool {
+		for _, f := range fields {
+			if !bytes.Equal(f(&k1), f(&k2)) {
+				return false
+			}
+		}
+		return true
+	}
+	return hasher, equal
+}
@@ -0,0 +1,38 @@
+package kvmap
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+type fieldKeyTestUser struct {
+	Org, Name string
+	ID        int
+	// LastSeen is not part of the key.
+	LastSeen int64
+}
+
+func TestHashableKeyFromFields(t *testing.T) {
+	hasher, equal := HashableKeyFromFields(
+		func(u *fieldKeyTestUser) []byte { return []byte(u.Org) },
+		func(u *fieldKeyTestUser) []byte { return []byte(u.Name) },
+		func(u *fieldKeyTestUser) []byte { return binary.LittleEndian.AppendUint64(nil, uint64(u.ID)) },
+	)
+	m := NewLinkedHashMapWithHasher[fieldKeyTestUser, string](hasher, equal)
+
+	m.Put(fieldKeyTestUser{"acme", "ann", 1, 100}, "first")
+	m.Put(fieldKeyTestUser{"acme", "ann", 1, 200}, "second")
+	m.Put(fieldKeyTestUser{"acm", "eann", 1, 100}, "other")
+
+	if m.Len() != 2 {
+		t.Errorf("Want Len() == 2 as LastSeen is ignored, Got %d", m.Len())
+	}
+	if v, ok := m.Get(fieldKeyTestUser{Org: "acme", Name: "ann", ID: 1}); !ok || v != "second" {
+		t.Errorf(`Want Get() == ("second", true), Got (%q, %t)`, v, ok)
+	}
+
+	a, b := fieldKeyTestUser{Org: "ab", Name: "c"}, fieldKeyTestUser{Org: "a", Name: "bc"}
+	if equal(a, b) || hasher.Hash(&a) == hasher.Hash(&b) {
+		t.Errorf("Want field boundaries to distinguish %v and %v", a, b)
+	}
+}
@@ -0,0 +1,66 @@
+package kvmap
+
+import (
+	"iter"
+	"math/bits"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// mix64 is the finalizer of SplitMix64, which spreads every bit of h over the
+// result, so that sums of mixed hashes don't cancel out.
+func mix64(h uint64) uint64 {
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// HashOf returns a hash of the entries of m, using kh and vh to hash keys and
+// values. It doesn't depend on the iteration order of m, so maps with equal
+// entries have equal hashes whatever their type, as long as they are hashed
+// with the same MapHashers. Like the MapHashers' hashes, it is only
+// consistent within a process.
+func HashOf[K, V any](m IterableMap[K, V], kh MapHasher[K], vh MapHasher[V]) uint64 {
+	var sum uint64
+	ForEach(m, func(key K, val V) {
+		sum += mix64(kh.Hash(&key) ^ bits.RotateLeft64(vh.Hash(&val), 32))
+	})
+	return mix64(sum ^ uint64(m.Len()))
+}
+
+// HashOfSet returns a hash of the elements of s, using h to hash them, which
+// doesn't depend on the order of s. s should not yield equal elements more
+// than once, as for a set; if it does, each counts separately.
+func HashOfSet[E any](s iter.Seq[E], h MapHasher[E]) uint64 {
+	var sum, n uint64
+	for e := range s {
+		sum += mix64(h.Hash(&e))
+		n++
+	}
+	return mix64(sum ^ n)
+}
+
+// HashOfList returns a hash of the elements of s in order, using h to hash
+// them.
+func HashOfList[E any](s iter.Seq[E], h MapHasher[E]) uint64 {
+	var acc, n uint64
+	for e := range s {
+		acc = mix64(acc ^ h.Hash(&e))
+		n++
+	}
+	return mix64(acc ^ n)
+}
+
+// Equal returns true if m1 and m2 have the same keys, and valueEq returns true
+// for the values of each key in both. The maps may be of different types.
+func Equal[K, V any](m1, m2 IterableMap[K, V], valueEq compare.Comparator[V]) bool {
+	if m1.Len() != m2.Len() {
+		return false
+	}
+	for e := range EntrySeq(m1) {
+		if v, ok := m2.Get(e.Key()); !ok || !valueEq(e.Value(), v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package kvmap
+
+import (
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections"
+	"github.org/jccarlson/collections/compare"
+)
+
+// closeCountingMap is an IterableMap whose Iterators have a Close method, and
+// which counts the Iterators not yet closed in open.
+type closeCountingMap[K, V any] struct {
+	IterableMap[K, V]
+	open *int
+}
+
+func (m closeCountingMap[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	*m.open++
+	return &closeCountingIterator[K, V]{m.IterableMap.Iterator(), m.open}
+}
+
+type closeCountingIterator[K, V any] struct {
+	collections.Iterator[Entry[K, V]]
+	open *int
+}
+
+func (i *closeCountingIterator[K, V]) Close() {
+	*i.open--
+}
+
+func TestHashOf(t *testing.T) {
+	kh, vh := ComparableMapHasher[string](), ComparableMapHasher[int]()
+	m1 := NewOrderedMap[string, int]()
+	m2 := NewComparableLinkedHashMap[string, int]()
+	for i, k := range []string{"a", "b", "c", "d"} {
+		m1.Put(k, i)
+	}
+	for i, k := range []string{"d", "c", "b", "a"} {
+		m2.Put(k, 3-i)
+	}
+	if h1, h2 := HashOf[string, int](m1, kh, vh), HashOf[string, int](m2, kh, vh); h1 != h2 {
+		t.Errorf("Want equal hashes of equal maps, Got %x and %x", h1, h2)
+	}
+	if !Equal[string, int](m1, m2, compare.Equal[int]) {
+		t.Errorf("Want Equal() == true for equal maps, Got false")
+	}
+
+	h := HashOf[string, int](m1, kh, vh)
+	m2.Put("a", 10)
+	if HashOf[string, int](m2, kh, vh) == h {
+		t.Errorf("Want hash to change with a value, Got %x", h)
+	}
+	if Equal[string, int](m1, m2, compare.Equal[int]) {
+		t.Errorf("Want Equal() == false after changing a value, Got true")
+	}
+	m2.Put("a", 0)
+	m2.Put("e", 4)
+	if HashOf[string, int](m2, kh, vh) == h {
+		t.Errorf("Want hash to change with an added key, Got %x", h)
+	}
+}
+
+func TestEqualClosesIterator(t *testing.T) {
+	var open int
+	m1 := closeCountingMap[int, int]{NewComparableLinkedHashMap[int, int](), &open}
+	m2 := NewComparableLinkedHashMap[int, int]()
+	for i := range 5 {
+		m1.Put(i, i)
+		m2.Put(i, -i)
+	}
+	if Equal[int, int](m1, m2, compare.Equal[int]) {
+		t.Errorf("Want Equal() == false for different values, Got true")
+	}
+	if open != 0 {
+		t.Errorf("Want Equal() to close its Iterator, Got %d open", open)
+	}
+}
+
+func TestHashOfSetAndList(t *testing.T) {
+	h := ComparableMapHasher[int]()
+	s1, s2 := []int{1, 2, 3}, []int{3, 1, 2}
+	if HashOfSet(slices.Values(s1), h) != HashOfSet(slices.Values(s2), h) {
+		t.Errorf("Want HashOfSet() independent of order, Got different hashes")
+	}
+	if HashOfList(slices.Values(s1), h) == HashOfList(slices.Values(s2), h) {
+		t.Errorf("Want HashOfList() to depend on order, Got equal hashes")
+	}
+	if HashOfList(slices.Values(s1), h) != HashOfList(slices.Values([]int{1, 2, 3}), h) {
+		t.Errorf("Want equal HashOfList() for equal lists, Got different hashes")
+	}
+	if HashOfSet(slices.Values([]int{1, 2}), h) == HashOfSet(slices.Values(s1), h) {
+		t.Errorf("Want HashOfSet() to change with an element, Got equal hashes")
+	}
+}
@@ -0,0 +1,368 @@
+package kvmap
+
+import (
+	"iter"
+	"math/bits"
+
+	"github.org/jccarlson/collections"
+	"github.org/jccarlson/collections/compare"
+)
+
+// hopscotchNeighborhood is the number of consecutive slots, starting at a
+// key's home slot, in which the key must be stored.
+const hopscotchNeighborhood = 32
+
+// hopscotchDefaultLoadFactor is the default LoadFactor of a
+// HopscotchHashMap.
+const hopscotchDefaultLoadFactor = 0.9
+
+// hopscotchMaxStash is the number of keys a HopscotchHashMap keeps in its
+// overflow when they can't be moved into their neighborhood, before growing
+// the table. At high load factors a few unlucky neighborhoods fill up long
+// before the table does, and stashing their keys is much cheaper than
+// doubling it.
+const hopscotchMaxStash = 32
+
+// NewComparableHopscotchHashMap returns a pointer to a new HopscotchHashMap
+// with comparable keys, and uses the == operator to compare keys.
+func NewComparableHopscotchHashMap[K comparable, V any](opts ...Option) *HopscotchHashMap[K, V] {
+	return must(NewComparableHopscotchHashMapE[K, V](opts...))
+}
+
+// NewComparableHopscotchHashMapE is like NewComparableHopscotchHashMap, but
+// returns an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewComparableHopscotchHashMapE[K comparable, V any](opts ...Option) (*HopscotchHashMap[K, V], error) {
+	return NewHopscotchHashMapWithHasherE[K, V](ComparableMapHasher[K](), compare.Equal[K], opts...)
+}
+
+// NewHashableKeyHopscotchHashMap returns a pointer to a new HopscotchHashMap
+// with HashableKey keys.
+func NewHashableKeyHopscotchHashMap[K HashableKey[K], V any](opts ...Option) *HopscotchHashMap[K, V] {
+	return must(NewHashableKeyHopscotchHashMapE[K, V](opts...))
+}
+
+// NewHashableKeyHopscotchHashMapE is like NewHashableKeyHopscotchHashMap, but
+// returns an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewHashableKeyHopscotchHashMapE[K HashableKey[K], V any](opts ...Option) (*HopscotchHashMap[K, V], error) {
+	return NewHopscotchHashMapWithHasherE[K, V](HashableKeyMapHasher[K](), compare.EqualableComparator[K], opts...)
+}
+
+// NewHopscotchHashMapWithHasher returns a pointer to a new HopscotchHashMap
+// which hashes keys with hasher and compares them with equal. equal must be
+// consistent with hasher: keys which are equal must have equal hashes.
+func NewHopscotchHashMapWithHasher[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) *HopscotchHashMap[K, V] {
+	return must(NewHopscotchHashMapWithHasherE[K, V](hasher, equal, opts...))
+}
+
+// NewHopscotchHashMapWithHasherE is like NewHopscotchHashMapWithHasher, but
+// returns an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewHopscotchHashMapWithHasherE[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) (*HopscotchHashMap[K, V], error) {
+	o := kvMapOpts{capacity: defaultCap, loadFactor: hopscotchDefaultLoadFactor}
+	if err := applyOptions(&o, opts); err != nil {
+		return nil, err
+	}
+	n := hopscotchNeighborhood
+	for n < o.capacity {
+		n <<= 1
+	}
+	maxCap := o.maxCapacity
+	if maxCap > 0 {
+		maxCap = max(maxCap, hopscotchNeighborhood)
+		n = min(n, maxCap)
+	}
+	return &HopscotchHashMap[K, V]{
+		comparator:   equal,
+		hasher:       hasher,
+		loadFactor:   o.loadFactor,
+		growthFactor: o.growthFactor,
+		maxCap:       maxCap,
+		cap:          n,
+	}, nil
+}
+
+// HopscotchHashMap is a hash map which can store keys and values of any type,
+// using hopscotch hashing: every key is stored within a small neighborhood of
+// slots following its home slot, and each home slot keeps a bitmap of which
+// neighbors hold its keys. Insertions move keys closer to home to make room,
+// so lookups stay short even when the table is almost full, which makes
+// HopscotchHashMap suited to memory-constrained uses with load factors above
+// 0.9. Iteration order is unspecified.
+//
+// HopscotchHashMap supports the Capacity() (default: 32), LoadFactor()
+// (default: 0.9), GrowthFactor() (default: 2) and MaxCapacity() (default:
+// unbounded) Options; other Options are ignored.
+type HopscotchHashMap[K, V any] struct {
+	comparator compare.Comparator[K]
+	hasher     MapHasher[K]
+
+	loadFactor   float32
+	growthFactor float64
+	// maxCap is the capacity m may grow to, or 0 if unbounded.
+	maxCap int
+
+	slots []hashSlot[K, V]
+	// hops[i] has bit d set if slots[i+d] holds a key whose home slot is i.
+	hops []uint32
+	// overflow holds keys which could not be moved into their neighborhood:
+	// up to hopscotchMaxStash of them at any load, and any number while the
+	// table is sparse, which only happens when more keys share a neighborhood
+	// than it has slots.
+	overflow []hashSlot[K, V]
+
+	cap  int
+	size int
+
+	longestProbe, rehashes int
+}
+
+// home returns the index of the home slot of keys with hash h. Capacities
+// need not be powers of 2, so h is mapped onto the table by multiplication
+// rather than masking.
+func (m *HopscotchHashMap[K, V]) home(h uint64) int {
+	return int(uint64(uint32(h)) * uint64(m.cap) >> 32)
+}
+
+// wrap returns the index of slot i, where i is in [-cap, 2*cap), wrapping
+// around the ends of the table.
+func (m *HopscotchHashMap[K, V]) wrap(i int) int {
+	if i >= m.cap {
+		return i - m.cap
+	}
+	if i < 0 {
+		return i + m.cap
+	}
+	return i
+}
+
+// canGrow returns true if m may grow beyond cap slots.
+func (m *HopscotchHashMap[K, V]) canGrow(cap int) bool {
+	return m.maxCap == 0 || cap < m.maxCap
+}
+
+// find returns the slot holding key, or nil if key is not in m.
+func (m *HopscotchHashMap[K, V]) find(key *K) *hashSlot[K, V] {
+	if m.size == 0 {
+		return nil
+	}
+	h := m.hasher.Hash(key)
+	home := m.home(h)
+	for hop := m.hops[home]; hop != 0; hop &= hop - 1 {
+		s := &m.slots[m.wrap(home+bits.TrailingZeros32(hop))]
+		if s.hashCache == h && m.comparator(s.key, *key) {
+			return s
+		}
+	}
+	for i := range m.overflow {
+		if s := &m.overflow[i]; s.hashCache == h && m.comparator(s.key, *key) {
+			return s
+		}
+	}
+	return nil
+}
+
+// place inserts s into the table, returning false if no empty slot could be
+// moved into its neighborhood.
+func (m *HopscotchHashMap[K, V]) place(s hashSlot[K, V]) bool {
+	home := m.home(s.hashCache)
+
+	// Find the nearest empty slot by linear probing.
+	dist := 0
+	for ; dist < m.cap && m.slots[m.wrap(home+dist)].used; dist++ {
+	}
+	if dist == m.cap {
+		return false
+	}
+	m.longestProbe = max(m.longestProbe, dist)
+
+	// Hop the empty slot back towards home, by moving a key from an earlier
+	// slot into it while the key stays within its own neighborhood.
+	for free := m.wrap(home + dist); dist >= hopscotchNeighborhood; {
+		moved := false
+		for d := hopscotchNeighborhood - 1; d > 0 && !moved; d-- {
+			b := m.wrap(free - d)
+			// Only keys of b stored before free can be moved into it.
+			if hop := m.hops[b] & (1<<d - 1); hop != 0 {
+				o := bits.TrailingZeros32(hop)
+				k := m.wrap(b + o)
+				m.slots[free], m.slots[k] = m.slots[k], hashSlot[K, V]{}
+				m.hops[b] ^= 1<<o | 1<<d
+				free, dist, moved = k, dist-(d-o), true
+			}
+		}
+		if !moved {
+			return false
+		}
+	}
+	m.slots[m.wrap(home+dist)] = s
+	m.hops[home] |= 1 << dist
+	return true
+}
+
+// insert adds s, which must not already be in m, growing the table if needed.
+// It returns ErrMaxCapacity if s doesn't fit and m can't grow.
+func (m *HopscotchHashMap[K, V]) insert(s hashSlot[K, V]) error {
+	if float32(m.size+1) > m.loadFactor*float32(m.cap) {
+		if !m.canGrow(m.cap) {
+			return ErrMaxCapacity
+		}
+		m.rebuild(grownCapacity(m.cap, m.growthFactor, m.maxCap))
+	}
+	for !m.place(s) {
+		if m.size+1 < m.cap/2 || len(m.overflow) < hopscotchMaxStash {
+			// The neighborhood is crowded, not the table, so growing won't
+			// help, or isn't needed yet.
+			m.overflow = append(m.overflow, s)
+			break
+		}
+		if !m.canGrow(m.cap) {
+			return ErrMaxCapacity
+		}
+		m.rebuild(grownCapacity(m.cap, m.growthFactor, m.maxCap))
+	}
+	m.size++
+	return nil
+}
+
+// rebuild reinserts every entry of m into a new table of cap slots, growing
+// cap until they fit. At the maximum capacity, entries which don't fit are
+// kept in the overflow.
+func (m *HopscotchHashMap[K, V]) rebuild(cap int) {
+	old := make([]hashSlot[K, V], 0, m.size)
+	for _, s := range m.slots {
+		if s.used {
+			old = append(old, s)
+		}
+	}
+	old = append(old, m.overflow...)
+
+	for {
+		m.rehashes++
+		m.cap, m.longestProbe = cap, 0
+		m.slots, m.hops = make([]hashSlot[K, V], cap), make([]uint32, cap)
+		m.overflow = nil
+		ok := true
+		for _, s := range old {
+			if !m.place(s) {
+				if len(old) >= cap/2 && len(m.overflow) == hopscotchMaxStash && m.canGrow(cap) {
+					ok = false
+					break
+				}
+				m.overflow = append(m.overflow, s)
+			}
+		}
+		if ok {
+			return
+		}
+		cap = grownCapacity(cap, m.growthFactor, m.maxCap)
+	}
+}
+
+// Put maps key to val. It panics with ErrMaxCapacity if key is new and m
+// can't grow to make room for it.
+func (m *HopscotchHashMap[K, V]) Put(key K, val V) {
+	if err := m.TryPut(key, val); err != nil {
+		panic(err)
+	}
+}
+
+// TryPut is like Put, but returns ErrMaxCapacity rather than panicking, in
+// which case m is unchanged.
+func (m *HopscotchHashMap[K, V]) TryPut(key K, val V) error {
+	if s := m.find(&key); s != nil {
+		s.value = val
+		return nil
+	}
+	if m.slots == nil {
+		m.slots, m.hops = make([]hashSlot[K, V], m.cap), make([]uint32, m.cap)
+	}
+	return m.insert(hashSlot[K, V]{key: key, value: val, hashCache: m.hasher.Hash(&key), used: true})
+}
+
+func (m *HopscotchHashMap[K, V]) Get(key K) (val V, ok bool) {
+	if s := m.find(&key); s != nil {
+		return s.value, true
+	}
+	return
+}
+
+func (m *HopscotchHashMap[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *HopscotchHashMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	s := m.find(&key)
+	if s == nil {
+		return
+	}
+	val, ok = s.value, true
+	m.size--
+	for i := range m.overflow {
+		if s == &m.overflow[i] {
+			m.overflow = append(m.overflow[:i], m.overflow[i+1:]...)
+			return
+		}
+	}
+	home := m.home(s.hashCache)
+	for hop := m.hops[home]; hop != 0; hop &= hop - 1 {
+		d := bits.TrailingZeros32(hop)
+		if &m.slots[m.wrap(home+d)] == s {
+			m.hops[home] &^= 1 << d
+			break
+		}
+	}
+	*s = hashSlot[K, V]{}
+	return
+}
+
+func (m *HopscotchHashMap[K, V]) Has(key K) bool {
+	return m.find(&key) != nil
+}
+
+func (m *HopscotchHashMap[K, V]) Len() int {
+	return m.size
+}
+
+// Stats returns a snapshot of m's hash table statistics. LongestProbe is the
+// longest linear probe made by an insertion to find an empty slot since the
+// table was last rebuilt.
+func (m *HopscotchHashMap[K, V]) Stats() HashMapStats {
+	return HashMapStats{
+		Len:          m.size,
+		Capacity:     m.cap,
+		LongestProbe: m.longestProbe,
+		Rehashes:     m.rehashes,
+	}
+}
+
+func (m *HopscotchHashMap[K, V]) String() string {
+	return IterableMapToString[K, V](m)
+}
+
+func (m *HopscotchHashMap[K, V]) GoString() string {
+	return IterableMapToGoString[K, V](m)
+}
+
+// Iterator returns an Iterator over the entries of m, in unspecified order.
+// m must not be modified during iteration, except through Entry.SetValue.
+func (m *HopscotchHashMap[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	return &hashSlotIterator[K, V]{tables: [][]hashSlot[K, V]{m.slots, m.overflow}}
+}
+
+// All returns an iter.Seq2 over the keys and values of m, in unspecified
+// order. m must not be modified during iteration.
+func (m *HopscotchHashMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, tbl := range [][]hashSlot[K, V]{m.slots, m.overflow} {
+			for i := range tbl {
+				if s := &tbl[i]; s.used && !yield(s.key, s.value) {
+					return
+				}
+			}
+		}
+	}
+}
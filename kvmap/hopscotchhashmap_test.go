@@ -0,0 +1,65 @@
+package kvmap
+
+import (
+	"testing"
+)
+
+func TestHopscotchHashMapHighLoad(t *testing.T) {
+	m := NewComparableHopscotchHashMap[int, int](Capacity(1024), LoadFactor(0.95))
+	for i := 0; i < 972; i++ {
+		m.Put(i, i*2)
+	}
+	if st := m.Stats(); st.Len != 972 || float64(st.Len)/float64(st.Capacity) < 0.9 {
+		t.Errorf("Want Stats() with Len 972 and Len/Capacity >= 0.9, Got %+v", st)
+	}
+	for i := 0; i < 972; i += 3 {
+		m.Delete(i)
+	}
+	for i := 0; i < 1000; i++ {
+		v, ok := m.Get(i)
+		if want := i < 972 && i%3 != 0; ok != want || (ok && v != i*2) {
+			t.Errorf("Want Get(%d) == (%d, %t), Got (%d, %t)", i, i*2, want, v, ok)
+		}
+	}
+
+	n := 0
+	for k, v := range m.All() {
+		if k%3 == 0 || v != k*2 {
+			t.Errorf("Want no multiples of 3 and doubled values, Got %d:%d", k, v)
+		}
+		n++
+	}
+	if n != m.Len() || n != 648 {
+		t.Errorf("Want All() to yield Len() == 648 entries, Got %d and Len() == %d", n, m.Len())
+	}
+}
+
+func TestHopscotchHashMapCrowdedNeighborhood(t *testing.T) {
+	m := NewComparableHopscotchHashMap[int, int](Capacity(1024))
+	// Every key has the same home slot, so no more than hopscotchNeighborhood
+	// of them fit in the table.
+	m.hasher = CustomMapHasher[int](func(*int) []byte { return []byte{0} })
+
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	if st := m.Stats(); st.Capacity != 1024 || st.Len != 100 {
+		t.Errorf("Want Stats() {Len: 100, Capacity: 1024}, Got %+v", st)
+	}
+	for i := 0; i < 100; i += 2 {
+		m.Delete(i)
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := m.Get(i); ok != (i%2 == 1) || (ok && v != i) {
+			t.Errorf("Want Get(%d) == (%[1]d, %t), Got (%d, %t)", i, i%2 == 1, v, ok)
+		}
+	}
+}
+
+func BenchmarkHopscotchHashMapGet(b *testing.B) {
+	benchmarkMapGet(b, NewComparableHopscotchHashMap[int, int]())
+}
+
+func BenchmarkHopscotchHashMapPut(b *testing.B) {
+	benchmarkMapPut(b, func() Interface[int, int] { return NewComparableHopscotchHashMap[int, int]() })
+}
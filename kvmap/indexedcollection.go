@@ -0,0 +1,292 @@
+package kvmap
+
+import (
+	"errors"
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// ErrDuplicateKey is returned when an Insert or Update would give two
+// elements of an IndexedCollection the same key in a UniqueIndex.
+var ErrDuplicateKey = errors.New("kvmap: duplicate key in unique index")
+
+// ElementID identifies an element of an IndexedCollection. IDs are never
+// reused by the collection which issued them.
+type ElementID uint64
+
+// collectionIndex is the interface through which an IndexedCollection keeps
+// its indexes in sync with its elements.
+type collectionIndex[E any] interface {
+	// check returns an error if adding e, as the element id, would violate
+	// a constraint of the index.
+	check(id ElementID, e E) error
+	add(id ElementID, e E)
+	remove(id ElementID, e E)
+}
+
+// IndexedCollection is a collection of elements which are stored once, and
+// can be looked up through any number of indexes on keys derived from them:
+// a UniqueIndex, a HashIndex allowing many elements per key, or an
+// OrderedIndex supporting range queries. Indexes are registered with
+// NewUniqueIndex, NewHashIndex and NewOrderedIndex, and are kept in sync as
+// elements are inserted, updated and deleted.
+type IndexedCollection[E any] struct {
+	elems   map[ElementID]E
+	indexes []collectionIndex[E]
+	nextID  ElementID
+}
+
+// NewIndexedCollection returns a pointer to a new, empty IndexedCollection
+// with no indexes.
+func NewIndexedCollection[E any]() *IndexedCollection[E] {
+	return &IndexedCollection[E]{elems: make(map[ElementID]E)}
+}
+
+// register adds idx to c, indexing the existing elements of c.
+func (c *IndexedCollection[E]) register(idx collectionIndex[E]) {
+	for id, e := range c.elems {
+		if err := idx.check(id, e); err != nil {
+			panic("index registered on an IndexedCollection with duplicate keys")
+		}
+		idx.add(id, e)
+	}
+	c.indexes = append(c.indexes, idx)
+}
+
+// check returns the first error from an index of c for adding e as id.
+func (c *IndexedCollection[E]) check(id ElementID, e E) error {
+	for _, idx := range c.indexes {
+		if err := idx.check(id, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Insert adds e to c and its indexes, and returns its ID. If e would violate
+// a UniqueIndex, it returns ErrDuplicateKey and c is unchanged.
+func (c *IndexedCollection[E]) Insert(e E) (ElementID, error) {
+	id := c.nextID + 1
+	if err := c.check(id, e); err != nil {
+		return 0, err
+	}
+	c.nextID = id
+	c.elems[id] = e
+	for _, idx := range c.indexes {
+		idx.add(id, e)
+	}
+	return id, nil
+}
+
+// Update replaces the element id with e, moving it within every index whose
+// key changes. It returns false if id is not in c. If e would violate a
+// UniqueIndex, it returns ErrDuplicateKey and c is unchanged.
+func (c *IndexedCollection[E]) Update(id ElementID, e E) (ok bool, err error) {
+	old, ok := c.elems[id]
+	if !ok {
+		return false, nil
+	}
+	if err := c.check(id, e); err != nil {
+		return true, err
+	}
+	for _, idx := range c.indexes {
+		idx.remove(id, old)
+		idx.add(id, e)
+	}
+	c.elems[id] = e
+	return true, nil
+}
+
+// Delete removes the element id from c and its indexes, and returns false if
+// it is not in c.
+func (c *IndexedCollection[E]) Delete(id ElementID) bool {
+	e, ok := c.elems[id]
+	if !ok {
+		return false
+	}
+	for _, idx := range c.indexes {
+		idx.remove(id, e)
+	}
+	delete(c.elems, id)
+	return true
+}
+
+// Get returns the element id, and false if it is not in c.
+func (c *IndexedCollection[E]) Get(id ElementID) (e E, ok bool) {
+	e, ok = c.elems[id]
+	return
+}
+
+// Len returns the number of elements in c.
+func (c *IndexedCollection[E]) Len() int {
+	return len(c.elems)
+}
+
+// All returns an iter.Seq2 over the IDs and elements of c, in unspecified
+// order. c must not be modified during iteration.
+func (c *IndexedCollection[E]) All() iter.Seq2[ElementID, E] {
+	return func(yield func(ElementID, E) bool) {
+		for id, e := range c.elems {
+			if !yield(id, e) {
+				return
+			}
+		}
+	}
+}
+
+// UniqueIndex is an index of an IndexedCollection in which no two elements
+// may have the same key.
+type UniqueIndex[K comparable, E any] struct {
+	c   *IndexedCollection[E]
+	key func(E) K
+	ids map[K]ElementID
+}
+
+// NewUniqueIndex registers and returns a UniqueIndex of the elements of c by
+// key. It panics if two elements already in c have the same key.
+func NewUniqueIndex[K comparable, E any](c *IndexedCollection[E], key func(E) K) *UniqueIndex[K, E] {
+	idx := &UniqueIndex[K, E]{c: c, key: key, ids: make(map[K]ElementID)}
+	c.register(idx)
+	return idx
+}
+
+func (idx *UniqueIndex[K, E]) check(id ElementID, e E) error {
+	if other, ok := idx.ids[idx.key(e)]; ok && other != id {
+		return ErrDuplicateKey
+	}
+	return nil
+}
+
+func (idx *UniqueIndex[K, E]) add(id ElementID, e E) {
+	idx.ids[idx.key(e)] = id
+}
+
+func (idx *UniqueIndex[K, E]) remove(id ElementID, e E) {
+	delete(idx.ids, idx.key(e))
+}
+
+// Get returns the ID of the element with key, and the element, or false if
+// there is none.
+func (idx *UniqueIndex[K, E]) Get(key K) (id ElementID, e E, ok bool) {
+	if id, ok = idx.ids[key]; ok {
+		e = idx.c.elems[id]
+	}
+	return
+}
+
+// HashIndex is an index of an IndexedCollection in which many elements may
+// have the same key.
+type HashIndex[K comparable, E any] struct {
+	c   *IndexedCollection[E]
+	key func(E) K
+	ids map[K][]ElementID
+}
+
+// NewHashIndex registers and returns a HashIndex of the elements of c by key.
+func NewHashIndex[K comparable, E any](c *IndexedCollection[E], key func(E) K) *HashIndex[K, E] {
+	idx := &HashIndex[K, E]{c: c, key: key, ids: make(map[K][]ElementID)}
+	c.register(idx)
+	return idx
+}
+
+func (idx *HashIndex[K, E]) check(ElementID, E) error {
+	return nil
+}
+
+func (idx *HashIndex[K, E]) add(id ElementID, e E) {
+	k := idx.key(e)
+	idx.ids[k] = append(idx.ids[k], id)
+}
+
+func (idx *HashIndex[K, E]) remove(id ElementID, e E) {
+	k := idx.key(e)
+	ids := slices.DeleteFunc(idx.ids[k], func(other ElementID) bool { return other == id })
+	if len(ids) == 0 {
+		delete(idx.ids, k)
+		return
+	}
+	idx.ids[k] = ids
+}
+
+// Count returns the number of elements with key.
+func (idx *HashIndex[K, E]) Count(key K) int {
+	return len(idx.ids[key])
+}
+
+// Get returns an iter.Seq2 over the IDs and elements with key, in the order
+// they were indexed. The collection must not be modified during iteration.
+func (idx *HashIndex[K, E]) Get(key K) iter.Seq2[ElementID, E] {
+	return func(yield func(ElementID, E) bool) {
+		for _, id := range idx.ids[key] {
+			if !yield(id, idx.c.elems[id]) {
+				return
+			}
+		}
+	}
+}
+
+// orderedIndexKey orders the entries of an OrderedIndex by key, then by ID,
+// so that elements with equal keys are distinct entries.
+type orderedIndexKey[K any] struct {
+	key K
+	id  ElementID
+}
+
+// OrderedIndex is an index of an IndexedCollection which orders elements by
+// key, allowing many elements to have the same key.
+type OrderedIndex[K, E any] struct {
+	c        *IndexedCollection[E]
+	key      func(E) K
+	ordering compare.Ordering[K]
+	tree     *OrderedMap[orderedIndexKey[K], struct{}]
+}
+
+// NewOrderedIndex registers and returns an OrderedIndex of the elements of c
+// by key, using ordering to order keys. Elements with equal keys are ordered
+// by ID.
+func NewOrderedIndex[K, E any](c *IndexedCollection[E], key func(E) K, ordering compare.Ordering[K]) *OrderedIndex[K, E] {
+	idx := &OrderedIndex[K, E]{c: c, key: key, ordering: ordering}
+	idx.tree = NewOrderedMapWithOrdering[orderedIndexKey[K], struct{}](func(a, b orderedIndexKey[K]) bool {
+		if ordering(a.key, b.key) {
+			return true
+		}
+		return !ordering(b.key, a.key) && a.id < b.id
+	})
+	c.register(idx)
+	return idx
+}
+
+func (idx *OrderedIndex[K, E]) check(ElementID, E) error {
+	return nil
+}
+
+func (idx *OrderedIndex[K, E]) add(id ElementID, e E) {
+	idx.tree.Put(orderedIndexKey[K]{idx.key(e), id}, struct{}{})
+}
+
+func (idx *OrderedIndex[K, E]) remove(id ElementID, e E) {
+	idx.tree.Delete(orderedIndexKey[K]{idx.key(e), id})
+}
+
+// All returns an iter.Seq2 over the IDs and elements of the collection in
+// ascending key order. The collection must not be modified during iteration.
+func (idx *OrderedIndex[K, E]) All() iter.Seq2[ElementID, E] {
+	return func(yield func(ElementID, E) bool) {
+		idx.tree.Ascend(func(k orderedIndexKey[K], _ struct{}) bool {
+			return yield(k.id, idx.c.elems[k.id])
+		})
+	}
+}
+
+// Range returns an iter.Seq2 over the IDs and elements with keys in
+// [from, to), in ascending key order. The collection must not be modified
+// during iteration.
+func (idx *OrderedIndex[K, E]) Range(from, to K) iter.Seq2[ElementID, E] {
+	return func(yield func(ElementID, E) bool) {
+		idx.tree.AscendGreaterOrEqual(orderedIndexKey[K]{key: from}, func(k orderedIndexKey[K], _ struct{}) bool {
+			return idx.ordering(k.key, to) && yield(k.id, idx.c.elems[k.id])
+		})
+	}
+}
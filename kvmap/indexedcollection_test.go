@@ -0,0 +1,73 @@
+package kvmap
+
+import (
+	"errors"
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+type employee struct {
+	email string
+	dept  string
+	age   int
+}
+
+func TestIndexedCollection(t *testing.T) {
+	c := NewIndexedCollection[employee]()
+	alice, _ := c.Insert(employee{"alice@x", "eng", 30})
+	byEmail := NewUniqueIndex(c, func(e employee) string { return e.email })
+	byDept := NewHashIndex(c, func(e employee) string { return e.dept })
+	byAge := NewOrderedIndex(c, func(e employee) int { return e.age }, compare.Less[int])
+
+	bob, _ := c.Insert(employee{"bob@x", "eng", 25})
+	carol, _ := c.Insert(employee{"carol@x", "ops", 41})
+	dave, _ := c.Insert(employee{"dave@x", "ops", 30})
+
+	if _, err := c.Insert(employee{"bob@x", "ops", 50}); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Want ErrDuplicateKey inserting bob@x again, Got %v", err)
+	}
+	if c.Len() != 4 || byDept.Count("ops") != 2 {
+		t.Errorf("Want failed Insert to leave 4 elements and 2 in ops, Got %d and %d", c.Len(), byDept.Count("ops"))
+	}
+	if id, e, ok := byEmail.Get("alice@x"); !ok || id != alice || e.age != 30 {
+		t.Errorf("Want byEmail.Get(alice@x) to find alice, Got (%d, %v, %t)", id, e, ok)
+	}
+
+	var ids []ElementID
+	for id := range byAge.Range(26, 41) {
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 || ids[0] != alice || ids[1] != dave {
+		t.Errorf("Want ages in [26, 41) to be alice then dave, Got %v", ids)
+	}
+
+	// Moving bob to ops updates every index.
+	if ok, err := c.Update(bob, employee{"robert@x", "ops", 26}); !ok || err != nil {
+		t.Fatalf("Want Update(bob) to succeed, Got (%t, %v)", ok, err)
+	}
+	if _, _, ok := byEmail.Get("bob@x"); ok {
+		t.Errorf("Want bob@x unindexed after Update, Got found")
+	}
+	if byDept.Count("eng") != 1 || byDept.Count("ops") != 3 {
+		t.Errorf("Want 1 in eng and 3 in ops, Got %d and %d", byDept.Count("eng"), byDept.Count("ops"))
+	}
+	if _, err := c.Update(bob, employee{"carol@x", "ops", 26}); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Want ErrDuplicateKey updating to carol@x, Got %v", err)
+	}
+	if e, _ := c.Get(bob); e.email != "robert@x" {
+		t.Errorf("Want failed Update to leave robert@x, Got %s", e.email)
+	}
+
+	c.Delete(carol)
+	var ages []int
+	for _, e := range byAge.All() {
+		ages = append(ages, e.age)
+	}
+	if len(ages) != 3 || ages[0] != 26 || ages[1] != 30 || ages[2] != 30 {
+		t.Errorf("Want ages [26 30 30] after deleting carol, Got %v", ages)
+	}
+	if c.Delete(carol) {
+		t.Errorf("Want Delete of a deleted element to return false, Got true")
+	}
+}
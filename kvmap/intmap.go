@@ -0,0 +1,224 @@
+package kvmap
+
+import (
+	"iter"
+	"maps"
+	"math/bits"
+	"slices"
+
+	"github.org/jccarlson/collections"
+)
+
+// minIntMapDense is the size the dense array of an IntMap may always grow to.
+const minIntMapDense = 64
+
+// IntMap is a map with int keys, which stores values for small non-negative
+// keys directly in a slice indexed by key, and other keys in a built-in map.
+// For ID-dense workloads, such as entity-component systems, most operations
+// are a bounds check and an index rather than a hash lookup. The slice only
+// grows to cover a key if at least half of it would be in use, so a few
+// large or sparse keys don't waste memory.
+//
+// IntMap supports the Capacity() Option, which sets the initial length of
+// the slice; other Options are ignored.
+type IntMap[V any] struct {
+	dense []V
+	// present has bit i set if dense[i] holds a value.
+	present []uint64
+	ndense  int
+
+	sparse map[int]V
+}
+
+// NewIntMap returns a pointer to a new, empty IntMap.
+func NewIntMap[V any](opts ...Option) *IntMap[V] {
+	var o kvMapOpts
+	mustApplyOptions(&o, opts)
+	m := &IntMap[V]{}
+	m.growDense(o.capacity)
+	return m
+}
+
+func (m *IntMap[V]) isDense(key int) bool {
+	return key >= 0 && key < len(m.dense)
+}
+
+func (m *IntMap[V]) hasDense(key int) bool {
+	return m.present[key/64]&(1<<(key%64)) != 0
+}
+
+// growDense grows the slice to cover at least n keys, moving any values for
+// newly covered keys out of the sparse map.
+func (m *IntMap[V]) growDense(n int) {
+	if n <= len(m.dense) {
+		return
+	}
+	n = max(n, 2*len(m.dense))
+	dense := make([]V, n)
+	copy(dense, m.dense)
+	present := make([]uint64, (n+63)/64)
+	copy(present, m.present)
+	m.dense, m.present = dense, present
+	for k, v := range m.sparse {
+		if m.isDense(k) {
+			delete(m.sparse, k)
+			m.dense[k] = v
+			m.present[k/64] |= 1 << (k % 64)
+			m.ndense++
+		}
+	}
+}
+
+func (m *IntMap[V]) Put(key int, val V) {
+	if !m.isDense(key) {
+		if _, ok := m.sparse[key]; ok {
+			// Replacing a value never grows the slice, so that SetValue
+			// can't move keys into it during iteration.
+			m.sparse[key] = val
+			return
+		}
+		if key >= 0 && key < max(minIntMapDense, 2*(m.Len()+1)) {
+			m.growDense(key + 1)
+		}
+	}
+	if m.isDense(key) {
+		if !m.hasDense(key) {
+			m.present[key/64] |= 1 << (key % 64)
+			m.ndense++
+		}
+		m.dense[key] = val
+		return
+	}
+	if m.sparse == nil {
+		m.sparse = make(map[int]V)
+	}
+	m.sparse[key] = val
+}
+
+func (m *IntMap[V]) Get(key int) (val V, ok bool) {
+	if m.isDense(key) {
+		if m.hasDense(key) {
+			return m.dense[key], true
+		}
+		return val, false
+	}
+	val, ok = m.sparse[key]
+	return val, ok
+}
+
+func (m *IntMap[V]) Delete(key int) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *IntMap[V]) DeleteAndGet(key int) (val V, ok bool) {
+	if m.isDense(key) {
+		if m.hasDense(key) {
+			val, ok = m.dense[key], true
+			var zero V
+			m.dense[key] = zero
+			m.present[key/64] &^= 1 << (key % 64)
+			m.ndense--
+		}
+		return
+	}
+	if val, ok = m.sparse[key]; ok {
+		delete(m.sparse, key)
+	}
+	return
+}
+
+func (m *IntMap[V]) Has(key int) bool {
+	if m.isDense(key) {
+		return m.hasDense(key)
+	}
+	_, ok := m.sparse[key]
+	return ok
+}
+
+func (m *IntMap[V]) Len() int {
+	return m.ndense + len(m.sparse)
+}
+
+func (m *IntMap[V]) String() string {
+	return IterableMapToString[int, V](m)
+}
+
+func (m *IntMap[V]) GoString() string {
+	return IterableMapToGoString[int, V](m)
+}
+
+// All returns an iter.Seq2 over the keys and values of m: first those stored
+// in the slice, in ascending key order, then the others in unspecified
+// order. m must not be modified during iteration.
+func (m *IntMap[V]) All() iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		for i, w := range m.present {
+			for ; w != 0; w &= w - 1 {
+				k := i*64 + bits.TrailingZeros64(w)
+				if !yield(k, m.dense[k]) {
+					return
+				}
+			}
+		}
+		for k, v := range m.sparse {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator returns an Iterator over the entries of m, in the order of All. m
+// must not be modified during iteration, except through Entry.SetValue.
+func (m *IntMap[V]) Iterator() collections.Iterator[Entry[int, V]] {
+	return &intMapIterator[V]{m: m}
+}
+
+// intMapIterator iterates over the slice in key order, then over the keys of
+// the sparse map, which it collects once the slice is done.
+type intMapIterator[V any] struct {
+	m *IntMap[V]
+	// next is the next key of the slice to check.
+	next   int
+	sparse []int
+}
+
+func (i *intMapIterator[V]) Next() (entry Entry[int, V], ok bool) {
+	for ; i.next < len(i.m.dense); i.next++ {
+		if k := i.next; i.m.hasDense(k) {
+			i.next++
+			return &intMapEntry[V]{i.m, k, i.m.dense[k]}, true
+		}
+	}
+	if i.next == len(i.m.dense) {
+		i.next++
+		i.sparse = slices.Collect(maps.Keys(i.m.sparse))
+	}
+	if len(i.sparse) == 0 {
+		return
+	}
+	k := i.sparse[0]
+	i.sparse = i.sparse[1:]
+	return &intMapEntry[V]{i.m, k, i.m.sparse[k]}, true
+}
+
+type intMapEntry[V any] struct {
+	m     *IntMap[V]
+	key   int
+	value V
+}
+
+func (e *intMapEntry[V]) Key() int {
+	return e.key
+}
+
+func (e *intMapEntry[V]) Value() V {
+	return e.value
+}
+
+func (e *intMapEntry[V]) SetValue(v V) {
+	e.value = v
+	e.m.Put(e.key, v)
+}
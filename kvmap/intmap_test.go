@@ -0,0 +1,111 @@
+package kvmap
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestIntMap(t *testing.T) {
+	m := NewIntMap[string]()
+	for i := 0; i < 1000; i++ {
+		m.Put(i, strconv.Itoa(i))
+	}
+	m.Put(-5, "minus five")
+	m.Put(1<<40, "huge")
+	if len(m.dense) < 1000 || len(m.sparse) != 2 {
+		t.Errorf("Want keys [0, 1000) in the slice and 2 sparse keys, Got slice of %d and %d sparse", len(m.dense), len(m.sparse))
+	}
+	if m.Len() != 1002 {
+		t.Errorf("Want Len() == 1002, Got %d", m.Len())
+	}
+	m.Delete(10)
+	m.Delete(-5)
+	m.Delete(5000)
+	if m.Has(10) || m.Has(-5) || !m.Has(1<<40) || m.Len() != 1000 {
+		t.Errorf("Want 10 and -5 deleted, Got Len() == %d", m.Len())
+	}
+	if v, ok := m.Get(999); !ok || v != "999" {
+		t.Errorf("Want Get(999) == (999, true), Got (%s, %t)", v, ok)
+	}
+
+	prev, n := -1, 0
+	for k, v := range m.All() {
+		if k != 1<<40 && (k <= prev || v != strconv.Itoa(k)) {
+			t.Errorf("Want dense keys in ascending order with their values, Got %d:%s after %d", k, v, prev)
+		}
+		prev, n = k, n+1
+	}
+	if n != 1000 {
+		t.Errorf("Want All() to yield 1000 entries, Got %d", n)
+	}
+}
+
+func TestIntMapMovesSparseKeysWhenSliceGrows(t *testing.T) {
+	m := NewIntMap[int](Capacity(0))
+	m.Put(100, 100)
+	if len(m.sparse) != 1 {
+		t.Fatalf("Want 100 stored sparsely in an empty map, Got slice of %d", len(m.dense))
+	}
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	if len(m.sparse) != 0 || !m.Has(100) {
+		t.Errorf("Want 100 moved into the slice once it is dense, Got %d sparse keys", len(m.sparse))
+	}
+}
+
+func TestIntMapIterator(t *testing.T) {
+	m := NewIntMap[int]()
+	for _, k := range []int{3, 0, 50, -1, 1 << 40} {
+		m.Put(k, k*2)
+	}
+	var got []int
+	it := m.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		if e.Value() != e.Key()*2 {
+			t.Errorf("Want %d:%d, Got %d:%d", e.Key(), e.Key()*2, e.Key(), e.Value())
+		}
+		got = append(got, e.Key())
+	}
+	var want []int
+	for k := range m.All() {
+		want = append(want, k)
+	}
+	if len(got) != 5 || len(got) != len(want) || got[0] != 0 || got[1] != 3 || got[2] != 50 {
+		t.Errorf("Want Iterator() to yield %v, Got %v", want, got)
+	}
+
+	// Abandoned iterators need no Close, and hold no resources.
+	before := runtime.NumGoroutine()
+	for range 100 {
+		m.Iterator().Next()
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Want no goroutines left by abandoned iterators, Got %d more", after-before)
+	}
+}
+
+func TestIntMapIteratorSetValue(t *testing.T) {
+	m := NewIntMap[int]()
+	m.Put(100, 100)
+	for i := 0; i < 60; i++ {
+		m.Put(i, i)
+	}
+	seen := map[int]int{}
+	it := m.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		seen[e.Key()]++
+		e.SetValue(e.Value() + 1)
+	}
+	if len(seen) != 61 || seen[100] != 1 {
+		t.Errorf("Want each of 61 keys visited once, Got %d keys with 100 visited %d times", len(seen), seen[100])
+	}
+	if v, _ := m.Get(100); v != 101 {
+		t.Errorf("Want Get(100) == 101 after SetValue, Got %d", v)
+	}
+}
+
+func BenchmarkIntMapGet(b *testing.B) {
+	benchmarkMapGet(b, NewIntMap[int]())
+}
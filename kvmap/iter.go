@@ -0,0 +1,53 @@
+package kvmap
+
+import "iter"
+
+// Seq2 returns an iter.Seq2 over the keys and values of m, in m's iteration
+// order, for use with the standard library's maps and slices packages, e.g.
+// maps.Collect(kvmap.Seq2(m)). If m has an All method, as the maps of this
+// package do, Seq2 returns its result; otherwise it adapts m's Iterator,
+// closing it when iteration stops if it has a Close method.
+func Seq2[K, V any](m IterableMap[K, V]) iter.Seq2[K, V] {
+	if a, ok := m.(interface{ All() iter.Seq2[K, V] }); ok {
+		return a.All()
+	}
+	return func(yield func(K, V) bool) {
+		for e := range EntrySeq(m) {
+			if !yield(e.Key(), e.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iter.Seq over the keys of m, in m's iteration order, like
+// maps.Keys does for built-in maps.
+func Keys[K, V any](m IterableMap[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range Seq2(m) {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iter.Seq over the values of m, in m's iteration order,
+// like maps.Values does for built-in maps.
+func Values[K, V any](m IterableMap[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range Seq2(m) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Insert puts the keys and values of seq into dst, overwriting the values of
+// keys already in dst, like maps.Insert does for built-in maps.
+func Insert[K, V any](dst Interface[K, V], seq iter.Seq2[K, V]) {
+	for k, v := range seq {
+		dst.Put(k, v)
+	}
+}
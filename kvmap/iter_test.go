@@ -0,0 +1,51 @@
+package kvmap
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections"
+)
+
+// iteratorOnly hides the All method of an IterableMap.
+type iteratorOnly[K, V any] struct {
+	Interface[K, V]
+	it func() collections.Iterator[Entry[K, V]]
+}
+
+func (m iteratorOnly[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	return m.it()
+}
+
+func TestStdlibInterop(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	Insert[string, int](m, maps.All(map[string]int{"c": 3, "a": 1, "b": 2}))
+
+	for name, im := range map[string]IterableMap[string, int]{
+		"OrderedMap":   m,
+		"iteratorOnly": iteratorOnly[string, int]{m, m.Iterator},
+	} {
+		if got := slices.Collect(Keys(im)); !slices.Equal(got, []string{"a", "b", "c"}) {
+			t.Errorf("%s: Want Keys() == [a b c], Got %v", name, got)
+		}
+		if got := slices.Collect(Values(im)); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("%s: Want Values() == [1 2 3], Got %v", name, got)
+		}
+		if got := maps.Collect(Seq2(im)); !maps.Equal(got, map[string]int{"a": 1, "b": 2, "c": 3}) {
+			t.Errorf("%s: Want maps.Collect(Seq2()) == map[a:1 b:2 c:3], Got %v", name, got)
+		}
+		for k := range Keys(im) {
+			if k != "a" {
+				t.Errorf("%s: Want iteration to stop after a, Got %s", name, k)
+			}
+			break
+		}
+	}
+
+	w := NewMapWrapper[string, int]()
+	Insert[string, int](w, Seq2[string, int](m))
+	if !maps.Equal(map[string]int(w), map[string]int{"a": 1, "b": 2, "c": 3}) {
+		t.Errorf("Want MapWrapper map[a:1 b:2 c:3], Got %v", w)
+	}
+}
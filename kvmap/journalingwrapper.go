@@ -0,0 +1,140 @@
+package kvmap
+
+// journalOp is a recorded mutation of a map, with enough information to
+// reverse it.
+type journalOp[K, V any] struct {
+	// seq identifies the operation for Checkpoints.
+	seq uint64
+
+	key K
+	// old is the value of key before the operation, if hadOld is true.
+	old    V
+	hadOld bool
+	// new is the value of key after the operation, unless deleted is true.
+	new     V
+	deleted bool
+}
+
+// A Checkpoint identifies a state of a JournalingWrapper which it can be
+// rolled back to.
+type Checkpoint struct {
+	seq uint64
+}
+
+// JournalingWrapper wraps any kvmap.Interface, recording each Put and Delete
+// so that it can be undone and redone. Like a text editor, making a change
+// after undoing discards the undone changes. JournalingWrapper is not
+// thread-safe; wrap it in a ConcurrentWrapper for concurrent use.
+type JournalingWrapper[K, V any] struct {
+	Base Interface[K, V]
+
+	undo, redo []journalOp[K, V]
+	seq        uint64
+}
+
+func (m *JournalingWrapper[K, V]) record(op journalOp[K, V]) {
+	m.seq++
+	op.seq = m.seq
+	m.undo = append(m.undo, op)
+	m.redo = m.redo[:0]
+}
+
+func (m *JournalingWrapper[K, V]) Put(key K, val V) {
+	old, hadOld := m.Base.Get(key)
+	m.record(journalOp[K, V]{key: key, old: old, hadOld: hadOld, new: val})
+	m.Base.Put(key, val)
+}
+
+func (m *JournalingWrapper[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *JournalingWrapper[K, V]) DeleteAndGet(key K) (V, bool) {
+	old, hadOld := DeleteAndGet(m.Base, key)
+	if hadOld {
+		m.record(journalOp[K, V]{key: key, old: old, hadOld: true, deleted: true})
+	}
+	return old, hadOld
+}
+
+func (m *JournalingWrapper[K, V]) Get(key K) (V, bool) {
+	return m.Base.Get(key)
+}
+
+func (m *JournalingWrapper[K, V]) Has(key K) bool {
+	return m.Base.Has(key)
+}
+
+func (m *JournalingWrapper[K, V]) Len() int {
+	return m.Base.Len()
+}
+
+// Undo reverses the most recent Put or Delete which has not been undone,
+// returning false if there is nothing to undo.
+func (m *JournalingWrapper[K, V]) Undo() bool {
+	n := len(m.undo)
+	if n == 0 {
+		return false
+	}
+	op := m.undo[n-1]
+	m.undo = m.undo[:n-1]
+	if op.hadOld {
+		m.Base.Put(op.key, op.old)
+	} else {
+		m.Base.Delete(op.key)
+	}
+	m.redo = append(m.redo, op)
+	return true
+}
+
+// Redo reapplies the most recently undone operation, returning false if there
+// is nothing to redo.
+func (m *JournalingWrapper[K, V]) Redo() bool {
+	n := len(m.redo)
+	if n == 0 {
+		return false
+	}
+	op := m.redo[n-1]
+	m.redo = m.redo[:n-1]
+	if op.deleted {
+		m.Base.Delete(op.key)
+	} else {
+		m.Base.Put(op.key, op.new)
+	}
+	m.undo = append(m.undo, op)
+	return true
+}
+
+// Checkpoint returns a Checkpoint for the current state of m.
+func (m *JournalingWrapper[K, V]) Checkpoint() Checkpoint {
+	if n := len(m.undo); n > 0 {
+		return Checkpoint{m.undo[n-1].seq}
+	}
+	return Checkpoint{}
+}
+
+// RollbackTo undoes operations until m is in the state identified by c. It
+// returns false, without modifying m, if c was taken in a state that has since
+// been undone and replaced by other changes. Rolled back operations can be
+// redone.
+func (m *JournalingWrapper[K, V]) RollbackTo(c Checkpoint) bool {
+	i := len(m.undo)
+	for i > 0 && m.undo[i-1].seq > c.seq {
+		i--
+	}
+	if (i == 0 && c.seq != 0) || (i > 0 && m.undo[i-1].seq != c.seq) {
+		return false
+	}
+	for len(m.undo) > i {
+		m.Undo()
+	}
+	return true
+}
+
+// ClearJournal discards all undo and redo history, without modifying the
+// contents of m.
+func (m *JournalingWrapper[K, V]) ClearJournal() {
+	m.undo, m.redo = nil, nil
+}
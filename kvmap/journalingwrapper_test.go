@@ -0,0 +1,61 @@
+package kvmap
+
+import (
+	"testing"
+)
+
+func TestJournalingWrapper(t *testing.T) {
+	m := &JournalingWrapper[string, int]{Base: NewMapWrapper[string, int]()}
+	expect := func(want map[string]int) {
+		t.Helper()
+		if m.Len() != len(want) {
+			t.Errorf("Want Len() == %d, Got %d", len(want), m.Len())
+		}
+		for k, v := range want {
+			if got, ok := m.Get(k); !ok || got != v {
+				t.Errorf("Want Get(%q) == (%d, true), Got (%d, %t)", k, v, got, ok)
+			}
+		}
+	}
+
+	m.Put("a", 1)
+	m.Put("b", 2)
+	cp := m.Checkpoint()
+	m.Put("a", 3)
+	m.Delete("b")
+	m.Delete("missing")
+	expect(map[string]int{"a": 3})
+
+	if !m.Undo() {
+		t.Fatalf("Want Undo() == true, Got false")
+	}
+	expect(map[string]int{"a": 3, "b": 2})
+	if !m.Redo() {
+		t.Fatalf("Want Redo() == true, Got false")
+	}
+	expect(map[string]int{"a": 3})
+	if m.Redo() {
+		t.Errorf("Want Redo() == false with nothing to redo, Got true")
+	}
+
+	if !m.RollbackTo(cp) {
+		t.Fatalf("Want RollbackTo(cp) == true, Got false")
+	}
+	expect(map[string]int{"a": 1, "b": 2})
+
+	// Changing m after rolling back discards the rolled back operations,
+	// so checkpoints taken in that history are no longer valid.
+	m.Redo()
+	late := m.Checkpoint()
+	m.Undo()
+	m.Put("c", 4)
+	if m.RollbackTo(late) {
+		t.Errorf("Want RollbackTo(late) == false for discarded checkpoint, Got true")
+	}
+	expect(map[string]int{"a": 1, "b": 2, "c": 4})
+
+	if !m.RollbackTo(Checkpoint{}) {
+		t.Fatalf("Want RollbackTo(Checkpoint{}) == true, Got false")
+	}
+	expect(map[string]int{})
+}
@@ -0,0 +1,84 @@
+package kvmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes m as a JSON object whose members are in m's iteration
+// order. Keys are encoded with EncodeKey, and values with encoding/json.
+func MarshalJSON[K, V any](m IterableMap[K, V]) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	it := m.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		k, err := EncodeKey(e.Key())
+		if err != nil {
+			return nil, err
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(e.Value())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object into m, calling m.Put for each member
+// in document order. Keys are decoded with DecodeKey, and values with
+// encoding/json. Existing entries of m are kept unless overwritten.
+func UnmarshalJSON[K, V any](data []byte, m Interface[K, V]) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return fmt.Errorf("kvmap: cannot unmarshal JSON %v into a map", t)
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := DecodeKey[K](t.(string))
+		if err != nil {
+			return err
+		}
+		var val V
+		if err := dec.Decode(&val); err != nil {
+			return err
+		}
+		m.Put(key, val)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func (m *LinkedHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON[K, V](m)
+}
+
+// UnmarshalJSON adds the members of a JSON object to m in document order.
+func (m *LinkedHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON[K, V](data, m)
+}
+
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON[K, V](m)
+}
+
+// UnmarshalJSON adds the members of a JSON object to m.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON[K, V](data, m)
+}
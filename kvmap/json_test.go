@@ -0,0 +1,93 @@
+package kvmap
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type jsonTestPoint struct{ X, Y int }
+
+func init() {
+	RegisterKeyCodec(
+		func(p jsonTestPoint) (string, error) { return fmt.Sprintf("%d,%d", p.X, p.Y), nil },
+		func(s string) (p jsonTestPoint, err error) {
+			_, err = fmt.Sscanf(s, "%d,%d", &p.X, &p.Y)
+			return p, err
+		})
+}
+
+func TestLinkedHashMapJSONRoundTrip(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+
+	b, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Want MarshalJSON() to succeed, Got error: %v", err)
+	}
+	if want := `{"3":"c","1":"a","2":"b"}`; string(b) != want {
+		t.Errorf("Want %s, Got %s", want, b)
+	}
+
+	got := NewComparableLinkedHashMap[int, string]()
+	if err := got.UnmarshalJSON(b); err != nil {
+		t.Fatalf("Want UnmarshalJSON() to succeed, Got error: %v", err)
+	}
+	if got.String() != m.String() {
+		t.Errorf("Want %v, Got %v", m, got)
+	}
+}
+
+func TestJSONTextMarshalerKeys(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	m := NewOrderedMapFunc[time.Time, int](func(a, b time.Time) int { return a.Compare(b) })
+	m.Put(t0.Add(time.Hour), 2)
+	m.Put(t0, 1)
+
+	b, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Want MarshalJSON() to succeed, Got error: %v", err)
+	}
+	if want := `{"2024-01-02T03:04:05.000000006Z":1,"2024-01-02T04:04:05.000000006Z":2}`; string(b) != want {
+		t.Errorf("Want %s, Got %s", want, b)
+	}
+
+	got := NewOrderedMapFunc[time.Time, int](func(a, b time.Time) int { return a.Compare(b) })
+	if err := got.UnmarshalJSON(b); err != nil {
+		t.Fatalf("Want UnmarshalJSON() to succeed, Got error: %v", err)
+	}
+	if v, ok := got.Get(t0); !ok || v != 1 {
+		t.Errorf("Want Get(t0) == (1, true), Got (%d, %t)", v, ok)
+	}
+}
+
+func TestJSONRegisteredKeyCodec(t *testing.T) {
+	m := NewComparableLinkedHashMap[jsonTestPoint, bool]()
+	m.Put(jsonTestPoint{1, 2}, true)
+
+	b, err := MarshalJSON[jsonTestPoint, bool](m)
+	if err != nil || string(b) != `{"1,2":true}` {
+		t.Errorf(`Want ({"1,2":true}, nil), Got (%s, %v)`, b, err)
+	}
+	got := NewComparableLinkedHashMap[jsonTestPoint, bool]()
+	if err := UnmarshalJSON[jsonTestPoint, bool](b, got); err != nil || !got.Has(jsonTestPoint{1, 2}) {
+		t.Errorf("Want {1 2} decoded, Got %v, error: %v", got, err)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, string]()
+	for _, data := range []string{`[1]`, `{"x":"a"}`, `{"1":2}`} {
+		if err := m.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("Want UnmarshalJSON(%s) to fail, Got nil error", data)
+		}
+	}
+
+	unencodable := NewComparableLinkedHashMap[[2]int, int]()
+	unencodable.Put([2]int{1, 2}, 3)
+	if _, err := unencodable.MarshalJSON(); err == nil {
+		t.Errorf("Want MarshalJSON() with array keys to fail, Got nil error")
+	}
+}
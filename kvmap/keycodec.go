@@ -0,0 +1,99 @@
+package kvmap
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// keyCodec holds the registered encode and decode functions for a key type,
+// as func(K) (string, error) and func(string) (K, error) respectively.
+type keyCodec struct {
+	encode, decode any
+}
+
+// keyCodecs maps key types to their registered keyCodec.
+var keyCodecs sync.Map
+
+// RegisterKeyCodec registers the functions used by EncodeKey and DecodeKey to
+// convert keys of type K to and from text, taking precedence over K's
+// encoding.TextMarshaler and encoding.TextUnmarshaler implementations, if any.
+// It is intended to be called from init functions.
+func RegisterKeyCodec[K any](encode func(K) (string, error), decode func(string) (K, error)) {
+	keyCodecs.Store(reflect.TypeFor[K](), keyCodec{encode, decode})
+}
+
+// EncodeKey returns the text form of key used by the map serializers in this
+// package. In order of preference, it uses the codec registered for K with
+// RegisterKeyCodec, K's encoding.TextMarshaler implementation, or the
+// strconv formatting of K's underlying string, integer, float or bool type.
+func EncodeKey[K any](key K) (string, error) {
+	if c, ok := keyCodecs.Load(reflect.TypeFor[K]()); ok {
+		return c.(keyCodec).encode.(func(K) (string, error))(key)
+	}
+	if tm, ok := any(&key).(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	}
+	return "", fmt.Errorf("kvmap: cannot encode key of type %T as text", key)
+}
+
+// DecodeKey parses a key of type K from text produced by EncodeKey, using the
+// same order of preference.
+func DecodeKey[K any](s string) (key K, err error) {
+	if c, ok := keyCodecs.Load(reflect.TypeFor[K]()); ok {
+		return c.(keyCodec).decode.(func(string) (K, error))(s)
+	}
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err = tu.UnmarshalText([]byte(s))
+		return key, err
+	}
+
+	v := reflect.ValueOf(&key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(s, 10, v.Type().Bits()); err == nil {
+			v.SetInt(n)
+		}
+		return key, err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		if n, err = strconv.ParseUint(s, 10, v.Type().Bits()); err == nil {
+			v.SetUint(n)
+		}
+		return key, err
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+		return key, err
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			v.SetBool(b)
+		}
+		return key, err
+	}
+	return key, fmt.Errorf("kvmap: cannot decode key of type %T from text", key)
+}
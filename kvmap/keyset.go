@@ -0,0 +1,87 @@
+package kvmap
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// KeySetView is a live, set-like view of the keys of a map: it reflects later
+// changes to the map, and deleting a key from it deletes the key's entry from
+// the map. Keys cannot be added through it.
+type KeySetView[K, V any] struct {
+	m IterableMap[K, V]
+}
+
+// KeySet returns a live view of the keys of m.
+func KeySet[K, V any](m IterableMap[K, V]) KeySetView[K, V] {
+	return KeySetView[K, V]{m}
+}
+
+func (s KeySetView[K, V]) Has(key K) bool {
+	return s.m.Has(key)
+}
+
+// Delete removes key and its value from the underlying map.
+func (s KeySetView[K, V]) Delete(key K) {
+	s.m.Delete(key)
+}
+
+func (s KeySetView[K, V]) Len() int {
+	return s.m.Len()
+}
+
+// All returns an iter.Seq over the keys in the map's iteration order. The map
+// must not be modified during iteration.
+func (s KeySetView[K, V]) All() iter.Seq[K] {
+	return Keys(s.m)
+}
+
+func (s KeySetView[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("set[")
+	sep := ""
+	for k := range s.All() {
+		fmt.Fprintf(&sb, "%s%v", sep, k)
+		sep = " "
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// ValuesView is a live, read-only view of the values of a map, which reflects
+// later changes to the map. A value is counted once for each key it is the
+// value of.
+type ValuesView[K, V any] struct {
+	m     IterableMap[K, V]
+	equal compare.Comparator[V]
+}
+
+// ValuesCollection returns a live view of the values of m, whose Has compares
+// values with equal.
+func ValuesCollection[K, V any](m IterableMap[K, V], equal compare.Comparator[V]) ValuesView[K, V] {
+	return ValuesView[K, V]{m, equal}
+}
+
+// Has returns true if val is equal to the value of any key in the map. It
+// takes O(n) time.
+func (c ValuesView[K, V]) Has(val V) bool {
+	for v := range c.All() {
+		if c.equal(v, val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c ValuesView[K, V]) Len() int {
+	return c.m.Len()
+}
+
+// All returns an iter.Seq over the values in the map's iteration order. The
+// map must not be modified during iteration.
+func (c ValuesView[K, V]) All() iter.Seq[V] {
+	return Values(c.m)
+}
@@ -0,0 +1,49 @@
+package kvmap
+
+import (
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+func TestKeySet(t *testing.T) {
+	m := NewComparableLinkedHashMap[string, int]()
+	keys := KeySet[string, int](m)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	m.Put("c", 3)
+
+	if !keys.Has("b") || keys.Len() != 3 {
+		t.Errorf("Want live view with Has(b) and Len() == 3, Got %v", keys)
+	}
+	keys.Delete("b")
+	if m.Has("b") || m.Len() != 2 {
+		t.Errorf("Want Delete(b) to delete from the map, Got %v", m)
+	}
+	if got := slices.Collect(keys.All()); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("Want keys [a c], Got %v", got)
+	}
+	if s := keys.String(); s != "set[a c]" {
+		t.Errorf("Want set[a c], Got %s", s)
+	}
+}
+
+func TestValuesCollection(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	vals := ValuesCollection[string, int](m, compare.Equal[int])
+	m.Put("b", 2)
+	m.Put("a", 1)
+	m.Put("z", 2)
+
+	if !vals.Has(2) || vals.Has(3) {
+		t.Errorf("Want Has(2) && !Has(3), Got %t, %t", vals.Has(2), vals.Has(3))
+	}
+	if got := slices.Collect(vals.All()); !slices.Equal(got, []int{1, 2, 2}) || vals.Len() != 3 {
+		t.Errorf("Want values [1 2 2], Got %v", got)
+	}
+	m.Delete("a")
+	if vals.Has(1) {
+		t.Errorf("Want Has(1) == false after deleting its key, Got true")
+	}
+}
@@ -1,7 +1,9 @@
 package kvmap
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.org/jccarlson/collections"
@@ -35,15 +37,83 @@ type IterableMap[K, V any] interface {
 type kvMapOpts struct {
 	capacity   int
 	loadFactor float32
+
+	maxProbeLength int
+	maxFreeNodes   int
+
+	maxWeight int
+	weigher   any
+	onEvict   any
+
+	growthFactor float64
+	maxCapacity  int
 }
 
 // Option is an interface which wraps an adjustable parameter for a map at
 // creation. An Option should only be created via one of the functions below.
+//
+// Options are validated by the map constructors, which panic if an Option is
+// invalid, e.g. Capacity(-1). Constructors whose names end in E, such as
+// NewComparableLinkedHashMapE, return an error wrapping ErrInvalidOption
+// instead, and ValidateOptions checks Options in advance, so that
+// configuration mistakes can be reported rather than crash.
 type Option interface {
 	setOpt(*kvMapOpts)
+	// validate returns an error wrapping ErrInvalidOption if the Option's
+	// parameter is out of range.
+	validate() error
 	String() string
 }
 
+// ErrInvalidOption is wrapped by the errors returned for invalid Options.
+var ErrInvalidOption = errors.New("kvmap: invalid Option")
+
+// invalidOption returns an error wrapping ErrInvalidOption for o.
+func invalidOption(o Option, reason string) error {
+	return fmt.Errorf("%w %v: %s", ErrInvalidOption, o, reason)
+}
+
+// ValidateOptions returns an error wrapping ErrInvalidOption for each of opts
+// which is invalid, joined with errors.Join, or nil if they are all valid.
+// It can't check that a Weigher or OnEvict matches a map's types; the E
+// constructors report that too.
+func ValidateOptions(opts ...Option) error {
+	var errs []error
+	for _, opt := range opts {
+		if err := opt.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyOptions validates opts and applies them to o in order.
+func applyOptions(o *kvMapOpts, opts []Option) error {
+	if err := ValidateOptions(opts...); err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt.setOpt(o)
+	}
+	return nil
+}
+
+// must returns m, or panics with err if it is not nil. It turns the E
+// constructors into their panicking counterparts.
+func must[M any](m M, err error) M {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// mustApplyOptions is like applyOptions, but panics if an Option is invalid.
+func mustApplyOptions(o *kvMapOpts, opts []Option) {
+	if err := applyOptions(o, opts); err != nil {
+		panic(err)
+	}
+}
+
 type capOpt int
 
 func (o capOpt) setOpt(opts *kvMapOpts) {
@@ -56,12 +126,16 @@ func (o capOpt) String() string { return fmt.Sprintf("Capacity(%v)", int(o)) }
 // that it is only guaranteed that the capacity will be greater than or equal
 // to n.
 func Capacity(n int) Option {
-	if n < 0 {
-		panic("Capacity must be >= 0")
-	}
 	return capOpt(n)
 }
 
+func (o capOpt) validate() error {
+	if o < 0 {
+		return invalidOption(o, "must be >= 0")
+	}
+	return nil
+}
+
 type loadFactorOpt float32
 
 func (o loadFactorOpt) setOpt(opts *kvMapOpts) {
@@ -73,12 +147,191 @@ func (o loadFactorOpt) String() string { return fmt.Sprintf("LoadFactor(%v)", fl
 // Returns an Option which sets the desired load factor of the map. The load
 // factor must be in the range (0, 1].
 func LoadFactor(loadFactor float32) Option {
-	if loadFactor <= 0 || loadFactor > 1 {
-		panic(fmt.Sprintf("load factor %f out of range (0.0, 1.0]", loadFactor))
-	}
 	return loadFactorOpt(loadFactor)
 }
 
+func (o loadFactorOpt) validate() error {
+	if !(o > 0 && o <= 1) {
+		return invalidOption(o, "out of range (0.0, 1.0]")
+	}
+	return nil
+}
+
+type maxProbeLengthOpt int
+
+func (o maxProbeLengthOpt) setOpt(opts *kvMapOpts) {
+	opts.maxProbeLength = int(o)
+}
+
+func (o maxProbeLengthOpt) String() string { return fmt.Sprintf("MaxProbeLength(%v)", int(o)) }
+
+// Returns an Option which hardens a hash map against keys chosen to collide.
+// If an insertion probes more than n slots, the map's hasher is given a fresh
+// random seed and the table is rehashed. This is attempted at most once per
+// growth of the table, so keys which collide under every seed cannot cause
+// repeated rehashing.
+func MaxProbeLength(n int) Option {
+	return maxProbeLengthOpt(n)
+}
+
+func (o maxProbeLengthOpt) validate() error {
+	if o <= 0 {
+		return invalidOption(o, "must be > 0")
+	}
+	return nil
+}
+
+type maxFreeNodesOpt int
+
+func (o maxFreeNodesOpt) setOpt(opts *kvMapOpts) {
+	opts.maxFreeNodes = int(o)
+}
+
+func (o maxFreeNodesOpt) String() string { return fmt.Sprintf("MaxFreeNodes(%v)", int(o)) }
+
+// Returns an Option which keeps up to n of the tree nodes freed by deletions
+// from an OrderedMap, to be reused by subsequent Puts. This cuts allocations
+// for maps with many deletions and insertions, at the cost of holding the
+// memory of n nodes.
+func MaxFreeNodes(n int) Option {
+	return maxFreeNodesOpt(n)
+}
+
+func (o maxFreeNodesOpt) validate() error {
+	if o < 0 {
+		return invalidOption(o, "must be >= 0")
+	}
+	return nil
+}
+
+type maxWeightOpt int
+
+func (o maxWeightOpt) setOpt(opts *kvMapOpts) {
+	opts.maxWeight = int(o)
+}
+
+func (o maxWeightOpt) String() string { return fmt.Sprintf("MaxWeight(%v)", int(o)) }
+
+// Returns an Option which bounds the total weight of a map's entries, as
+// computed by its Weigher (by default every entry weighs 1). When a Put
+// exceeds the bound, the oldest entries are evicted until it is met again.
+// A value of 0 means the weight is unbounded, as with no MaxWeight Option.
+func MaxWeight(n int) Option {
+	return maxWeightOpt(n)
+}
+
+func (o maxWeightOpt) validate() error {
+	if o < 0 {
+		return invalidOption(o, "must be >= 0")
+	}
+	return nil
+}
+
+type weigherOpt struct {
+	f any
+}
+
+func (o weigherOpt) setOpt(opts *kvMapOpts) {
+	opts.weigher = o.f
+}
+
+func (o weigherOpt) String() string { return fmt.Sprintf("Weigher(%T)", o.f) }
+
+// Returns an Option which sets the function computing the weight of each
+// entry, e.g. its approximate size in bytes, for MaxWeight. f must return a
+// value >= 0. The key and value types of f must match those of the map,
+// otherwise the constructor panics.
+func Weigher[K, V any](f func(key K, val V) int) Option {
+	return weigherOpt{f}
+}
+
+func (o weigherOpt) validate() error { return nil }
+
+type onEvictOpt struct {
+	f any
+}
+
+func (o onEvictOpt) setOpt(opts *kvMapOpts) {
+	opts.onEvict = o.f
+}
+
+func (o onEvictOpt) String() string { return fmt.Sprintf("OnEvict(%T)", o.f) }
+
+// Returns an Option which registers f to be called with each entry a map
+// evicts to stay within its MaxWeight. f must not modify the map. The key and
+// value types of f must match those of the map, otherwise the constructor
+// panics.
+func OnEvict[K, V any](f func(key K, val V)) Option {
+	return onEvictOpt{f}
+}
+
+func (o onEvictOpt) validate() error { return nil }
+
+type growthFactorOpt float64
+
+func (o growthFactorOpt) setOpt(opts *kvMapOpts) {
+	opts.growthFactor = float64(o)
+}
+
+func (o growthFactorOpt) String() string { return fmt.Sprintf("GrowthFactor(%v)", float64(o)) }
+
+// Returns an Option which sets the factor by which a hash map's capacity is
+// multiplied when it grows (default: 2). A smaller factor, such as 1.5,
+// lowers the peak memory of a growing map at the cost of more frequent
+// rehashing. f must be > 1, and a power of 2 for a LinkedHashMap.
+func GrowthFactor(f float64) Option {
+	return growthFactorOpt(f)
+}
+
+func (o growthFactorOpt) validate() error {
+	if !(o > 1) || math.IsInf(float64(o), 1) {
+		return invalidOption(o, "must be > 1")
+	}
+	return nil
+}
+
+type maxCapacityOpt int
+
+func (o maxCapacityOpt) setOpt(opts *kvMapOpts) {
+	opts.maxCapacity = int(o)
+}
+
+func (o maxCapacityOpt) String() string { return fmt.Sprintf("MaxCapacity(%v)", int(o)) }
+
+// Returns an Option which bounds the capacity a hash map may grow to, so that
+// its memory use is bounded. Once the map can't grow, Putting a new key which
+// needs more room panics with ErrMaxCapacity, and the map's TryPut method
+// returns it instead. A Capacity above n is reduced to n.
+func MaxCapacity(n int) Option {
+	return maxCapacityOpt(n)
+}
+
+func (o maxCapacityOpt) validate() error {
+	if o <= 0 {
+		return invalidOption(o, "must be > 0")
+	}
+	return nil
+}
+
+// ErrMaxCapacity is returned by the TryPut method of a hash map, and passed to
+// panic by Put, when a new key doesn't fit without growing the map beyond its
+// MaxCapacity.
+var ErrMaxCapacity = errors.New("kvmap: map is at its maximum capacity")
+
+// grownCapacity returns the capacity a hash table of cap slots grows to, by
+// growthFactor (or doubling, if it is 0) and by at least one slot, but to no
+// more than maxCap if it is > 0.
+func grownCapacity(cap int, growthFactor float64, maxCap int) int {
+	if growthFactor == 0 {
+		growthFactor = 2
+	}
+	n := max(cap+1, int(math.Ceil(float64(cap)*growthFactor)))
+	if maxCap > 0 {
+		n = min(n, maxCap)
+	}
+	return n
+}
+
 // ForEach calls f(key, value) for each key-value pair in m.
 func ForEach[K, V any](m IterableMap[K, V], f func(key K, val V)) {
 	it := m.Iterator()
@@ -87,6 +340,30 @@ func ForEach[K, V any](m IterableMap[K, V], f func(key K, val V)) {
 	}
 }
 
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m. It uses m's DeleteAndGet method if it has one, as the maps of this
+// package do, and otherwise a Get followed by a Delete.
+func DeleteAndGet[K, V any](m Interface[K, V], key K) (V, bool) {
+	if d, ok := m.(interface{ DeleteAndGet(K) (V, bool) }); ok {
+		return d.DeleteAndGet(key)
+	}
+	val, ok := m.Get(key)
+	if ok {
+		m.Delete(key)
+	}
+	return val, ok
+}
+
+// DeepCloneInto puts a copy of each entry of src into dst, copying values with
+// collections.DeepClone and clone, and returns dst. dst is typically a new,
+// empty map of the same type as src.
+func DeepCloneInto[K, V any](dst Interface[K, V], src IterableMap[K, V], clone func(V) V) Interface[K, V] {
+	ForEach(src, func(key K, val V) {
+		dst.Put(key, collections.DeepClone(val, clone))
+	})
+	return dst
+}
+
 // Prints the provided IterableMap to a string. Can be used to easily implement
 // the String() method for IterableMap types.
 func IterableMapToString[K, V any](m IterableMap[K, V]) string {
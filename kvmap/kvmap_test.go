@@ -1,6 +1,10 @@
 package kvmap
 
 import (
+	"cmp"
+	"errors"
+	"slices"
+	"strconv"
 	"testing"
 	"unsafe"
 )
@@ -50,6 +54,22 @@ func TestKVMaps(t *testing.T) {
 			name: "MapWrapper",
 			m:    NewMapWrapper[testKey, string](Capacity(0)),
 		},
+		{
+			name: "ComparableCuckooHashMap",
+			m:    NewComparableCuckooHashMap[testKey, string](Capacity(4)),
+		},
+		{
+			name: "HashableKeyCuckooHashMap",
+			m:    NewHashableKeyCuckooHashMap[testKey, string](),
+		},
+		{
+			name: "ComparableHopscotchHashMap",
+			m:    NewComparableHopscotchHashMap[testKey, string](LoadFactor(1)),
+		},
+		{
+			name: "HashableKeyHopscotchHashMap",
+			m:    NewHashableKeyHopscotchHashMap[testKey, string](),
+		},
 	}
 
 	for _, tc := range tcs {
@@ -133,3 +153,244 @@ func TestKVMaps(t *testing.T) {
 		})
 	}
 }
+
+func TestNewOrderedMapFunc(t *testing.T) {
+	m := NewOrderedMapFunc[string, int](func(a, b string) int { return cmp.Compare(len(a), len(b)) })
+	m.Put("ccc", 3)
+	m.Put("a", 1)
+	m.Put("bb", 2)
+	m.Put("dd", 4)
+
+	got := ""
+	ForEach[string, int](m, func(k string, _ int) { got += k + " " })
+	if want := "a dd ccc "; got != want {
+		t.Errorf("Want keys ordered by length %q, Got %q", want, got)
+	}
+}
+
+// boundedHashMap is implemented by the hash maps supporting MaxCapacity.
+type boundedHashMap interface {
+	Interface[int, int]
+	TryPut(int, int) error
+	Stats() HashMapStats
+}
+
+func TestGrowthFactor(t *testing.T) {
+	for name, m := range map[string]boundedHashMap{
+		"HopscotchHashMap": NewComparableHopscotchHashMap[int, int](Capacity(64), GrowthFactor(1.5)),
+		"CuckooHashMap":    NewComparableCuckooHashMap[int, int](Capacity(64), GrowthFactor(1.5)),
+	} {
+		caps := []int{m.Stats().Capacity}
+		for i := 0; i < 1000; i++ {
+			m.Put(i, i)
+			if c := m.Stats().Capacity; c != caps[len(caps)-1] {
+				caps = append(caps, c)
+			}
+		}
+		for i := 1; i < len(caps); i++ {
+			// Growth is by the factor, unless the table had to be rebuilt
+			// larger again to fit its entries.
+			if caps[i] < caps[i-1]*3/2 || caps[i] > caps[i-1]*9/4+2 {
+				t.Errorf("%s: Want capacity to grow by a factor of 1.5, Got %v", name, caps)
+				break
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			if v, ok := m.Get(i); !ok || v != i {
+				t.Errorf("%s: Want Get(%d) == (%d, true), Got (%d, %t)", name, i, i, v, ok)
+			}
+		}
+	}
+
+	// LinkedHashMap capacities are powers of 2, so other factors are invalid.
+	for _, f := range []float64{1.5, 3} {
+		if _, err := NewComparableLinkedHashMapE[int, int](GrowthFactor(f)); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("LinkedHashMap: Want GrowthFactor(%v) to be invalid, Got %v", f, err)
+		}
+	}
+	m := NewComparableLinkedHashMap[int, int](GrowthFactor(4))
+	last := m.Stats().Capacity
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+		if c := m.Stats().Capacity; c != last {
+			if c != last*4 {
+				t.Errorf("LinkedHashMap: Want capacity to grow from %d to %d, Got %d", last, last*4, c)
+			}
+			last = c
+		}
+	}
+	if last == 32 {
+		t.Errorf("LinkedHashMap: Want capacity to grow, Got %d", last)
+	}
+}
+
+func TestMaxCapacity(t *testing.T) {
+	for name, m := range map[string]boundedHashMap{
+		"LinkedHashMap":    NewComparableLinkedHashMap[int, int](MaxCapacity(100)),
+		"HopscotchHashMap": NewComparableHopscotchHashMap[int, int](MaxCapacity(100)),
+		"CuckooHashMap":    NewComparableCuckooHashMap[int, int](MaxCapacity(100)),
+	} {
+		n := 0
+		for ; n < 200; n++ {
+			if err := m.TryPut(n, n); err != nil {
+				if err != ErrMaxCapacity {
+					t.Errorf("%s: Want ErrMaxCapacity, Got %v", name, err)
+				}
+				break
+			}
+		}
+		if st := m.Stats(); n == 200 || st.Capacity > 100 || st.Len != n {
+			t.Errorf("%s: Want TryPut to fail within capacity 100, Got %d Puts and %+v", name, n, st)
+		}
+		if err := m.TryPut(0, -1); err != nil {
+			t.Errorf("%s: Want TryPut of an existing key to succeed when full, Got %v", name, err)
+		}
+		for i := 0; i < n; i++ {
+			if v, ok := m.Get(i); !ok || (i > 0 && v != i) {
+				t.Errorf("%s: Want Get(%d) == (%d, true), Got (%d, %t)", name, i, i, v, ok)
+			}
+		}
+		m.Delete(1)
+		if err := m.TryPut(n, n); err != nil {
+			t.Errorf("%s: Want TryPut to succeed after a Delete, Got %v", name, err)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != ErrMaxCapacity {
+					t.Errorf("%s: Want Put to panic with ErrMaxCapacity, Got %v", name, r)
+				}
+			}()
+			m.Put(1000, 1000)
+		}()
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	if err := ValidateOptions(Capacity(0), LoadFactor(1), GrowthFactor(1.5), MaxWeight(0)); err != nil {
+		t.Errorf("Want no error for valid Options, Got %v", err)
+	}
+	for _, opt := range []Option{Capacity(-1), LoadFactor(0), LoadFactor(1.5), MaxProbeLength(0), MaxFreeNodes(-1), MaxWeight(-1), GrowthFactor(1), MaxCapacity(0)} {
+		if err := ValidateOptions(Capacity(8), opt); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Want ErrInvalidOption for %v, Got %v", opt, err)
+		}
+	}
+}
+
+func TestConstructorsE(t *testing.T) {
+	bad := []Option{Capacity(-1)}
+	if m, err := NewComparableLinkedHashMapE[int, int](bad...); m != nil || !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Want NewComparableLinkedHashMapE to return ErrInvalidOption, Got %v, %v", m, err)
+	}
+	if m, err := NewComparableHopscotchHashMapE[int, int](bad...); m != nil || !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Want NewComparableHopscotchHashMapE to return ErrInvalidOption, Got %v, %v", m, err)
+	}
+	if m, err := NewComparableCuckooHashMapE[int, int](bad...); m != nil || !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Want NewComparableCuckooHashMapE to return ErrInvalidOption, Got %v, %v", m, err)
+	}
+	if _, err := NewComparableLinkedHashMapE[int, int](OnEvict(func(string, int) {})); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Want ErrInvalidOption for a mismatched OnEvict, Got %v", err)
+	}
+
+	m, err := NewComparableLinkedHashMapE[int, int](Capacity(100))
+	if err != nil {
+		t.Fatalf("Want no error for valid Options, Got %v", err)
+	}
+	m.Put(1, 1)
+	if v, ok := m.Get(1); !ok || v != 1 {
+		t.Errorf("Want Get(1) == (1, true), Got (%d, %t)", v, ok)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Want constructor to panic with ErrInvalidOption, Got %v", err)
+		}
+	}()
+	NewOrderedMap[int, int](MaxFreeNodes(-1))
+}
+
+func TestDeleteAndGet(t *testing.T) {
+	tcs := []struct {
+		name string
+		m    IterableMap[testKey, int]
+	}{
+		{"LinkedHashMap", NewComparableLinkedHashMap[testKey, int]()},
+		{"OrderedMap", NewOrderedMap[testKey, int]()},
+		{"MapWrapper", NewMapWrapper[testKey, int]()},
+		{"CuckooHashMap", NewComparableCuckooHashMap[testKey, int]()},
+		{"HopscotchHashMap", NewComparableHopscotchHashMap[testKey, int]()},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := range 10 {
+				tc.m.Put(testKey(i), i*10)
+			}
+			var order []testKey
+			ForEach(tc.m, func(key testKey, _ int) { order = append(order, key) })
+
+			// Delete the first, a middle and the last key in iteration
+			// order, then check the rest still iterate in order.
+			deleted := []testKey{order[0], order[5], order[9]}
+			for _, k := range deleted {
+				if v, ok := DeleteAndGet(tc.m, k); !ok || v != int(k)*10 {
+					t.Errorf("Want DeleteAndGet(%d) == (%d, true), Got (%d, %t)", k, k*10, v, ok)
+				}
+				if v, ok := DeleteAndGet(tc.m, k); ok || v != 0 {
+					t.Errorf("Want second DeleteAndGet(%d) == (0, false), Got (%d, %t)", k, v, ok)
+				}
+			}
+			if l := tc.m.Len(); l != 7 {
+				t.Errorf("Want Len() == 7, Got %d", l)
+			}
+			var got []testKey
+			ForEach(tc.m, func(key testKey, _ int) { got = append(got, key) })
+			want := []testKey{order[1], order[2], order[3], order[4], order[6], order[7], order[8]}
+			if _, unordered := tc.m.(MapWrapper[testKey, int]); unordered {
+				slices.Sort(got)
+				slices.Sort(want)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("Want iteration after deletes %v, Got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestWrappersDeleteAndGet(t *testing.T) {
+	tcs := []struct {
+		name string
+		m    Interface[int, string]
+	}{
+		{"ShardedMap", NewComparableShardedMap[int, string](4)},
+		{"ObservableWrapper", &ObservableWrapper[int, string]{Base: NewMapWrapper[int, string]()}},
+		{"JournalingWrapper", &JournalingWrapper[int, string]{Base: NewMapWrapper[int, string]()}},
+		{"SpillMap", NewSpillMap[int, string](newTestLogSpillStorage(t), TextCodec[int](), TextCodec[string](), MaxWeight(5))},
+		{"ExpiryMap", NewExpiryMap[int, string]()},
+		{"VersionedMap", NewVersionedMap[int, string]()},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := tc.m.(interface{ DeleteAndGet(int) (string, bool) })
+			if !ok {
+				t.Fatalf("Want a DeleteAndGet method, Got none")
+			}
+			for i := 0; i < 20; i++ {
+				tc.m.Put(i, strconv.Itoa(i))
+			}
+			for _, k := range []int{0, 7, 19} {
+				if v, ok := d.DeleteAndGet(k); !ok || v != strconv.Itoa(k) {
+					t.Errorf("Want DeleteAndGet(%d) == (%[1]d, true), Got (%s, %t)", k, v, ok)
+				}
+				if v, ok := d.DeleteAndGet(k); ok || v != "" {
+					t.Errorf("Want second DeleteAndGet(%d) == (\"\", false), Got (%q, %t)", k, v, ok)
+				}
+				if tc.m.Has(k) {
+					t.Errorf("Want Has(%d) == false after DeleteAndGet, Got true", k)
+				}
+			}
+			if l := tc.m.Len(); l != 17 {
+				t.Errorf("Want Len() == 17, Got %d", l)
+			}
+		})
+	}
+}
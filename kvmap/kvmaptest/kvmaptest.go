@@ -0,0 +1,94 @@
+// Package kvmaptest provides utilities for testing types used with package
+// kvmap, such as custom MapHashers.
+package kvmaptest
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/bits"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+// HasherReport describes how well a MapHasher spreads a sample of keys.
+type HasherReport struct {
+	// Keys is the number of keys sampled.
+	Keys int
+	// DistinctHashes is the number of distinct 64-bit hashes of the keys.
+	DistinctHashes int
+	// CollisionRate is the fraction of keys whose full hash equals that of an
+	// earlier key. For a sample of distinct keys it should be ~0; a high rate
+	// usually means the key serializer discards information. Duplicate keys
+	// in the sample are counted as collisions.
+	CollisionRate float64
+
+	// Buckets is the number of buckets the keys were distributed over, the
+	// smallest power of 2 >= Keys, using the low bits of each hash as hash
+	// maps in package kvmap do.
+	Buckets int
+	// EmptyBuckets is the number of buckets no key fell into. For a uniform
+	// hash it is about Buckets/e.
+	EmptyBuckets int
+	// MaxBucketLoad is the largest number of keys in a single bucket.
+	MaxBucketLoad int
+	// ChiSquared is the chi-squared statistic of the bucket loads divided by
+	// its degrees of freedom. It is close to 1 for a uniform hash, and much
+	// larger for a skewed one.
+	ChiSquared float64
+
+	// MaxBitBias is the largest deviation, across all 64 hash bits, of the
+	// fraction of keys with the bit set from the ideal 0.5.
+	MaxBitBias float64
+}
+
+func (r HasherReport) String() string {
+	return fmt.Sprintf("keys: %d, collision rate: %.4f, buckets: %d (empty: %d, max load: %d, chi-squared: %.3f), max bit bias: %.4f",
+		r.Keys, r.CollisionRate, r.Buckets, r.EmptyBuckets, r.MaxBucketLoad, r.ChiSquared, r.MaxBitBias)
+}
+
+// AnalyzeHasher hashes each key in keys with h and reports on the
+// distribution of the results. The sample should be representative of the
+// keys the hasher will see in production, and contain distinct keys.
+func AnalyzeHasher[K any](h kvmap.MapHasher[K], keys iter.Seq[K]) HasherReport {
+	var hashes []uint64
+	seen := map[uint64]struct{}{}
+	var bitCounts [64]int
+	for k := range keys {
+		hash := h.Hash(&k)
+		hashes = append(hashes, hash)
+		seen[hash] = struct{}{}
+		for b := hash; b != 0; b &= b - 1 {
+			bitCounts[bits.TrailingZeros64(b)]++
+		}
+	}
+
+	r := HasherReport{Keys: len(hashes), DistinctHashes: len(seen)}
+	if r.Keys == 0 {
+		return r
+	}
+	r.CollisionRate = float64(r.Keys-r.DistinctHashes) / float64(r.Keys)
+
+	r.Buckets = 1 << bits.Len(uint(r.Keys-1))
+	loads := make([]int, r.Buckets)
+	for _, hash := range hashes {
+		loads[hash&uint64(r.Buckets-1)]++
+	}
+	expected := float64(r.Keys) / float64(r.Buckets)
+	for _, l := range loads {
+		if l == 0 {
+			r.EmptyBuckets++
+		}
+		r.MaxBucketLoad = max(r.MaxBucketLoad, l)
+		d := float64(l) - expected
+		r.ChiSquared += d * d / expected
+	}
+	if r.Buckets > 1 {
+		r.ChiSquared /= float64(r.Buckets - 1)
+	}
+
+	for _, c := range bitCounts {
+		r.MaxBitBias = max(r.MaxBitBias, math.Abs(float64(c)/float64(r.Keys)-0.5))
+	}
+	return r
+}
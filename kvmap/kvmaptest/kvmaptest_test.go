@@ -0,0 +1,52 @@
+package kvmaptest
+
+import (
+	"encoding/binary"
+	"iter"
+	"testing"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+func ints(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestAnalyzeHasherGood(t *testing.T) {
+	r := AnalyzeHasher(kvmap.ComparableMapHasher[int](), ints(4096))
+	if r.Keys != 4096 || r.Buckets != 4096 {
+		t.Errorf("Want 4096 keys over 4096 buckets, Got %v", r)
+	}
+	if r.CollisionRate != 0 {
+		t.Errorf("Want no collisions, Got %v", r)
+	}
+	if r.ChiSquared > 1.5 || r.MaxBitBias > 0.05 {
+		t.Errorf("Want a uniform distribution, Got %v", r)
+	}
+}
+
+func TestAnalyzeHasherLossySerializer(t *testing.T) {
+	// Only the low byte of each key is serialized.
+	h := kvmap.CustomMapHasher(func(k *int) []byte {
+		return binary.LittleEndian.AppendUint64(nil, uint64(*k&0xff))
+	})
+	r := AnalyzeHasher(h, ints(4096))
+	if r.DistinctHashes != 256 {
+		t.Errorf("Want 256 distinct hashes, Got %v", r)
+	}
+	if r.CollisionRate < 0.9 || r.ChiSquared < 10 || r.MaxBucketLoad < 16 {
+		t.Errorf("Want a skewed distribution, Got %v", r)
+	}
+}
+
+func TestAnalyzeHasherEmpty(t *testing.T) {
+	if r := AnalyzeHasher(kvmap.ComparableMapHasher[int](), ints(0)); r != (HasherReport{}) {
+		t.Errorf("Want empty report, Got %v", r)
+	}
+}
@@ -2,6 +2,7 @@ package kvmap
 
 import (
 	"fmt"
+	"iter"
 	"math"
 
 	"github.org/jccarlson/collections"
@@ -14,6 +15,7 @@ type linkedHashMapEntry[K any, V any] struct {
 	value *V
 
 	hashCache uint64
+	weight    int
 
 	prev, next *linkedHashMapEntry[K, V]
 }
@@ -30,14 +32,13 @@ func (e *linkedHashMapEntry[K, V]) SetValue(v V) {
 	*(e.value) = v
 }
 
-func initLinkedHashMapOptions(opts []Option) kvMapOpts {
+func initLinkedHashMapOptions(opts []Option) (kvMapOpts, error) {
 	r := kvMapOpts{
 		capacity:   defaultCap,
 		loadFactor: defaultLoadFactor,
 	}
-
-	for _, opt := range opts {
-		opt.setOpt(&r)
+	if err := applyOptions(&r, opts); err != nil {
+		return r, err
 	}
 
 	// Round capacity up to a power of 2 (otherwise quadratic probing fails),
@@ -50,9 +51,26 @@ func initLinkedHashMapOptions(opts []Option) kvMapOpts {
 		}
 	}
 	if n >= 0 {
-		panic(fmt.Sprintf("LinkedHashMap initial capacity %d out of range", n))
+		return r, fmt.Errorf("%w: LinkedHashMap initial capacity %d out of range", ErrInvalidOption, n)
+	}
+
+	// Probing requires power-of-2 capacities, so only a growth factor which
+	// is a power of 2 keeps them so.
+	if frac, _ := math.Frexp(r.growthFactor); r.growthFactor != 0 && frac != 0.5 {
+		return r, fmt.Errorf("%w: LinkedHashMap GrowthFactor %v is not a power of 2", ErrInvalidOption, r.growthFactor)
+	}
+
+	// Round the maximum capacity down to a power of 2, but no lower than the
+	// initial capacity allows.
+	if r.maxCapacity > 0 {
+		c := minCap
+		for c<<1 > 0 && c<<1 <= r.maxCapacity {
+			c <<= 1
+		}
+		r.maxCapacity = c
+		r.capacity = min(r.capacity, c)
 	}
-	return r
+	return r, nil
 }
 
 const minCap = 1 << 3     // 8
@@ -64,41 +82,125 @@ const defaultLoadFactor = 0.75
 // capacity.
 const stepCheckProbabilityAtLoadFactor = 0.25
 
-// NewComparableLinkedHashMap returns a pointer to a new LinkedHashMap with
-// comparable keys, and uses the == operator to compare keys.
-func NewComparableLinkedHashMap[K comparable, V any](opts ...Option) *LinkedHashMap[K, V] {
-	o := initLinkedHashMapOptions(opts)
+// initEviction returns the weigher and eviction callback for a LinkedHashMap
+// from o, or an error if their types don't match the map's.
+func initEviction[K, V any](o kvMapOpts) (weigher func(K, V) int, onEvict func(K, V), err error) {
+	weigher = func(K, V) int { return 1 }
+	if o.weigher != nil {
+		f, ok := o.weigher.(func(K, V) int)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: Weigher %T does not match map type %T", ErrInvalidOption, o.weigher, f)
+		}
+		weigher = func(key K, val V) int {
+			w := f(key, val)
+			if w < 0 {
+				panic(fmt.Sprintf("Weigher returned negative weight %d for key %v", w, key))
+			}
+			return w
+		}
+	}
+	if o.onEvict != nil {
+		var ok bool
+		if onEvict, ok = o.onEvict.(func(K, V)); !ok {
+			return nil, nil, fmt.Errorf("%w: OnEvict callback %T does not match map type %T", ErrInvalidOption, o.onEvict, onEvict)
+		}
+	}
+	return weigher, onEvict, nil
+}
 
-	return &LinkedHashMap[K, V]{
-		comparator: compare.Equal[K],
-		hasher:     ComparableMapHasher[K](),
+// newLinkedHashMap returns a pointer to a new LinkedHashMap configured by
+// opts, without a hasher or comparator, or an error if opts are invalid.
+func newLinkedHashMap[K, V any](opts []Option) (*LinkedHashMap[K, V], error) {
+	o, err := initLinkedHashMapOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	weigher, onEvict, err := initEviction[K, V](o)
+	if err != nil {
+		return nil, err
+	}
 
+	return &LinkedHashMap[K, V]{
 		loadFactor: o.loadFactor,
 		stepCheck:  int(math.Round(math.Log(stepCheckProbabilityAtLoadFactor) / math.Log(float64(o.loadFactor)))),
 
-		cap: o.capacity,
-	}
+		cap:            o.capacity,
+		maxCap:         o.maxCapacity,
+		growthFactor:   o.growthFactor,
+		maxProbeLength: o.maxProbeLength,
+
+		maxWeight: o.maxWeight,
+		weigher:   weigher,
+		onEvict:   onEvict,
+	}, nil
+}
+
+// NewComparableLinkedHashMap returns a pointer to a new LinkedHashMap with
+// comparable keys, and uses the == operator to compare keys.
+func NewComparableLinkedHashMap[K comparable, V any](opts ...Option) *LinkedHashMap[K, V] {
+	return must(NewComparableLinkedHashMapE[K, V](opts...))
+}
+
+// NewComparableLinkedHashMapE is like NewComparableLinkedHashMap, but returns
+// an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewComparableLinkedHashMapE[K comparable, V any](opts ...Option) (*LinkedHashMap[K, V], error) {
+	return NewLinkedHashMapWithHasherE[K, V](ComparableMapHasher[K](), compare.Equal[K], opts...)
 }
 
 // NewHashableKeyLinkedHashMap returns a pointer to a new LinkedHashMap with
 // HashableKey keys. This can be used to create maps with non-comparable keys
 // or which don't use the == operator for comparison.
 func NewHashableKeyLinkedHashMap[K HashableKey[K], V any](opts ...Option) *LinkedHashMap[K, V] {
-	o := initLinkedHashMapOptions(opts)
-	return &LinkedHashMap[K, V]{
-		comparator: compare.EqualableComparator[K],
-		hasher:     HashableKeyMapHasher[K](),
-		loadFactor: o.loadFactor,
-		stepCheck:  int(math.Round(math.Log(stepCheckProbabilityAtLoadFactor) / math.Log(float64(o.loadFactor)))),
+	return must(NewHashableKeyLinkedHashMapE[K, V](opts...))
+}
+
+// NewHashableKeyLinkedHashMapE is like NewHashableKeyLinkedHashMap, but
+// returns an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewHashableKeyLinkedHashMapE[K HashableKey[K], V any](opts ...Option) (*LinkedHashMap[K, V], error) {
+	return NewLinkedHashMapWithHasherE[K, V](HashableKeyMapHasher[K](), compare.EqualableComparator[K], opts...)
+}
 
-		cap: o.capacity,
+// NewLinkedHashMapWithHasher returns a pointer to a new LinkedHashMap which
+// hashes keys with hasher and compares them with equal. equal must be
+// consistent with hasher: keys which are equal must have equal hashes.
+func NewLinkedHashMapWithHasher[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) *LinkedHashMap[K, V] {
+	return must(NewLinkedHashMapWithHasherE[K, V](hasher, equal, opts...))
+}
+
+// NewLinkedHashMapWithHasherE is like NewLinkedHashMapWithHasher, but returns
+// an error wrapping ErrInvalidOption instead of panicking if opts are
+// invalid.
+func NewLinkedHashMapWithHasherE[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) (*LinkedHashMap[K, V], error) {
+	m, err := newLinkedHashMap[K, V](opts)
+	if err != nil {
+		return nil, err
 	}
+	m.hasher, m.comparator = hasher, equal
+	return m, nil
 }
 
 // LinkedHashMap is a hash map which can store keys and values of any type, and
 // can iterate over inserted key-value pairs in insertion-order. LinkedHashMap
-// supports the Capacity() (default: 32) and the LoadFactor() (default: 0.75)
-// Options; other Options will panic.
+// supports the Capacity() (default: 32), LoadFactor() (default: 0.75),
+// GrowthFactor() (default: 2), MaxCapacity() (default: unbounded),
+// MaxProbeLength() (default: disabled), MaxWeight() (default: unbounded),
+// Weigher() and OnEvict() Options; other Options will panic. Its probing
+// requires a capacity which is a power of 2, so MaxCapacity() is rounded down
+// to one, and a GrowthFactor() which isn't a power of 2 is invalid.
+//
+// With MaxWeight, a LinkedHashMap acts as a bounded buffer: Put evicts the
+// least recently Put entries until the total weight is within the bound. An
+// entry heavier than the bound is never stored.
+//
+// A LinkedHashMap holding 8 or fewer entries keeps them only in its linked
+// list, and allocates its hash table when it grows beyond that.
+//
+// The zero value of a LinkedHashMap with comparable keys is an empty map
+// ready to use, configured like NewComparableLinkedHashMap with no Options,
+// so that it can be embedded in structs like a built-in map. The zero value
+// of a LinkedHashMap with other keys panics when first Put to.
 type LinkedHashMap[K any, V any] struct {
 	comparator compare.Comparator[K]
 	hasher     MapHasher[K]
@@ -116,36 +218,94 @@ type LinkedHashMap[K any, V any] struct {
 	size int
 	// cap is the maximum number of keys the map can currently hold.
 	cap int
+	// maxCap is the capacity the map may grow to, or 0 if unbounded.
+	maxCap       int
+	growthFactor float64
 	// nkeys is the number of keys (including tombstones) in the map.
 	nkeys int
 
 	head, tail *linkedHashMapEntry[K, V]
+
+	// maxProbeLength is the insertion probe length beyond which the hasher is
+	// re-seeded. Zero disables re-seeding.
+	maxProbeLength int
+	// reseedSpent is true if the hasher has been re-seeded since the table
+	// last grew. Re-seeding again cannot help if keys collide regardless of
+	// seed.
+	reseedSpent bool
+
+	longestProbe, rehashes, reseeds int
+
+	// maxWeight is the bound on weight, or 0 if unbounded.
+	maxWeight int
+	// weight is the total weight of the valid entries.
+	weight  int
+	weigher func(K, V) int
+	onEvict func(K, V)
 }
 
 func (m *LinkedHashMap[K, V]) maybeResizeAndRehash() {
 	if float32(m.nkeys)/float32(m.cap) >= m.loadFactor {
 		// If most of the space is taken by tombstones, keep the same capacity
 		// and rehash to clear the tombstones. Otherwise, double the capacity.
-		if m.nkeys < m.size*2 {
+		if m.nkeys < m.size*2 && m.canGrow() {
 			if m.cap<<1 < minCap {
 				panic("LinkedHashMap capacity out-of-range")
 			}
-			m.cap <<= 1
+			m.cap = m.grownCap()
+			// A larger table spreads keys differently, so allow another
+			// re-seed if probe lengths become pathological again.
+			m.reseedSpent = false
 		}
+		m.rehash(false /*reseed=*/)
+	}
+}
 
-		tmpEntries := m.entries
-		m.entries = make([]*linkedHashMapEntry[K, V], m.cap)
-		m.size, m.nkeys = 0, 0
-		for _, e := range tmpEntries {
-			if e == nil || e.key == nil || e.value == nil {
-				continue
-			}
-			m.emplace(e, false /*canReplace=*/)
+// canGrow returns true if m's hash table may grow.
+func (m *LinkedHashMap[K, V]) canGrow() bool {
+	return m.maxCap == 0 || m.cap < m.maxCap
+}
+
+// grownCap returns the capacity m's hash table grows to, by the growth factor
+// but to no more than maxCap. Both are powers of 2, so the result is too.
+func (m *LinkedHashMap[K, V]) grownCap() int {
+	return grownCapacity(m.cap, m.growthFactor, m.maxCap)
+}
+
+// full returns true if m can't hold another key without growing beyond its
+// maximum capacity.
+func (m *LinkedHashMap[K, V]) full() bool {
+	return !m.canGrow() && float32(m.size+1)/float32(m.cap) >= m.loadFactor
+}
+
+// rehash rebuilds the hash table at the current capacity, dropping
+// tombstones. If reseed is true, the hasher is given a fresh seed and the
+// hash of every live entry is recomputed.
+func (m *LinkedHashMap[K, V]) rehash(reseed bool) {
+	if reseed {
+		m.hasher.Reseed()
+		m.reseeds++
+	}
+	m.rehashes++
+	m.longestProbe = 0
+
+	tmpEntries := m.entries
+	m.entries = make([]*linkedHashMapEntry[K, V], m.cap)
+	m.size, m.nkeys = 0, 0
+	for _, e := range tmpEntries {
+		if e == nil || e.key == nil || e.value == nil {
+			continue
+		}
+		if reseed {
+			e.hashCache = m.hasher.Hash(e.key)
 		}
+		m.emplace(e, false /*canReplace=*/)
 	}
 }
 
-func (m *LinkedHashMap[K, V]) emplace(entry *linkedHashMapEntry[K, V], canReplace bool) {
+// emplace inserts entry into the hash table and returns the number of probes
+// taken to find its slot.
+func (m *LinkedHashMap[K, V]) emplace(entry *linkedHashMapEntry[K, V], canReplace bool) int {
 	if m.cap == m.nkeys {
 		m.maybeResizeAndRehash()
 	}
@@ -182,6 +342,7 @@ func (m *LinkedHashMap[K, V]) emplace(entry *linkedHashMapEntry[K, V], canReplac
 				// replacing element as the tail.
 				currEntry.next.prev = currEntry.prev
 				m.size--
+				m.weight -= currEntry.weight
 			}
 
 			m.entries[hIdx] = entry
@@ -193,17 +354,122 @@ func (m *LinkedHashMap[K, V]) emplace(entry *linkedHashMapEntry[K, V], canReplac
 		}
 		step++
 	}
+	if step > m.longestProbe {
+		m.longestProbe = step
+	}
 	if step >= m.stepCheck {
 		// Lots of collisions; check if rehash is needed.
 		m.maybeResizeAndRehash()
 	}
+	return step
+}
+
+// find returns the index of the slot holding key (which may be a tombstone),
+// or -1 if key has never been inserted since the last rehash.
+func (m *LinkedHashMap[K, V]) find(key *K) int {
+	if m.entries == nil {
+		return -1
+	}
+	capMask := m.cap - 1
+	h := m.hasher.Hash(key)
+	step := 0
+	for hIdx := int(h) & capMask; step < m.cap; hIdx = (hIdx + step) & capMask {
+		currEntry := m.entries[hIdx]
+		if currEntry == nil {
+			return -1
+		}
+		if h == currEntry.hashCache && m.comparator(*currEntry.key, *key) {
+			return hIdx
+		}
+		step++
+	}
+	// Quadratic probing visits every slot of a power-of-2 table within cap
+	// steps, so the table is full and key is not in it.
+	return -1
+}
+
+// smallMapMax is the number of entries a LinkedHashMap holds before it builds
+// its hash table. Until then, keys are found by a linear scan of the linked
+// list, which is faster than hashing for so few entries, and saves
+// allocating a table for maps which never grow.
+const smallMapMax = 8
+
+// findSmall returns the entry for key by scanning the linked list, or nil if
+// key is not in m.
+func (m *LinkedHashMap[K, V]) findSmall(key *K) *linkedHashMapEntry[K, V] {
+	for e := m.head; e != nil; e = e.next {
+		if m.comparator(*e.key, *key) {
+			return e
+		}
+	}
+	return nil
+}
+
+// lookup returns the valid entry for key, or nil if key is not in m.
+func (m *LinkedHashMap[K, V]) lookup(key *K) *linkedHashMapEntry[K, V] {
+	if m.entries == nil {
+		return m.findSmall(key)
+	}
+	hIdx := m.find(key)
+	if hIdx < 0 || m.entries[hIdx].value == nil {
+		return nil
+	}
+	return m.entries[hIdx]
+}
+
+// buildTable allocates the hash table and inserts every entry of the linked
+// list into it.
+func (m *LinkedHashMap[K, V]) buildTable() {
+	m.entries = make([]*linkedHashMapEntry[K, V], m.cap)
+	m.size, m.nkeys = 0, 0
+	for e := m.head; e != nil; e = e.next {
+		e.hashCache = m.hasher.Hash(e.key)
+		m.emplace(e, false /*canReplace=*/)
+	}
+}
+
+// lazyInit initializes the zero value of m like NewComparableLinkedHashMap.
+// Every constructor sets weigher, so it is nil only for the zero value.
+func (m *LinkedHashMap[K, V]) lazyInit() {
+	if m.weigher != nil {
+		return
+	}
+	hasher, equal := runtimeComparableMapHasher[K]("LinkedHashMap")
+	*m = *must(NewLinkedHashMapWithHasherE[K, V](hasher, equal))
 }
 
+// Put maps key to val, making it the most recently Put key. It panics with
+// ErrMaxCapacity if key is new and m can't grow to make room for it.
 func (m *LinkedHashMap[K, V]) Put(key K, val V) {
+	if err := m.TryPut(key, val); err != nil {
+		panic(err)
+	}
+}
+
+// TryPut is like Put, but returns ErrMaxCapacity rather than panicking, in
+// which case m is unchanged.
+func (m *LinkedHashMap[K, V]) TryPut(key K, val V) error {
+	m.lazyInit()
+	if m.full() && m.lookup(&key) == nil {
+		return ErrMaxCapacity
+	}
+	w := m.weigher(key, val)
+	if m.maxWeight > 0 && w > m.maxWeight {
+		// The entry could never fit; storing it would only flush the map.
+		if e := m.lookup(&key); e != nil {
+			m.unlink(e)
+		}
+		return nil
+	}
 	if m.entries == nil {
-		m.entries = make([]*linkedHashMapEntry[K, V], m.cap)
+		if old := m.findSmall(&key); old != nil {
+			m.unlink(old)
+		} else if m.size >= smallMapMax {
+			m.buildTable()
+		}
 	}
-	e := &linkedHashMapEntry[K, V]{key: &key, value: &val, hashCache: m.hasher.Hash(&key), prev: m.tail}
+	e := &linkedHashMapEntry[K, V]{key: &key, value: &val, weight: w, prev: m.tail}
+	m.weight += w
 	if m.head == nil {
 		m.head = e
 	}
@@ -211,66 +477,152 @@ func (m *LinkedHashMap[K, V]) Put(key K, val V) {
 		e.prev.next = e
 	}
 	m.tail = e
-	m.emplace(e, true /*canReplace=*/)
+	if m.entries == nil {
+		m.size++
+	} else {
+		e.hashCache = m.hasher.Hash(&key)
+		if step := m.emplace(e, true /*canReplace=*/); m.maxProbeLength > 0 && step > m.maxProbeLength && !m.reseedSpent {
+			// The probe sequence is far longer than the load factor explains,
+			// which suggests keys chosen to collide under the current seed.
+			m.reseedSpent = true
+			m.rehash(true /*reseed=*/)
+		}
+	}
+	for m.maxWeight > 0 && m.weight > m.maxWeight {
+		m.evictOldest()
+	}
+	return nil
+}
+
+// unlink removes the valid entry e from the iteration list. If m has a hash
+// table, e's slot becomes a tombstone.
+func (m *LinkedHashMap[K, V]) unlink(e *linkedHashMapEntry[K, V]) {
+	if e.prev == nil {
+		m.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		m.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.value = nil
+	e.next, e.prev = nil, nil
+	m.size--
+	m.weight -= e.weight
+}
+
+// moveToBack makes the valid entry e the last in the iteration list, as if it
+// was the most recently Put, without changing its slot in the hash table.
+func (m *LinkedHashMap[K, V]) moveToBack(e *linkedHashMapEntry[K, V]) {
+	if e == m.tail {
+		return
+	}
+	if e.prev == nil {
+		m.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	e.next.prev = e.prev
+	e.prev, e.next = m.tail, nil
+	m.tail.next = e
+	m.tail = e
+}
+
+// evictOldest removes the head entry and passes it to m.onEvict.
+func (m *LinkedHashMap[K, V]) evictOldest() {
+	key, val := *m.head.key, *m.head.value
+	m.unlink(m.head)
+	if m.onEvict != nil {
+		m.onEvict(key, val)
+	}
 }
 
 func (m *LinkedHashMap[K, V]) Get(key K) (val V, ok bool) {
-	capMask := m.cap - 1
-	h := m.hasher.Hash(&key)
-	step := 0
-	for hIdx := int(h) & capMask; ; hIdx = (hIdx + step) & capMask {
-		currEntry := m.entries[hIdx]
-		if currEntry.key == nil {
-			return
-		}
-		if h == currEntry.hashCache && m.comparator(*currEntry.key, key) {
-			if currEntry.value == nil {
-				return
-			}
-			return *currEntry.value, true
-		}
-		step++
+	if e := m.lookup(&key); e != nil {
+		return *e.value, true
 	}
+	return
 }
 
 func (m *LinkedHashMap[K, V]) Delete(key K) {
-	capMask := m.cap - 1
-	h := m.hasher.Hash(&key)
-	step := 0
-	for hIdx := int(h) & capMask; ; hIdx = (hIdx + step) & capMask {
-		currEntry := m.entries[hIdx]
-		if currEntry.key == nil {
-			return
-		}
-		if h == currEntry.hashCache && m.comparator(*currEntry.key, key) {
-			if currEntry.prev != nil {
-				currEntry.prev.next = currEntry.next
-			}
-			if currEntry.next != nil {
-				currEntry.next.prev = currEntry.prev
-			}
-			m.entries[hIdx].value = nil
-			m.entries[hIdx].next, m.entries[hIdx].prev = nil, nil
-			m.size--
-			return
-		}
-		step++
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *LinkedHashMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	_, val, ok = m.take(m.lookup(&key))
+	return val, ok
+}
+
+// Take removes key from m and returns its value, or false if key was not in
+// m. It is the same as DeleteAndGet.
+func (m *LinkedHashMap[K, V]) Take(key K) (V, bool) {
+	return m.DeleteAndGet(key)
+}
+
+// TakeFirst removes the first entry of m in iteration order, which is the
+// oldest, and returns its key and value, or false if m is empty. With
+// TakeFirst, a LinkedHashMap can be consumed as a FIFO queue of entries.
+func (m *LinkedHashMap[K, V]) TakeFirst() (K, V, bool) {
+	return m.take(m.head)
+}
+
+// TakeLast removes the last entry of m in iteration order and returns its key
+// and value, or false if m is empty.
+func (m *LinkedHashMap[K, V]) TakeLast() (K, V, bool) {
+	return m.take(m.tail)
+}
+
+// take unlinks e, if it isn't nil, and returns its key and value.
+func (m *LinkedHashMap[K, V]) take(e *linkedHashMapEntry[K, V]) (key K, val V, ok bool) {
+	if e == nil {
+		return
 	}
+	key, val = *e.key, *e.value
+	m.unlink(e)
+	return key, val, true
 }
 
 func (m *LinkedHashMap[K, V]) Has(key K) bool {
-	capMask := m.cap - 1
-	h := m.hasher.Hash(&key)
-	step := 0
-	for hIdx := int(h) & capMask; ; hIdx = (hIdx + step) & capMask {
-		currEntry := m.entries[hIdx]
-		if currEntry.key == nil {
-			return false
-		}
-		if h == currEntry.hashCache && m.comparator(*currEntry.key, key) {
-			return currEntry.value != nil
-		}
-		step++
+	return m.lookup(&key) != nil
+}
+
+// HashMapStats is a snapshot of the internal state of a hash table.
+type HashMapStats struct {
+	// Len is the number of entries in the map.
+	Len int
+	// Capacity is the number of slots in the hash table.
+	Capacity int
+	// Tombstones is the number of slots holding deleted keys.
+	Tombstones int
+	// LongestProbe is the longest probe sequence taken by an insertion since
+	// the table was last rehashed.
+	LongestProbe int
+	// Rehashes is the number of times the table has been rebuilt.
+	Rehashes int
+	// Reseeds is the number of times the hasher was given a fresh seed due to
+	// probe sequences exceeding MaxProbeLength.
+	Reseeds int
+}
+
+// Stats returns a snapshot of m's hash table statistics. Until m holds more
+// than 8 entries it has no hash table, and Capacity is the size the table
+// will be built with.
+func (m *LinkedHashMap[K, V]) Stats() HashMapStats {
+	var tombstones int
+	if m.entries != nil {
+		tombstones = m.nkeys - m.size
+	}
+	return HashMapStats{
+		Len:          m.size,
+		Capacity:     m.cap,
+		Tombstones:   tombstones,
+		LongestProbe: m.longestProbe,
+		Rehashes:     m.rehashes,
+		Reseeds:      m.reseeds,
 	}
 }
 
@@ -294,6 +646,30 @@ func (m *LinkedHashMap[K, V]) ReverseIterator() collections.Iterator[Entry[K, V]
 	return &linkedHashMapEntryReverseIterator[K, V]{m.tail}
 }
 
+// All returns an iter.Seq2 over the keys and values of m in insertion order.
+// m must not be modified during iteration.
+func (m *LinkedHashMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.head; e != nil; e = e.next {
+			if !yield(*e.key, *e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iter.Seq2 over the keys and values of m in reverse
+// insertion order. m must not be modified during iteration.
+func (m *LinkedHashMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.tail; e != nil; e = e.prev {
+			if !yield(*e.key, *e.value) {
+				return
+			}
+		}
+	}
+}
+
 type linkedHashMapEntryIterator[K, V any] struct {
 	current *linkedHashMapEntry[K, V]
 }
@@ -0,0 +1,254 @@
+package kvmap
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestLinkedHashMapMissingKeys(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, int](Capacity(8), LoadFactor(1))
+	if _, ok := m.Get(1); ok {
+		t.Errorf("Want Get(1) on empty map == (_, false), Got (_, true)")
+	}
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	for i := 100; i < 200; i++ {
+		if m.Has(i) {
+			t.Errorf("Want Has(%d) == false, Got true", i)
+		}
+		m.Delete(i)
+	}
+	if m.Len() != 100 {
+		t.Errorf("Want Len() == 100, Got %d", m.Len())
+	}
+}
+
+func TestLinkedHashMapReseedsOnLongProbes(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, int](MaxProbeLength(4))
+	// Until the first re-seed, every key hashes identically, modelling keys
+	// crafted to collide under the map's original seed.
+	m.hasher = CustomMapHasher[int](func(k *int) []byte {
+		if m.reseeds == 0 {
+			return []byte{0}
+		}
+		return binary.LittleEndian.AppendUint64(nil, uint64(*k))
+	})
+
+	for i := 0; i < 20; i++ {
+		m.Put(i, i*10)
+	}
+	st := m.Stats()
+	if st.Reseeds != 1 {
+		t.Errorf("Want Stats().Reseeds == 1, Got %d", st.Reseeds)
+	}
+	if st.Len != 20 {
+		t.Errorf("Want Stats().Len == 20, Got %d", st.Len)
+	}
+	for i := 0; i < 20; i++ {
+		if v, ok := m.Get(i); !ok || v != i*10 {
+			t.Errorf("Want Get(%d) == (%d, true), Got (%d, %t)", i, i*10, v, ok)
+		}
+	}
+	i := 0
+	for k := range m.All() {
+		if k != i {
+			t.Errorf("Want key %d in insertion order, Got %d", i, k)
+		}
+		i++
+	}
+}
+
+func TestLinkedHashMapReseedIsBounded(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, int](Capacity(64), MaxProbeLength(2))
+	// Keys which collide under every seed.
+	m.hasher = CustomMapHasher[int](func(*int) []byte { return []byte{0} })
+
+	for i := 0; i < 20; i++ {
+		m.Put(i, i)
+	}
+	if got := m.Stats().Reseeds; got != 1 {
+		t.Errorf("Want Stats().Reseeds == 1, Got %d", got)
+	}
+	for i := 0; i < 20; i++ {
+		if !m.Has(i) {
+			t.Errorf("Want Has(%d) == true, Got false", i)
+		}
+	}
+}
+
+func TestLinkedHashMapStats(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, int](Capacity(16))
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+	m.Delete(3)
+	m.Delete(4)
+
+	st := m.Stats()
+	if st.Len != 8 || st.Capacity != 16 || st.Tombstones != 2 {
+		t.Errorf("Want Stats() {Len: 8, Capacity: 16, Tombstones: 2}, Got %+v", st)
+	}
+}
+
+func TestLinkedHashMapMaxWeight(t *testing.T) {
+	var evicted []string
+	m := NewComparableLinkedHashMap[string, []byte](MaxWeight(10),
+		Weigher(func(_ string, v []byte) int { return len(v) }),
+		OnEvict(func(k string, _ []byte) { evicted = append(evicted, k) }))
+
+	m.Put("a", make([]byte, 4))
+	m.Put("b", make([]byte, 4))
+	m.Put("c", make([]byte, 4))
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Errorf("Want [a] evicted, Got %v", evicted)
+	}
+
+	// Re-putting "b" makes it the newest, so "c" is evicted next.
+	m.Put("b", make([]byte, 6))
+	m.Put("d", make([]byte, 2))
+	if len(evicted) != 2 || evicted[1] != "c" {
+		t.Errorf("Want [a c] evicted, Got %v", evicted)
+	}
+
+	var keys []string
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 2 || keys[0] != "b" || keys[1] != "d" {
+		t.Errorf("Want keys [b d], Got %v", keys)
+	}
+	if st := m.Stats(); st.Len != 2 {
+		t.Errorf("Want Len 2, Got %d", st.Len)
+	}
+
+	// An entry heavier than MaxWeight is dropped, along with the old value.
+	m.Put("d", make([]byte, 11))
+	if m.Has("d") || m.Len() != 1 {
+		t.Errorf("Want oversized d dropped leaving 1 entry, Got Has(d) == %t, Len() == %d", m.Has("d"), m.Len())
+	}
+	m.Put("e", make([]byte, 4))
+	if len(evicted) != 2 || m.Len() != 2 {
+		t.Errorf("Want no further evictions, Got %v", evicted)
+	}
+}
+
+func TestLinkedHashMapMaxWeightZeroIsUnbounded(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, int](MaxWeight(0))
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	if m.Len() != 100 {
+		t.Errorf("Want MaxWeight(0) to leave the map unbounded with Len() == 100, Got %d", m.Len())
+	}
+}
+
+func TestLinkedHashMapEvictionTypeMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want constructor to panic on mismatched OnEvict, Got no panic")
+		}
+	}()
+	NewComparableLinkedHashMap[int, int](OnEvict(func(string, int) {}))
+}
+
+func TestLinkedHashMapSmallMode(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, int]()
+	for i := 0; i < smallMapMax; i++ {
+		m.Put(i, i)
+	}
+	m.Put(0, 100)
+	m.Delete(3)
+	m.Put(3, 3)
+	if m.entries != nil {
+		t.Fatalf("Want no hash table with %d entries, Got %d slots", m.Len(), len(m.entries))
+	}
+	if v, ok := m.Get(0); !ok || v != 100 {
+		t.Errorf("Want Get(0) == (100, true), Got (%d, %t)", v, ok)
+	}
+
+	for i := smallMapMax; i < 4*smallMapMax; i++ {
+		m.Put(i, i)
+	}
+	if m.entries == nil {
+		t.Fatalf("Want a hash table with %d entries, Got none", m.Len())
+	}
+	if m.Len() != 4*smallMapMax {
+		t.Errorf("Want Len() == %d, Got %d", 4*smallMapMax, m.Len())
+	}
+	// Re-putting 0 and 3 moved them after the other small-mode keys.
+	want := []int{1, 2, 4, 5, 6, 7, 0, 3}
+	i := 0
+	for k := range m.All() {
+		if i < len(want) && k != want[i] {
+			t.Fatalf("Want key %d at position %d, Got %d", want[i], i, k)
+		}
+		if !m.Has(k) {
+			t.Errorf("Want Has(%d) after growth, Got false", k)
+		}
+		i++
+	}
+}
+
+func TestLinkedHashMapZeroValue(t *testing.T) {
+	var s struct {
+		m LinkedHashMap[string, int]
+	}
+	if _, ok := s.m.Get("a"); ok || s.m.Len() != 0 {
+		t.Errorf("Want empty zero value, Got %v", &s.m)
+	}
+	for i := 0; i < 100; i++ {
+		s.m.Put(fmt.Sprint(i), i)
+	}
+	s.m.Delete("0")
+	if s.m.Len() != 99 || s.m.Has("0") {
+		t.Errorf("Want Len() == 99 without key 0, Got %d", s.m.Len())
+	}
+	if v, ok := s.m.Get("42"); !ok || v != 42 {
+		t.Errorf("Want Get(42) == (42, true), Got (%d, %t)", v, ok)
+	}
+	if st := s.m.Stats(); st.Capacity < 100 {
+		t.Errorf("Want table grown beyond 100 slots, Got %+v", st)
+	}
+
+	var j LinkedHashMap[string, int]
+	if err := json.Unmarshal([]byte(`{"b": 2, "a": 1}`), &j); err != nil || j.String() != "map[b:2 a:1]" {
+		t.Errorf("Want map[b:2 a:1], Got %v, %v", &j, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want Put to panic for a zero value with non-comparable keys, Got no panic")
+		}
+	}()
+	var bad LinkedHashMap[[]int, int]
+	bad.Put(nil, 1)
+}
+
+func TestLinkedHashMapTake(t *testing.T) {
+	m := NewComparableLinkedHashMap[int, string]()
+	for i, s := range []string{"a", "b", "c", "d"} {
+		m.Put(i, s)
+	}
+	if k, v, ok := m.TakeFirst(); !ok || k != 0 || v != "a" {
+		t.Errorf(`Want TakeFirst() == (0, "a", true), Got (%d, %q, %t)`, k, v, ok)
+	}
+	if k, v, ok := m.TakeLast(); !ok || k != 3 || v != "d" {
+		t.Errorf(`Want TakeLast() == (3, "d", true), Got (%d, %q, %t)`, k, v, ok)
+	}
+	if v, ok := m.Take(2); !ok || v != "c" {
+		t.Errorf(`Want Take(2) == ("c", true), Got (%q, %t)`, v, ok)
+	}
+	if m.String() != "map[1:b]" {
+		t.Errorf("Want map[1:b], Got %v", m)
+	}
+	m.TakeFirst()
+	if k, v, ok := m.TakeFirst(); ok {
+		t.Errorf(`Want TakeFirst() == (0, "", false) on empty map, Got (%d, %q, %t)`, k, v, ok)
+	}
+	if _, _, ok := m.TakeLast(); ok || m.Len() != 0 {
+		t.Errorf("Want TakeLast() to fail on empty map, Got ok == %t, Len() == %d", ok, m.Len())
+	}
+}
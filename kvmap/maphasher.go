@@ -1,6 +1,7 @@
 package kvmap
 
 import (
+	"encoding"
 	"fmt"
 	"hash/maphash"
 	"reflect"
@@ -20,6 +21,12 @@ func (m MapHasher[K]) Hash(key *K) uint64 {
 	return maphash.Bytes(m.seed, m.toBytes(key))
 }
 
+// Reseed replaces m's seed with a new random seed. Hashes computed before the
+// call are not consistent with hashes computed after it.
+func (m *MapHasher[K]) Reseed() {
+	m.seed = maphash.MakeSeed()
+}
+
 // HashableKey is a compare.Equaler with a HashBytes() method. HashBytes()
 // should return a byte-slice representation of the wrapped value which is
 // consistent with Equals().
@@ -52,6 +59,20 @@ func ComparableMapHasher[K comparable]() MapHasher[K] {
 	}
 }
 
+// runtimeComparableMapHasher returns a MapHasher for keys of type K, and a
+// Comparator using the == operator, for maps created without a constructor.
+// It panics if K is not comparable.
+func runtimeComparableMapHasher[K any](mapType string) (MapHasher[K], compare.Comparator[K]) {
+	if t := reflect.TypeFor[K](); !t.Comparable() {
+		panic(fmt.Sprintf("zero value of %s used with non-comparable key type %v; use a constructor", mapType, t))
+	}
+	h := MapHasher[K]{
+		seed:    maphash.MakeSeed(),
+		toBytes: defaultHashBytesFunc[K](),
+	}
+	return h, func(k1, k2 K) bool { return any(k1) == any(k2) }
+}
+
 // CustomMapHasher returns a MapHasher for any key type. Users must provide a
 // serialization function that takes a pointer to a key and returns a
 // byte-slice representation of the key which is consistent with the comparison
@@ -63,6 +84,43 @@ func CustomMapHasher[K any](toBytes func(*K) []byte) MapHasher[K] {
 	}
 }
 
+// BinaryMarshalerMapHasher returns a MapHasher for key types implementing
+// encoding.BinaryMarshaler, which hashes the MarshalBinary encoding of each
+// key, so that domain types with marshalers can be map keys without a
+// HashBytes method. Keys which are equal must have equal encodings. Hash
+// panics if MarshalBinary returns an error.
+//
+// Since the maps of this package keep the hash of each key in its entry, a
+// key is only marshaled when it is Put or looked up, and again if the map's
+// hasher is re-seeded, not whenever the table grows.
+func BinaryMarshalerMapHasher[K encoding.BinaryMarshaler]() MapHasher[K] {
+	return MapHasher[K]{
+		seed: maphash.MakeSeed(),
+		toBytes: func(key *K) []byte {
+			b, err := (*key).MarshalBinary()
+			if err != nil {
+				panic(fmt.Sprintf("MarshalBinary of key %v failed: %v", *key, err))
+			}
+			return b
+		},
+	}
+}
+
+// TextMarshalerMapHasher is like BinaryMarshalerMapHasher, for key types
+// implementing encoding.TextMarshaler.
+func TextMarshalerMapHasher[K encoding.TextMarshaler]() MapHasher[K] {
+	return MapHasher[K]{
+		seed: maphash.MakeSeed(),
+		toBytes: func(key *K) []byte {
+			b, err := (*key).MarshalText()
+			if err != nil {
+				panic(fmt.Sprintf("MarshalText of key %v failed: %v", *key, err))
+			}
+			return b
+		},
+	}
+}
+
 // isFixedSize returns true if values of comparable type t take a fixed-size
 // contiguous block of memory for the purpose of hashing consistent with the ==
 // operator for use as map keys.
@@ -116,8 +174,10 @@ func isFixedSize(t reflect.Type) bool {
 // defaultHashBytesFunc returns a value to byte-slice function for values of
 // type T which is consistent with the == operator. The functions should not
 // be exposed and the returned byte slices should never be modified, as they
-// are often the allocated memory of the key reinterpreted as a []byte.
-func defaultHashBytesFunc[T comparable]() func(*T) []byte {
+// are often the allocated memory of the key reinterpreted as a []byte. T must
+// be comparable, but isn't constrained to be so that maps whose key type is
+// only known to be comparable at run time can use it.
+func defaultHashBytesFunc[T any]() func(*T) []byte {
 	var v T
 	t := reflect.TypeOf(v)
 
@@ -204,7 +264,7 @@ func defaultHashBytesFunc[T comparable]() func(*T) []byte {
 	panic("T is not a comparable type")
 }
 
-func deepHashBytes[T comparable](v *T) []byte {
+func deepHashBytes[T any](v *T) []byte {
 	if v == nil {
 		return []byte{}
 	}
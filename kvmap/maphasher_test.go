@@ -2,9 +2,12 @@ package kvmap
 
 import (
 	"fmt"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.org/jccarlson/collections/compare"
 	"golang.org/x/exp/constraints"
 )
 
@@ -229,3 +232,33 @@ func TestHashableKeyMapHasher(t *testing.T) {
 		t.Errorf("Expected Hash(%v) != Hash(%v); Got Hash(%[1]v) == Hash(%[2]v) == %v", v2, v3, h1)
 	}
 }
+
+// email is a key whose text encoding ignores case, like its equality.
+type email string
+
+func (e email) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(string(e))), nil
+}
+
+func TestMarshalerMapHashers(t *testing.T) {
+	addrs := NewLinkedHashMapWithHasher[netip.Addr, string](BinaryMarshalerMapHasher[netip.Addr](), compare.Equal[netip.Addr])
+	for i := 0; i < 100; i++ {
+		addrs.Put(netip.AddrFrom4([4]byte{10, 0, 0, byte(i)}), fmt.Sprint(i))
+	}
+	if v, ok := addrs.Get(netip.MustParseAddr("10.0.0.42")); !ok || v != "42" {
+		t.Errorf("Want Get(10.0.0.42) == (42, true), Got (%s, %t)", v, ok)
+	}
+	if addrs.Has(netip.MustParseAddr("10.0.1.0")) {
+		t.Errorf("Want Has(10.0.1.0) == false, Got true")
+	}
+
+	emails := NewLinkedHashMapWithHasher[email, int](TextMarshalerMapHasher[email](), func(e1, e2 email) bool {
+		return strings.EqualFold(string(e1), string(e2))
+	})
+	for i := 0; i < 20; i++ {
+		emails.Put(email(fmt.Sprintf("User%d@Example.com", i)), i)
+	}
+	if v, ok := emails.Get("user7@example.COM"); !ok || v != 7 {
+		t.Errorf("Want Get(user7@example.COM) == (7, true), Got (%d, %t)", v, ok)
+	}
+}
@@ -1,16 +1,16 @@
 package kvmap
 
 import (
+	"iter"
+	"maps"
+
 	"github.org/jccarlson/collections"
-	"github.org/jccarlson/collections/internal"
 )
 
 func initMapWrapperOptions(opts []Option) kvMapOpts {
 	r := kvMapOpts{capacity: -1}
 
-	for _, opt := range opts {
-		opt.setOpt(&r)
-	}
+	mustApplyOptions(&r, opts)
 	return r
 }
 
@@ -42,6 +42,15 @@ func (m MapWrapper[K, V]) Delete(key K) {
 	delete(m, key)
 }
 
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m MapWrapper[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	if val, ok = m[key]; ok {
+		delete(m, key)
+	}
+	return
+}
+
 func (m MapWrapper[K, V]) Has(key K) bool {
 	_, ok := m[key]
 	return ok
@@ -59,18 +68,38 @@ func (m MapWrapper[K, V]) Len() int {
 	return len(m)
 }
 
+// Iterator returns an Iterator over the entries of m. It iterates over a
+// snapshot of m's keys, which is allocated when Iterator is called; keys
+// deleted during iteration are skipped, and keys added are not visited.
+// Prefer All, which iterates in place.
 func (m MapWrapper[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return &mapWrapperIterator[K, V]{m: m, keys: keys}
+}
+
+// All returns an iter.Seq2 over the keys and values of m, in unspecified
+// order, with the semantics of a range loop over the built-in map.
+func (m MapWrapper[K, V]) All() iter.Seq2[K, V] {
+	return maps.All(map[K]V(m))
+}
 
-	sender, it := internal.NewChanIteratorPair[Entry[K, V]]()
-	go func() {
-		for k, v := range m {
-			if !sender.Send(&wrapperEntry[K, V]{map[K]V(m), k, v}) {
-				break
-			}
+type mapWrapperIterator[K comparable, V any] struct {
+	m    MapWrapper[K, V]
+	keys []K
+}
+
+func (i *mapWrapperIterator[K, V]) Next() (entry Entry[K, V], ok bool) {
+	for len(i.keys) > 0 {
+		k := i.keys[0]
+		i.keys = i.keys[1:]
+		if v, ok := i.m[k]; ok {
+			return &wrapperEntry[K, V]{map[K]V(i.m), k, v}, true
 		}
-		sender.Close()
-	}()
-	return it
+	}
+	return
 }
 
 type wrapperEntry[K comparable, V any] struct {
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package kvmap
+
+import (
+	"os"
+)
+
+// mmapFile reads the file at path into memory, on platforms where it cannot
+// be mapped.
+func mmapFile(path string) (data []byte, closer func() error, err error) {
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package kvmap
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the file at path read-only into memory, returning its
+// contents and a function which unmaps them.
+func mmapFile(path string) (data []byte, closer func() error, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi.Size() == 0 {
+		// Empty files can't be mapped; let the caller reject them.
+		return nil, func() error { return nil }, nil
+	}
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,408 @@
+package kvmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"iter"
+	"maps"
+
+	"github.org/jccarlson/collections"
+)
+
+// The MmapMap file format is a sequence of records, each holding a
+// uvarint-length-prefixed key and value, followed by an open-addressed hash
+// table of (hash, record offset) pairs and a fixed-size footer. Keeping the
+// table at the end lets WriteMmapMap stream records without seeking.
+const (
+	mmapMapMagic      = "KVMMAP01"
+	mmapMapFooterSize = 3*8 + len(mmapMapMagic)
+	mmapMapSlotSize   = 16
+)
+
+// mmapMapHash hashes encoded keys. Unlike MapHasher, it is unseeded so that
+// files can be read by other processes.
+func mmapMapHash(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// WriteMmapMap writes the entries of m to w in the format read by
+// OpenMmapMap, encoding keys with keyCodec and values with valCodec. Records
+// are streamed to w in m's iteration order; only 16 bytes per entry are held
+// in memory until the hash table is written at the end.
+func WriteMmapMap[K, V any](w io.Writer, m IterableMap[K, V], keyCodec Codec[K], valCodec Codec[V]) error {
+	bw := bufio.NewWriter(w)
+	type slot struct{ hash, offset uint64 }
+	var slots []slot
+	// Offsets start at 1 so that 0 can mark empty slots, which costs a
+	// padding byte at the start of the file.
+	offset := uint64(1)
+	if err := bw.WriteByte(0); err != nil {
+		return err
+	}
+	var buf []byte
+	it := m.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		kb, err := keyCodec.Encode(e.Key())
+		if err != nil {
+			return err
+		}
+		vb, err := valCodec.Encode(e.Value())
+		if err != nil {
+			return err
+		}
+		slots = append(slots, slot{mmapMapHash(kb), offset})
+		buf = binary.AppendUvarint(buf[:0], uint64(len(kb)))
+		buf = append(buf, kb...)
+		buf = binary.AppendUvarint(buf, uint64(len(vb)))
+		buf = append(buf, vb...)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		offset += uint64(len(buf))
+	}
+
+	// Size the table for a load factor of at most 0.5, so that probes are
+	// short.
+	nslots := 1
+	for nslots < 2*len(slots) {
+		nslots <<= 1
+	}
+	table := make([]byte, nslots*mmapMapSlotSize)
+	for _, s := range slots {
+		for i := s.hash & uint64(nslots-1); ; i = (i + 1) & uint64(nslots-1) {
+			b := table[i*mmapMapSlotSize:]
+			if binary.LittleEndian.Uint64(b[8:]) == 0 {
+				binary.LittleEndian.PutUint64(b, s.hash)
+				binary.LittleEndian.PutUint64(b[8:], s.offset)
+				break
+			}
+		}
+	}
+	if _, err := bw.Write(table); err != nil {
+		return err
+	}
+	footer := binary.LittleEndian.AppendUint64(nil, offset)
+	footer = binary.LittleEndian.AppendUint64(footer, uint64(nslots))
+	footer = binary.LittleEndian.AppendUint64(footer, uint64(len(slots)))
+	footer = append(footer, mmapMapMagic...)
+	if _, err := bw.Write(footer); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ErrCorruptMmapMap is returned by OpenMmapMap if a file is not in the format
+// written by WriteMmapMap.
+var ErrCorruptMmapMap = errors.New("kvmap: corrupt MmapMap file")
+
+// MmapMap is a map whose entries are stored in a memory-mapped file written
+// by WriteMmapMap, so that datasets larger than is comfortable for the Go
+// heap can be queried, and the pages shared read-only between processes
+// mapping the same file. Keys are looked up by their encoded bytes, and
+// values are decoded on every Get.
+//
+// The file is never modified. Put and Delete record changes in memory, on top
+// of the file's entries, and are lost when the map is closed. They panic if
+// the key cannot be encoded. Get panics if the file was modified after it was
+// opened or a value cannot be decoded. Iteration order is unspecified.
+type MmapMap[K, V any] struct {
+	keyCodec Codec[K]
+	valCodec Codec[V]
+
+	data   []byte
+	closer func() error
+
+	// records is the portion of data holding records, and table the hash
+	// table, of nslots slots.
+	records, table []byte
+	nslots         uint64
+
+	// overlay maps encoded keys to values Put since the file was opened, or
+	// to nil for keys in the file which were deleted.
+	overlay map[string]*V
+	size    int
+}
+
+// OpenMmapMap memory-maps the file at path, which must have been written by
+// WriteMmapMap with the same codecs, and returns a map over its entries.
+// The map must be closed with Close to release the mapping. On platforms
+// without mmap support, the file is read into memory instead.
+func OpenMmapMap[K, V any](path string, keyCodec Codec[K], valCodec Codec[V]) (*MmapMap[K, V], error) {
+	data, closer, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMmapMap(data, keyCodec, valCodec)
+	if err != nil {
+		closer()
+		return nil, err
+	}
+	m.closer = closer
+	return m, nil
+}
+
+func newMmapMap[K, V any](data []byte, keyCodec Codec[K], valCodec Codec[V]) (*MmapMap[K, V], error) {
+	if len(data) < mmapMapFooterSize || string(data[len(data)-len(mmapMapMagic):]) != mmapMapMagic {
+		return nil, ErrCorruptMmapMap
+	}
+	footer := data[len(data)-mmapMapFooterSize:]
+	tableOffset := binary.LittleEndian.Uint64(footer)
+	nslots := binary.LittleEndian.Uint64(footer[8:])
+	count := binary.LittleEndian.Uint64(footer[16:])
+	tableEnd := uint64(len(data) - mmapMapFooterSize)
+	if nslots == 0 || nslots&(nslots-1) != 0 || count > nslots || tableOffset > tableEnd ||
+		(tableEnd-tableOffset)/mmapMapSlotSize != nslots || (tableEnd-tableOffset)%mmapMapSlotSize != 0 {
+		return nil, ErrCorruptMmapMap
+	}
+	return &MmapMap[K, V]{
+		keyCodec: keyCodec,
+		valCodec: valCodec,
+		data:     data,
+		records:  data[:tableOffset],
+		table:    data[tableOffset:tableEnd],
+		nslots:   nslots,
+		overlay:  make(map[string]*V),
+		size:     int(count),
+	}, nil
+}
+
+// Close releases the file mapping. m must not be used after Close.
+func (m *MmapMap[K, V]) Close() error {
+	m.records, m.table, m.data = nil, nil, nil
+	if m.closer == nil {
+		return nil
+	}
+	closer := m.closer
+	m.closer = nil
+	return closer()
+}
+
+// record parses the record at offset, returning its encoded key and value
+// and the offset of the next record.
+func (m *MmapMap[K, V]) record(offset uint64) (kb, vb []byte, next uint64) {
+	field := func() []byte {
+		if offset >= uint64(len(m.records)) {
+			panic(ErrCorruptMmapMap)
+		}
+		n, w := binary.Uvarint(m.records[offset:])
+		if w <= 0 || n > uint64(len(m.records))-offset-uint64(w) {
+			panic(ErrCorruptMmapMap)
+		}
+		offset += uint64(w) + n
+		return m.records[offset-n : offset]
+	}
+	kb = field()
+	vb = field()
+	return kb, vb, offset
+}
+
+// lookup returns the encoded value for the encoded key kb in the file.
+func (m *MmapMap[K, V]) lookup(kb []byte) (vb []byte, ok bool) {
+	h := mmapMapHash(kb)
+	for i, n := h&(m.nslots-1), uint64(0); n < m.nslots; i, n = (i+1)&(m.nslots-1), n+1 {
+		s := m.table[i*mmapMapSlotSize:]
+		offset := binary.LittleEndian.Uint64(s[8:])
+		if offset == 0 {
+			return nil, false
+		}
+		if binary.LittleEndian.Uint64(s) != h {
+			continue
+		}
+		if k, v, _ := m.record(offset); bytes.Equal(k, kb) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (m *MmapMap[K, V]) encodeKey(key K) string {
+	kb, err := m.keyCodec.Encode(key)
+	if err != nil {
+		panic(fmt.Sprintf("kvmap: cannot encode MmapMap key %v: %v", key, err))
+	}
+	return string(kb)
+}
+
+func (m *MmapMap[K, V]) decodeKey(kb []byte) K {
+	k, err := m.keyCodec.Decode(kb)
+	if err != nil {
+		panic(fmt.Sprintf("kvmap: cannot decode MmapMap key: %v", err))
+	}
+	return k
+}
+
+func (m *MmapMap[K, V]) decodeValue(vb []byte) V {
+	v, err := m.valCodec.Decode(vb)
+	if err != nil {
+		panic(fmt.Sprintf("kvmap: cannot decode MmapMap value: %v", err))
+	}
+	return v
+}
+
+// has returns whether the encoded key kb is in m, and whether it is in the
+// file.
+func (m *MmapMap[K, V]) has(kb string) (inMap, inFile bool) {
+	_, inFile = m.lookup([]byte(kb))
+	if v, ok := m.overlay[kb]; ok {
+		return v != nil, inFile
+	}
+	return inFile, inFile
+}
+
+func (m *MmapMap[K, V]) Put(key K, val V) {
+	kb := m.encodeKey(key)
+	if inMap, _ := m.has(kb); !inMap {
+		m.size++
+	}
+	m.overlay[kb] = &val
+}
+
+func (m *MmapMap[K, V]) Get(key K) (val V, ok bool) {
+	kb := m.encodeKey(key)
+	if v, ok := m.overlay[kb]; ok {
+		if v == nil {
+			return val, false
+		}
+		return *v, true
+	}
+	vb, ok := m.lookup([]byte(kb))
+	if !ok {
+		return val, false
+	}
+	return m.decodeValue(vb), true
+}
+
+func (m *MmapMap[K, V]) Delete(key K) {
+	kb := m.encodeKey(key)
+	inMap, inFile := m.has(kb)
+	if !inMap {
+		return
+	}
+	m.size--
+	if inFile {
+		m.overlay[kb] = nil
+	} else {
+		delete(m.overlay, kb)
+	}
+}
+
+func (m *MmapMap[K, V]) Has(key K) bool {
+	inMap, _ := m.has(m.encodeKey(key))
+	return inMap
+}
+
+func (m *MmapMap[K, V]) Len() int {
+	return m.size
+}
+
+func (m *MmapMap[K, V]) String() string {
+	return IterableMapToString[K, V](m)
+}
+
+func (m *MmapMap[K, V]) GoString() string {
+	return IterableMapToGoString[K, V](m)
+}
+
+// All returns an iter.Seq2 over the keys and values of m: first those of the
+// file which have not been overwritten or deleted, in the order they were
+// written, then those Put since m was opened. m must not be modified during
+// iteration.
+func (m *MmapMap[K, V]) All() iter.Seq2[K, V] {
+	return m.all(m.overlay)
+}
+
+// all returns an iter.Seq2 over the entries of the file not in overlay,
+// followed by the values in overlay.
+func (m *MmapMap[K, V]) all(overlay map[string]*V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for offset := uint64(1); offset < uint64(len(m.records)); {
+			kb, vb, next := m.record(offset)
+			offset = next
+			if _, ok := overlay[string(kb)]; ok {
+				continue
+			}
+			if !yield(m.decodeKey(kb), m.decodeValue(vb)) {
+				return
+			}
+		}
+		for kb, v := range overlay {
+			if v == nil {
+				continue
+			}
+			if !yield(m.decodeKey([]byte(kb)), *v) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator returns an Iterator over the entries of m, in the order of All.
+// Setting the value of an Entry Puts it into m, without the Iterator visiting
+// the entry again.
+func (m *MmapMap[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	return &mmapMapIterator[K, V]{m: m, overlay: maps.Clone(m.overlay), offset: 1}
+}
+
+// mmapMapIterator iterates over the records of the file which are not in
+// overlay, a copy of m's overlay taken when iteration started, then over the
+// encoded keys of overlay, which it collects once the records are done.
+type mmapMapIterator[K, V any] struct {
+	m       *MmapMap[K, V]
+	overlay map[string]*V
+	// offset is the offset of the next record to visit.
+	offset uint64
+	// keys holds the encoded keys of overlay not yet visited, once
+	// inOverlay.
+	keys      []string
+	inOverlay bool
+}
+
+func (i *mmapMapIterator[K, V]) Next() (entry Entry[K, V], ok bool) {
+	for !i.inOverlay && i.offset < uint64(len(i.m.records)) {
+		kb, vb, next := i.m.record(i.offset)
+		i.offset = next
+		if _, ok := i.overlay[string(kb)]; !ok {
+			return &mmapMapEntry[K, V]{i.m, i.m.decodeKey(kb), i.m.decodeValue(vb)}, true
+		}
+	}
+	if !i.inOverlay {
+		i.inOverlay = true
+		for kb, v := range i.overlay {
+			if v != nil {
+				i.keys = append(i.keys, kb)
+			}
+		}
+	}
+	if len(i.keys) == 0 {
+		return
+	}
+	kb := i.keys[0]
+	i.keys = i.keys[1:]
+	return &mmapMapEntry[K, V]{i.m, i.m.decodeKey([]byte(kb)), *i.overlay[kb]}, true
+}
+
+type mmapMapEntry[K, V any] struct {
+	m     *MmapMap[K, V]
+	key   K
+	value V
+}
+
+func (e *mmapMapEntry[K, V]) Key() K {
+	return e.key
+}
+
+func (e *mmapMapEntry[K, V]) Value() V {
+	return e.value
+}
+
+func (e *mmapMapEntry[K, V]) SetValue(v V) {
+	e.value = v
+	e.m.Put(e.key, v)
+}
@@ -0,0 +1,116 @@
+package kvmap
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func writeMmapMapFile(t *testing.T, m IterableMap[int, string]) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "map")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := WriteMmapMap(f, m, TextCodec[int](), TextCodec[string]()); err != nil {
+		t.Fatalf("WriteMmapMap() == %v, Want nil", err)
+	}
+	return path
+}
+
+func TestMmapMap(t *testing.T) {
+	src := NewComparableLinkedHashMap[int, string]()
+	for i := 0; i < 1000; i++ {
+		src.Put(i, strconv.Itoa(i*i))
+	}
+	m, err := OpenMmapMap(writeMmapMapFile(t, src), TextCodec[int](), TextCodec[string]())
+	if err != nil {
+		t.Fatalf("OpenMmapMap() == %v, Want nil", err)
+	}
+	defer m.Close()
+
+	if m.Len() != 1000 {
+		t.Errorf("Want Len() == 1000, Got %d", m.Len())
+	}
+	for i := 0; i < 1000; i++ {
+		if v, ok := m.Get(i); !ok || v != strconv.Itoa(i*i) {
+			t.Errorf("Want Get(%d) == (%d, true), Got (%s, %t)", i, i*i, v, ok)
+		}
+	}
+	if m.Has(1000) || m.Has(-1) {
+		t.Errorf("Want Has() == false for keys not written, Got true")
+	}
+
+	// Changes are kept in memory on top of the file.
+	m.Put(5, "five")
+	m.Put(2000, "two thousand")
+	m.Delete(7)
+	m.Delete(2001)
+	if m.Len() != 1000 {
+		t.Errorf("Want Len() == 1000 after Put, Put, Delete, Got %d", m.Len())
+	}
+	if v, _ := m.Get(5); v != "five" {
+		t.Errorf("Want Get(5) == five, Got %s", v)
+	}
+	if m.Has(7) || !m.Has(2000) {
+		t.Errorf("Want Has(7) == false and Has(2000) == true, Got %t and %t", m.Has(7), m.Has(2000))
+	}
+	m.Put(7, "seven")
+	if v, ok := m.Get(7); !ok || v != "seven" || m.Len() != 1001 {
+		t.Errorf("Want Get(7) == (seven, true) and Len() == 1001, Got (%s, %t) and %d", v, ok, m.Len())
+	}
+
+	seen := map[int]string{}
+	for k, v := range m.All() {
+		if _, ok := seen[k]; ok {
+			t.Errorf("Want each key once from All(), Got %d twice", k)
+		}
+		seen[k] = v
+	}
+	if len(seen) != 1001 || seen[5] != "five" || seen[7] != "seven" || seen[2000] != "two thousand" {
+		t.Errorf("Want All() to reflect changes, Got %d entries, 5:%s 7:%s 2000:%s", len(seen), seen[5], seen[7], seen[2000])
+	}
+
+	n := 0
+	it := m.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		if v, ok := seen[e.Key()]; !ok || v != e.Value() {
+			t.Errorf("Want Iterator() to yield the entries of All(), Got %d:%s", e.Key(), e.Value())
+		}
+		n++
+	}
+	if n != len(seen) {
+		t.Errorf("Want Iterator() to yield %d entries, Got %d", len(seen), n)
+	}
+	before := runtime.NumGoroutine()
+	for range 100 {
+		m.Iterator().Next()
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Want no goroutines left by abandoned iterators, Got %d more", after-before)
+	}
+}
+
+func TestMmapMapRejectsCorruptFiles(t *testing.T) {
+	var buf bytes.Buffer
+	src := NewMapWrapper[int, string]()
+	src.Put(1, "one")
+	if err := WriteMmapMap[int, string](&buf, src, TextCodec[int](), TextCodec[string]()); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	for _, corrupt := range [][]byte{nil, data[:len(data)-1], data[1:]} {
+		if _, err := newMmapMap(corrupt, TextCodec[int](), TextCodec[string]()); !errors.Is(err, ErrCorruptMmapMap) {
+			t.Errorf("Want ErrCorruptMmapMap for %d bytes, Got %v", len(corrupt), err)
+		}
+	}
+	if m, err := newMmapMap(data, TextCodec[int](), TextCodec[string]()); err != nil || m.Len() != 1 {
+		t.Errorf("Want valid map of Len() 1, Got error %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package kvmap
+
+import (
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// Multimap is the interface common to maps which associate each key with one
+// or more values.
+type Multimap[K, V any] interface {
+	// Put adds val to the values of key.
+	Put(key K, val V)
+	// Get returns the values of key, or nil if key is not in the Multimap.
+	Get(key K) []V
+	// Delete removes key and all of its values.
+	Delete(key K)
+	Has(key K) bool
+	// Len returns the total number of values in the Multimap.
+	Len() int
+}
+
+// ListMultimap is a Multimap which keeps the values of each key in the order
+// they were Put, and iterates over keys in the order they were first Put.
+type ListMultimap[K, V any] struct {
+	m    *LinkedHashMap[K, *[]V]
+	size int
+}
+
+// NewComparableListMultimap returns a pointer to a new ListMultimap with
+// comparable keys. opts configure the underlying LinkedHashMap of keys.
+func NewComparableListMultimap[K comparable, V any](opts ...Option) *ListMultimap[K, V] {
+	return &ListMultimap[K, V]{m: NewComparableLinkedHashMap[K, *[]V](opts...)}
+}
+
+// NewListMultimapWithHasher returns a pointer to a new ListMultimap which
+// hashes keys with hasher and compares them with equal.
+func NewListMultimapWithHasher[K, V any](hasher MapHasher[K], equal compare.Comparator[K], opts ...Option) *ListMultimap[K, V] {
+	return &ListMultimap[K, V]{m: NewLinkedHashMapWithHasher[K, *[]V](hasher, equal, opts...)}
+}
+
+func (m *ListMultimap[K, V]) Put(key K, val V) {
+	// Appending through the pointer keeps key's place in insertion order.
+	if vals, ok := m.m.Get(key); ok {
+		*vals = append(*vals, val)
+	} else {
+		m.m.Put(key, &[]V{val})
+	}
+	m.size++
+}
+
+// Get returns the values of key in the order they were Put. The returned
+// slice must not be modified.
+func (m *ListMultimap[K, V]) Get(key K) []V {
+	if vals, ok := m.m.Get(key); ok {
+		return slices.Clip(*vals)
+	}
+	return nil
+}
+
+func (m *ListMultimap[K, V]) Delete(key K) {
+	if vals, ok := m.m.Get(key); ok {
+		m.size -= len(*vals)
+		m.m.Delete(key)
+	}
+}
+
+func (m *ListMultimap[K, V]) Has(key K) bool {
+	return m.m.Has(key)
+}
+
+func (m *ListMultimap[K, V]) Len() int {
+	return m.size
+}
+
+// KeyLen returns the number of distinct keys in m.
+func (m *ListMultimap[K, V]) KeyLen() int {
+	return m.m.Len()
+}
+
+// All returns an iter.Seq2 over the keys of m and their values, in the order
+// keys were first Put. The yielded slices must not be modified, and m must
+// not be modified during iteration.
+func (m *ListMultimap[K, V]) All() iter.Seq2[K, []V] {
+	return func(yield func(K, []V) bool) {
+		for k, vals := range m.m.All() {
+			if !yield(k, slices.Clip(*vals)) {
+				return
+			}
+		}
+	}
+}
+
+// GroupInto adds each value of s to dst, under the key returned by key.
+func GroupInto[K, V any](dst Multimap[K, V], s iter.Seq[V], key func(V) K) {
+	for v := range s {
+		dst.Put(key(v), v)
+	}
+}
+
+// GroupBy returns a new ListMultimap grouping the values of s by the key
+// returned by key, keeping their order within each group.
+func GroupBy[K comparable, V any](s iter.Seq[V], key func(V) K) *ListMultimap[K, V] {
+	m := NewComparableListMultimap[K, V]()
+	GroupInto[K, V](m, s, key)
+	return m
+}
@@ -0,0 +1,82 @@
+package kvmap
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "bean", "avocado", "cherry", "banana", "apricot"}
+	m := GroupBy(slices.Values(words), func(w string) byte { return w[0] })
+
+	if m.Len() != 6 || m.KeyLen() != 3 {
+		t.Errorf("Want Len() == 6 and KeyLen() == 3, Got %d and %d", m.Len(), m.KeyLen())
+	}
+	if got, want := m.Get('a'), []string{"apple", "avocado", "apricot"}; !slices.Equal(got, want) {
+		t.Errorf("Want Get('a') == %v, Got %v", want, got)
+	}
+	if got := m.Get('z'); got != nil {
+		t.Errorf("Want Get('z') == nil, Got %v", got)
+	}
+
+	var keys []byte
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+	if string(keys) != "abc" {
+		t.Errorf("Want keys in first-Put order \"abc\", Got %q", keys)
+	}
+
+	m.Delete('b')
+	if m.Has('b') || m.Len() != 4 {
+		t.Errorf("Want Delete('b') to remove 2 values, Got Has('b') == %t, Len() == %d", m.Has('b'), m.Len())
+	}
+}
+
+func TestGroupIntoExisting(t *testing.T) {
+	m := NewComparableListMultimap[int, string]()
+	m.Put(3, "the")
+	GroupInto[int, string](m, slices.Values(strings.Fields("a quick brown fox")), func(w string) int { return len(w) })
+	if got, want := m.Get(3), []string{"the", "fox"}; !slices.Equal(got, want) {
+		t.Errorf("Want Get(3) == %v, Got %v", want, got)
+	}
+	if got, want := m.Get(5), []string{"quick", "brown"}; !slices.Equal(got, want) {
+		t.Errorf("Want Get(5) == %v, Got %v", want, got)
+	}
+}
+
+func TestOrderedMultimap(t *testing.T) {
+	m := NewOrderedMultimap[int, string]()
+	var _ Multimap[int, string] = m
+	for _, w := range strings.Fields("the quick brown fox jumps over the lazy dog") {
+		m.Put(len(w), w)
+	}
+	if m.Len() != 9 || m.KeyLen() != 3 {
+		t.Errorf("Want Len() == 9 and KeyLen() == 3, Got %d and %d", m.Len(), m.KeyLen())
+	}
+	if got, want := slices.Collect(m.EqualRange(3)), []string{"the", "fox", "the", "dog"}; !slices.Equal(got, want) {
+		t.Errorf("Want EqualRange(3) == %v, Got %v", want, got)
+	}
+	if c := m.Count(5); c != 3 {
+		t.Errorf("Want Count(5) == 3, Got %d", c)
+	}
+	if c := m.Count(7); c != 0 || m.Get(7) != nil {
+		t.Errorf("Want Count(7) == 0 and Get(7) == nil, Got %d and %v", c, m.Get(7))
+	}
+
+	var pairs []string
+	for k, v := range m.Pairs() {
+		pairs = append(pairs, strconv.Itoa(k)+v)
+	}
+	want := []string{"3the", "3fox", "3the", "3dog", "4over", "4lazy", "5quick", "5brown", "5jumps"}
+	if !slices.Equal(pairs, want) {
+		t.Errorf("Want Pairs() == %v, Got %v", want, pairs)
+	}
+
+	m.Delete(3)
+	if m.Has(3) || m.Len() != 5 || m.KeyLen() != 2 {
+		t.Errorf("Want Delete(3) to remove 4 values, Got Has(3) == %t, Len() == %d", m.Has(3), m.Len())
+	}
+}
@@ -0,0 +1,112 @@
+package kvmap
+
+import "sync"
+
+// Listener is a set of callbacks which an ObservableWrapper invokes after its
+// contents change. Any of the callbacks may be nil.
+type Listener[K, V any] struct {
+	// OnPut is called after Put(key, new). If key was already present, old is
+	// its previous value and hadOld is true.
+	OnPut func(key K, old V, hadOld bool, new V)
+	// OnDelete is called after Delete(key) removes a key with value old.
+	// Deletes of absent keys are not reported.
+	OnDelete func(key K, old V)
+	// OnEvict is called when the Base map evicts an entry, as reported via
+	// NotifyEvict.
+	OnEvict func(key K, val V)
+}
+
+// ObservableWrapper wraps any kvmap.Interface, calling registered Listeners on
+// every mutation, so that indexes and metrics derived from the map can stay in
+// sync without wrapping every call site. Listeners are called synchronously,
+// in registration order. Registering and unregistering listeners is
+// thread-safe, but other operations are only thread-safe if Base is.
+type ObservableWrapper[K, V any] struct {
+	Base Interface[K, V]
+
+	mu        sync.RWMutex
+	listeners []*Listener[K, V]
+}
+
+// Listen registers l to be notified of mutations of m, and returns a function
+// which unregisters it.
+func (m *ObservableWrapper[K, V]) Listen(l Listener[K, V]) (cancel func()) {
+	lp := &l
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, lp)
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, other := range m.listeners {
+			if other == lp {
+				// Copy rather than modifying in place, as the slice may be
+				// being iterated over by notify.
+				m.listeners = append(m.listeners[:i:i], m.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (m *ObservableWrapper[K, V]) notify(f func(l *Listener[K, V])) {
+	m.mu.RLock()
+	listeners := m.listeners
+	m.mu.RUnlock()
+	for _, l := range listeners {
+		f(l)
+	}
+}
+
+func (m *ObservableWrapper[K, V]) Put(key K, val V) {
+	old, hadOld := m.Base.Get(key)
+	m.Base.Put(key, val)
+	m.notify(func(l *Listener[K, V]) {
+		if l.OnPut != nil {
+			l.OnPut(key, old, hadOld, val)
+		}
+	})
+}
+
+func (m *ObservableWrapper[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *ObservableWrapper[K, V]) DeleteAndGet(key K) (V, bool) {
+	old, hadOld := DeleteAndGet(m.Base, key)
+	if !hadOld {
+		return old, false
+	}
+	m.notify(func(l *Listener[K, V]) {
+		if l.OnDelete != nil {
+			l.OnDelete(key, old)
+		}
+	})
+	return old, true
+}
+
+// NotifyEvict reports to m's Listeners that the Base map evicted key with
+// value val. Bases which evict entries on their own, such as caches, should
+// be configured to call it, e.g. with cache.OnEvict(w.NotifyEvict).
+func (m *ObservableWrapper[K, V]) NotifyEvict(key K, val V) {
+	m.notify(func(l *Listener[K, V]) {
+		if l.OnEvict != nil {
+			l.OnEvict(key, val)
+		}
+	})
+}
+
+func (m *ObservableWrapper[K, V]) Get(key K) (V, bool) {
+	return m.Base.Get(key)
+}
+
+func (m *ObservableWrapper[K, V]) Has(key K) bool {
+	return m.Base.Has(key)
+}
+
+func (m *ObservableWrapper[K, V]) Len() int {
+	return m.Base.Len()
+}
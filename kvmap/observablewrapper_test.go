@@ -0,0 +1,42 @@
+package kvmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestObservableWrapper(t *testing.T) {
+	m := &ObservableWrapper[string, int]{Base: NewMapWrapper[string, int]()}
+	var events []string
+	cancel := m.Listen(Listener[string, int]{
+		OnPut: func(key string, old int, hadOld bool, new int) {
+			events = append(events, fmt.Sprintf("put %s %d %t %d", key, old, hadOld, new))
+		},
+		OnDelete: func(key string, old int) {
+			events = append(events, fmt.Sprintf("delete %s %d", key, old))
+		},
+		OnEvict: func(key string, val int) {
+			events = append(events, fmt.Sprintf("evict %s %d", key, val))
+		},
+	})
+	sum := 0
+	m.Listen(Listener[string, int]{
+		OnPut: func(_ string, old int, _ bool, new int) { sum += new - old },
+	})
+
+	m.Put("a", 1)
+	m.Put("a", 2)
+	m.Delete("a")
+	m.Delete("b")
+	m.NotifyEvict("c", 3)
+	cancel()
+	m.Put("d", 4)
+
+	want := []string{"put a 0 false 1", "put a 1 true 2", "delete a 2", "evict c 3"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("Want events %q, Got %q", want, events)
+	}
+	if sum != 6 {
+		t.Errorf("Want second listener to observe all Puts (sum == 6), Got %d", sum)
+	}
+}
@@ -0,0 +1,81 @@
+package kvmap
+
+import (
+	"iter"
+
+	"github.org/jccarlson/collections/compare"
+	"github.org/jccarlson/collections/seq"
+)
+
+// OrderedIterable is implemented by maps whose iteration order is defined and
+// reversible, such as LinkedHashMap (insertion order) and OrderedMap (key
+// order), as opposed to maps like MapWrapper, whose order is unspecified.
+type OrderedIterable[K, V any] interface {
+	IterableMap[K, V]
+	// All iterates over the map in its order.
+	All() iter.Seq2[K, V]
+	// Backward iterates over the map in reverse order.
+	Backward() iter.Seq2[K, V]
+}
+
+// SortedIterable is implemented by maps which iterate in the order of their
+// keys, such as OrderedMap. Algorithms which rely on sorted input, such as
+// merges and merge joins, take SortedIterables, so that passing them a map in
+// any other order doesn't compile.
+type SortedIterable[K, V any] interface {
+	OrderedIterable[K, V]
+	// KeyOrdering returns the ordering of the map's keys.
+	KeyOrdering() compare.Ordering[K]
+}
+
+var (
+	_ OrderedIterable[int, int] = (*LinkedHashMap[int, int])(nil)
+	_ SortedIterable[int, int]  = (*OrderedMap[int, int])(nil)
+)
+
+// MergeSortedMaps returns an iter.Seq2 over the entries of maps, merged in
+// key order by the KeyOrdering of the first map, which all the maps must
+// share. Like MergeOrderedMaps, equal keys are yielded once per map holding
+// them, in the order of maps.
+func MergeSortedMaps[K, V any](maps ...SortedIterable[K, V]) iter.Seq2[K, V] {
+	if len(maps) == 0 {
+		return func(func(K, V) bool) {}
+	}
+	seqs := make([]iter.Seq2[K, V], len(maps))
+	for i, m := range maps {
+		seqs[i] = m.All()
+	}
+	return seq.MergeSorted2(maps[0].KeyOrdering(), seqs...)
+}
+
+// DiffSorted computes the same MapDiff as Diff, with each list in key order,
+// by walking old and new together in one pass rather than looking up each
+// key of one map in the other. old and new must share the same KeyOrdering.
+func DiffSorted[K, V any](old, new SortedIterable[K, V], valueEq compare.Comparator[V]) MapDiff[K, V] {
+	var d MapDiff[K, V]
+	less := old.KeyOrdering()
+	nextOld, stopOld := iter.Pull2(old.All())
+	defer stopOld()
+	nextNew, stopNew := iter.Pull2(new.All())
+	defer stopNew()
+
+	ko, vo, okOld := nextOld()
+	kn, vn, okNew := nextNew()
+	for okOld || okNew {
+		switch {
+		case !okNew || okOld && less(ko, kn):
+			d.Removed = append(d.Removed, ValueChange[K, V]{Key: ko, Old: vo})
+			ko, vo, okOld = nextOld()
+		case !okOld || less(kn, ko):
+			d.Added = append(d.Added, ValueChange[K, V]{Key: kn, New: vn})
+			kn, vn, okNew = nextNew()
+		default:
+			if !valueEq(vo, vn) {
+				d.Changed = append(d.Changed, ValueChange[K, V]{Key: ko, Old: vo, New: vn})
+			}
+			ko, vo, okOld = nextOld()
+			kn, vn, okNew = nextNew()
+		}
+	}
+	return d
+}
@@ -0,0 +1,46 @@
+package kvmap
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+func TestDiffSorted(t *testing.T) {
+	old := NewOrderedMap[string, int]()
+	new := NewOrderedMap[string, int]()
+	for k, v := range map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "f": 6} {
+		old.Put(k, v)
+	}
+	for k, v := range map[string]int{"b": 2, "c": 30, "d": 40, "e": 5, "g": 7} {
+		new.Put(k, v)
+	}
+
+	got := DiffSorted[string, int](old, new, compare.Equal[int])
+	if want := Diff[string, int](old, new, compare.Equal[int]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want DiffSorted == Diff == %+v, Got %+v", want, got)
+	}
+	if d := DiffSorted[string, int](old, NewOrderedMap[string, int](), compare.Equal[int]); len(d.Removed) != 5 {
+		t.Errorf("Want 5 removed keys diffing with an empty map, Got %+v", d)
+	}
+}
+
+func TestMergeSortedMaps(t *testing.T) {
+	m1 := NewOrderedMapWithOrdering[int, string](compare.Reverse(compare.Less[int]))
+	m2 := NewOrderedMapWithOrdering[int, string](compare.Reverse(compare.Less[int]))
+	m1.Put(1, "a")
+	m1.Put(5, "b")
+	m2.Put(3, "c")
+	m2.Put(5, "d")
+
+	var keys []int
+	var vals []string
+	for k, v := range MergeSortedMaps[int, string](m1, m2) {
+		keys, vals = append(keys, k), append(vals, v)
+	}
+	if !slices.Equal(keys, []int{5, 5, 3, 1}) || !slices.Equal(vals, []string{"b", "d", "c", "a"}) {
+		t.Errorf("Want keys [5 5 3 1] with values [b d c a], Got %v and %v", keys, vals)
+	}
+}
@@ -1,11 +1,16 @@
 package kvmap
 
 import (
-	"golang.org/x/exp/constraints"
+	"cmp"
+	"fmt"
+	"iter"
+	"reflect"
+	"unsafe"
 
 	"github.org/jccarlson/collections"
 	"github.org/jccarlson/collections/compare"
 	"github.org/jccarlson/collections/internal/ds"
+	"github.org/jccarlson/collections/seq"
 )
 
 // orderedMapEntry is a struct wrapping a Key-Value pair in a
@@ -27,41 +32,107 @@ func (e *orderedMapEntry[K, V]) SetValue(v V) {
 	*e.value = v
 }
 
-// NewOrderedMap returns a new, empty OrderedMap with constraints.Ordered keys
-// (i.e. keys which support the '<' operator) and any value type.
-func NewOrderedMap[K constraints.Ordered, V any]() *OrderedMap[K, V] {
-	return &OrderedMap[K, V]{
-		Ordering: func(o1, o2 Entry[K, V]) bool {
-			return compare.Less(o1.Key(), o2.Key())
-		},
-	}
+// newOrderedMap returns a new, empty OrderedMap ordered by entryOrdering and
+// configured by opts.
+func newOrderedMap[K, V any](entryOrdering compare.Ordering[Entry[K, V]], opts []Option) *OrderedMap[K, V] {
+	var o kvMapOpts
+	mustApplyOptions(&o, opts)
+	return &OrderedMap[K, V]{Ordering: entryOrdering, MaxFree: o.maxFreeNodes}
+}
+
+// NewOrderedMap returns a new, empty OrderedMap with cmp.Ordered keys (i.e.
+// keys which support the '<' operator) and any value type.
+func NewOrderedMap[K cmp.Ordered, V any](opts ...Option) *OrderedMap[K, V] {
+	return newOrderedMap(func(o1, o2 Entry[K, V]) bool {
+		return compare.Less(o1.Key(), o2.Key())
+	}, opts)
 }
 
 // NewOrderedMapWithOrderableKeys returns a new, empty OrderedMap with
 // compare.Orderable keys and any value type.
-func NewOrderedMapWithOrderableKeys[K compare.Orderable[K], V any]() *OrderedMap[K, V] {
-	return &OrderedMap[K, V]{
-		Ordering: func(o1, o2 Entry[K, V]) bool {
-			return compare.OrderableOrdering(o1.Key(), o2.Key())
-		},
-	}
+func NewOrderedMapWithOrderableKeys[K compare.Orderable[K], V any](opts ...Option) *OrderedMap[K, V] {
+	return newOrderedMap(func(o1, o2 Entry[K, V]) bool {
+		return compare.OrderableOrdering(o1.Key(), o2.Key())
+	}, opts)
 }
 
 // NewOrderedMapWithOrdering returns a new, empty OrderedMap with any key
 // and value type, using ordering to order keys.
-func NewOrderedMapWithOrdering[K, V any](ordering compare.Ordering[K]) *OrderedMap[K, V] {
-	return &OrderedMap[K, V]{
-		Ordering: func(o1, o2 Entry[K, V]) bool {
-			return ordering(o1.Key(), o2.Key())
-		},
-	}
+func NewOrderedMapWithOrdering[K, V any](ordering compare.Ordering[K], opts ...Option) *OrderedMap[K, V] {
+	return newOrderedMap(func(o1, o2 Entry[K, V]) bool {
+		return ordering(o1.Key(), o2.Key())
+	}, opts)
+}
+
+// NewOrderedMapFunc returns a new, empty OrderedMap with any key and value
+// type, using a cmp-style comparison function such as cmp.Compare to order
+// keys.
+func NewOrderedMapFunc[K, V any](cmp func(k1, k2 K) int, opts ...Option) *OrderedMap[K, V] {
+	return NewOrderedMapWithOrdering[K, V](compare.CompareFunc(cmp), opts...)
 }
 
 // OrderedMap is a mapping of keys of type K to values of type
-// V, which iterates over entries in key order.
+// V, which iterates over entries in key order. The OrderedMap constructors
+// support the MaxFreeNodes() (default: 0) Option; other Options are ignored.
+//
+// The zero value of an OrderedMap whose key type has a cmp.Ordered
+// underlying type is an empty map ready to use, ordered by the '<' operator
+// like NewOrderedMap, so that it can be embedded in structs like a built-in
+// map. The zero value of an OrderedMap with other keys panics when first Put
+// to.
 type OrderedMap[K, V any] ds.RedBlackTree[Entry[K, V]]
 
+// underlyingOrdering returns an Ordering of entries by the '<' operator on
+// their keys, which must have underlying type T.
+func underlyingOrdering[K, V any, T cmp.Ordered]() compare.Ordering[Entry[K, V]] {
+	return func(o1, o2 Entry[K, V]) bool {
+		k1, k2 := o1.Key(), o2.Key()
+		return compare.Less(*(*T)(unsafe.Pointer(&k1)), *(*T)(unsafe.Pointer(&k2)))
+	}
+}
+
+// lazyInit gives the zero value of m the ordering of NewOrderedMap, if K's
+// underlying type is cmp.Ordered, and panics otherwise.
+func (m *OrderedMap[K, V]) lazyInit() {
+	if m.Ordering != nil {
+		return
+	}
+	switch t := reflect.TypeFor[K](); t.Kind() {
+	case reflect.Int:
+		m.Ordering = underlyingOrdering[K, V, int]()
+	case reflect.Int8:
+		m.Ordering = underlyingOrdering[K, V, int8]()
+	case reflect.Int16:
+		m.Ordering = underlyingOrdering[K, V, int16]()
+	case reflect.Int32:
+		m.Ordering = underlyingOrdering[K, V, int32]()
+	case reflect.Int64:
+		m.Ordering = underlyingOrdering[K, V, int64]()
+	case reflect.Uint:
+		m.Ordering = underlyingOrdering[K, V, uint]()
+	case reflect.Uint8:
+		m.Ordering = underlyingOrdering[K, V, uint8]()
+	case reflect.Uint16:
+		m.Ordering = underlyingOrdering[K, V, uint16]()
+	case reflect.Uint32:
+		m.Ordering = underlyingOrdering[K, V, uint32]()
+	case reflect.Uint64:
+		m.Ordering = underlyingOrdering[K, V, uint64]()
+	case reflect.Uintptr:
+		m.Ordering = underlyingOrdering[K, V, uintptr]()
+	case reflect.Float32:
+		m.Ordering = underlyingOrdering[K, V, float32]()
+	case reflect.Float64:
+		m.Ordering = underlyingOrdering[K, V, float64]()
+	case reflect.String:
+		m.Ordering = underlyingOrdering[K, V, string]()
+	default:
+		panic(fmt.Sprintf("zero value of OrderedMap used with key type %v, which is not cmp.Ordered; use a constructor", t))
+	}
+}
+
 func (m *OrderedMap[K, V]) Put(key K, value V) {
+	m.lazyInit()
 	(*ds.RedBlackTree[Entry[K, V]])(m).Put(&orderedMapEntry[K, V]{key: key, value: &value})
 }
 
@@ -81,10 +152,64 @@ func (m *OrderedMap[K, V]) Delete(key K) {
 	(*ds.RedBlackTree[Entry[K, V]])(m).Delete(&orderedMapEntry[K, V]{key: key})
 }
 
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m.
+func (m *OrderedMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	tree := (*ds.RedBlackTree[Entry[K, V]])(m)
+	_, val, ok = m.take(tree.Find(&orderedMapEntry[K, V]{key: key}))
+	return val, ok
+}
+
+// Take removes key from m and returns its value, or false if key was not in
+// m. It is the same as DeleteAndGet.
+func (m *OrderedMap[K, V]) Take(key K) (V, bool) {
+	return m.DeleteAndGet(key)
+}
+
+// TakeFirst removes the least key of m and returns it with its value, or
+// false if m is empty.
+func (m *OrderedMap[K, V]) TakeFirst() (K, V, bool) {
+	return m.take((*ds.RedBlackTree[Entry[K, V]])(m).First())
+}
+
+// TakeLast removes the greatest key of m and returns it with its value, or
+// false if m is empty.
+func (m *OrderedMap[K, V]) TakeLast() (K, V, bool) {
+	return m.take((*ds.RedBlackTree[Entry[K, V]])(m).Last())
+}
+
+// take deletes tn, if it isn't nil, and returns its key and value.
+func (m *OrderedMap[K, V]) take(tn *ds.TreeNode[Entry[K, V]]) (key K, val V, ok bool) {
+	if tn == nil {
+		return
+	}
+	key, val = tn.Elem.Key(), tn.Elem.Value()
+	(*ds.RedBlackTree[Entry[K, V]])(m).DeleteNode(tn)
+	return key, val, true
+}
+
+// ResetFreelist releases the tree nodes kept for reuse under MaxFreeNodes to
+// the garbage collector, e.g. after a burst of deletions which won't be
+// followed by insertions.
+func (m *OrderedMap[K, V]) ResetFreelist() {
+	(*ds.RedBlackTree[Entry[K, V]])(m).ResetFreelist()
+}
+
 func (m *OrderedMap[K, V]) Len() int {
 	return (*ds.RedBlackTree[Entry[K, V]])(m).Len()
 }
 
+// CountRange returns the number of keys of m in [from, to), in O(log n) time.
+func (m *OrderedMap[K, V]) CountRange(from, to K) int {
+	m.lazyInit()
+	tree := (*ds.RedBlackTree[Entry[K, V]])(m)
+	fromEntry, toEntry := &orderedMapEntry[K, V]{key: from}, &orderedMapEntry[K, V]{key: to}
+	if !tree.Ordering(fromEntry, toEntry) {
+		return 0
+	}
+	return tree.Rank(toEntry) - tree.Rank(fromEntry)
+}
+
 func (m *OrderedMap[K, V]) String() string {
 	return IterableMapToString[K, V](m)
 }
@@ -114,3 +239,221 @@ func (m *OrderedMap[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
 func (m *OrderedMap[K, V]) ReverseIterator() collections.Iterator[Entry[K, V]] {
 	return &orderedMapIterator[K, V]{direction: ds.Left, tn: (*ds.RedBlackTree[Entry[K, V]])(m).Last()}
 }
+
+// All returns an iter.Seq2 over the keys and values of m in ascending key
+// order. Iteration walks the tree in place, without goroutines or
+// allocation, and can be stopped early at no cost. m must not be modified
+// during iteration.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return m.Ascend
+}
+
+// Backward returns an iter.Seq2 over the keys and values of m in descending
+// key order. m must not be modified during iteration.
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return m.Descend
+}
+
+func (m *OrderedMap[K, V]) walk(tn *ds.TreeNode[Entry[K, V]], d ds.Direction, fn func(key K, val V) bool) {
+	for ; tn != nil; tn = tn.Walk(d) {
+		if !fn(tn.Elem.Key(), tn.Elem.Value()) {
+			return
+		}
+	}
+}
+
+// Ascend calls fn for each entry of m in ascending key order, until fn
+// returns false.
+func (m *OrderedMap[K, V]) Ascend(fn func(key K, val V) bool) {
+	m.walk((*ds.RedBlackTree[Entry[K, V]])(m).First(), ds.Right, fn)
+}
+
+// AscendGreaterOrEqual calls fn for each entry of m with a key not before
+// pivot, in ascending key order, until fn returns false.
+func (m *OrderedMap[K, V]) AscendGreaterOrEqual(pivot K, fn func(key K, val V) bool) {
+	m.walk((*ds.RedBlackTree[Entry[K, V]])(m).Ceiling(&orderedMapEntry[K, V]{key: pivot}), ds.Right, fn)
+}
+
+// Descend calls fn for each entry of m in descending key order, until fn
+// returns false.
+func (m *OrderedMap[K, V]) Descend(fn func(key K, val V) bool) {
+	m.walk((*ds.RedBlackTree[Entry[K, V]])(m).Last(), ds.Left, fn)
+}
+
+// DescendLessOrEqual calls fn for each entry of m with a key not after pivot,
+// in descending key order, until fn returns false.
+func (m *OrderedMap[K, V]) DescendLessOrEqual(pivot K, fn func(key K, val V) bool) {
+	m.walk((*ds.RedBlackTree[Entry[K, V]])(m).Floor(&orderedMapEntry[K, V]{key: pivot}), ds.Left, fn)
+}
+
+// MergeOrderedMaps returns an iter.Seq2 over the keys and values of maps,
+// which must all order keys the same way, in ascending key order. A key in
+// several maps is yielded once for each, in the order of maps. The maps
+// must not be modified during iteration.
+func MergeOrderedMaps[K, V any](maps ...*OrderedMap[K, V]) iter.Seq2[K, V] {
+	sorted := make([]SortedIterable[K, V], len(maps))
+	for i, m := range maps {
+		sorted[i] = m
+	}
+	return MergeSortedMaps(sorted...)
+}
+
+// KeyOrdering returns the ordering of m's keys.
+func (m *OrderedMap[K, V]) KeyOrdering() compare.Ordering[K] {
+	m.lazyInit()
+	ordering := m.Ordering
+	return func(k1, k2 K) bool {
+		return ordering(&orderedMapEntry[K, V]{key: k1}, &orderedMapEntry[K, V]{key: k2})
+	}
+}
+
+// Clone returns a copy of m with the same ordering and options, copying values
+// which implement collections.Cloner with their Clone method. It takes O(n)
+// time.
+func (m *OrderedMap[K, V]) Clone() *OrderedMap[K, V] {
+	c := &OrderedMap[K, V]{Ordering: m.Ordering, MaxFree: m.MaxFree}
+	entries := make([]Entry[K, V], 0, m.Len())
+	for k, v := range m.All() {
+		v = collections.DeepClone(v, nil)
+		entries = append(entries, &orderedMapEntry[K, V]{key: k, value: &v})
+	}
+	(*ds.RedBlackTree[Entry[K, V]])(c).Build(entries)
+	return c
+}
+
+// CollectSortedMerge returns a new OrderedMap holding the entries of seqs,
+// each of which must yield keys in ascending order. The seqs are merged in
+// one linear pass and the map is built balanced in O(n) time, which is
+// faster than Putting each entry. Where several entries have the same key,
+// resolve is called with the key, the value collected so far and the next
+// value, in the order of seqs, and returns the value to keep; if resolve is
+// nil, the last value is kept. It panics if a seq is not in order.
+func CollectSortedMerge[K cmp.Ordered, V any](resolve func(key K, v1, v2 V) V, seqs ...iter.Seq2[K, V]) *OrderedMap[K, V] {
+	return CollectSortedMergeWithOrdering(compare.Less[K], resolve, seqs...)
+}
+
+// CollectSortedMergeWithOrdering is like CollectSortedMerge, but for any key
+// type, using ordering to order keys.
+func CollectSortedMergeWithOrdering[K, V any](ordering compare.Ordering[K], resolve func(key K, v1, v2 V) V, seqs ...iter.Seq2[K, V]) *OrderedMap[K, V] {
+	m := NewOrderedMapWithOrdering[K, V](ordering)
+	var entries []Entry[K, V]
+	var last *orderedMapEntry[K, V]
+	for k, v := range seq.MergeSorted2(ordering, seqs...) {
+		if last != nil && !ordering(last.key, k) {
+			if ordering(k, last.key) {
+				panic("CollectSortedMerge called with a seq not in ascending key order")
+			}
+			if resolve == nil {
+				*last.value = v
+			} else {
+				*last.value = resolve(k, *last.value, v)
+			}
+			continue
+		}
+		last = &orderedMapEntry[K, V]{key: k, value: &v}
+		entries = append(entries, last)
+	}
+	(*ds.RedBlackTree[Entry[K, V]])(m).Build(entries)
+	return m
+}
+
+// OrderedMapCursor is a position in an OrderedMap, either at an entry or
+// between two adjacent entries (or before the first or after the last),
+// which can move through the map in either direction, replace values and
+// delete entries as it goes. The map must not be modified during traversal,
+// except through the cursor.
+type OrderedMapCursor[K, V any] struct {
+	m *OrderedMap[K, V]
+	// tn is the entry the cursor is at. If it is nil, the cursor is between
+	// prev and next, either of which is nil at the ends of the map.
+	tn         *ds.TreeNode[Entry[K, V]]
+	prev, next *ds.TreeNode[Entry[K, V]]
+}
+
+// SeekCursor returns a cursor at the first entry of m with a key not before
+// key, or after the last entry if there is none.
+func (m *OrderedMap[K, V]) SeekCursor(key K) *OrderedMapCursor[K, V] {
+	tree := (*ds.RedBlackTree[Entry[K, V]])(m)
+	c := &OrderedMapCursor[K, V]{m: m, tn: tree.Ceiling(&orderedMapEntry[K, V]{key: key})}
+	if c.tn == nil {
+		c.prev = tree.Last()
+	}
+	return c
+}
+
+// Valid returns true if c is at an entry.
+func (c *OrderedMapCursor[K, V]) Valid() bool {
+	return c.tn != nil
+}
+
+func (c *OrderedMapCursor[K, V]) entry() Entry[K, V] {
+	if c.tn == nil {
+		panic("OrderedMapCursor is not at an entry")
+	}
+	return c.tn.Elem
+}
+
+// Key returns the key of the entry c is at. It panics if c is not Valid.
+func (c *OrderedMapCursor[K, V]) Key() K {
+	return c.entry().Key()
+}
+
+// Value returns the value of the entry c is at. It panics if c is not Valid.
+func (c *OrderedMapCursor[K, V]) Value() V {
+	return c.entry().Value()
+}
+
+// SetValue replaces the value of the entry c is at, without moving c. It
+// panics if c is not Valid.
+func (c *OrderedMapCursor[K, V]) SetValue(val V) {
+	c.entry().SetValue(val)
+}
+
+// Next moves c to the next entry in key order, and returns false if there is
+// none, leaving c after the last entry. After DeleteCurrent, Next moves to
+// the entry which followed the deleted one.
+func (c *OrderedMapCursor[K, V]) Next() bool {
+	if c.tn == nil {
+		if c.next == nil {
+			return false
+		}
+		c.tn, c.prev, c.next = c.next, nil, nil
+		return true
+	}
+	if next := c.tn.Walk(ds.Right); next != nil {
+		c.tn = next
+		return true
+	}
+	c.tn, c.prev = nil, c.tn
+	return false
+}
+
+// Prev moves c to the previous entry in key order, and returns false if
+// there is none, leaving c before the first entry. After DeleteCurrent, Prev
+// moves to the entry which preceded the deleted one.
+func (c *OrderedMapCursor[K, V]) Prev() bool {
+	if c.tn == nil {
+		if c.prev == nil {
+			return false
+		}
+		c.tn, c.prev, c.next = c.prev, nil, nil
+		return true
+	}
+	if prev := c.tn.Walk(ds.Left); prev != nil {
+		c.tn = prev
+		return true
+	}
+	c.tn, c.next = nil, c.tn
+	return false
+}
+
+// DeleteCurrent deletes the entry c is at from the map, leaving c between
+// the entries before and after it. It panics if c is not Valid.
+func (c *OrderedMapCursor[K, V]) DeleteCurrent() {
+	if c.tn == nil {
+		panic("OrderedMapCursor is not at an entry")
+	}
+	c.prev = c.tn.Walk(ds.Left)
+	c.next = (*ds.RedBlackTree[Entry[K, V]])(c.m).DeleteNode(c.tn)
+	c.tn = nil
+}
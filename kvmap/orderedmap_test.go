@@ -0,0 +1,352 @@
+package kvmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestOrderedMapAscendDescend(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		m.Put(k, fmt.Sprint(k))
+	}
+
+	collect := func(traverse func(fn func(int, string) bool), limit int) []int {
+		var keys []int
+		traverse(func(k int, v string) bool {
+			if v != fmt.Sprint(k) {
+				t.Errorf("Want value %q for key %d, Got %q", fmt.Sprint(k), k, v)
+			}
+			keys = append(keys, k)
+			return len(keys) < limit
+		})
+		return keys
+	}
+
+	tcs := []struct {
+		name     string
+		traverse func(fn func(int, string) bool)
+		limit    int
+		want     []int
+	}{
+		{"Ascend", m.Ascend, 10, []int{10, 20, 30, 40, 50}},
+		{"AscendEarlyExit", m.Ascend, 2, []int{10, 20}},
+		{"Descend", m.Descend, 10, []int{50, 40, 30, 20, 10}},
+		{"AscendGreaterOrEqualPresent", func(fn func(int, string) bool) { m.AscendGreaterOrEqual(30, fn) }, 10, []int{30, 40, 50}},
+		{"AscendGreaterOrEqualAbsent", func(fn func(int, string) bool) { m.AscendGreaterOrEqual(35, fn) }, 10, []int{40, 50}},
+		{"AscendGreaterOrEqualPastEnd", func(fn func(int, string) bool) { m.AscendGreaterOrEqual(51, fn) }, 10, nil},
+		{"DescendLessOrEqualPresent", func(fn func(int, string) bool) { m.DescendLessOrEqual(30, fn) }, 10, []int{30, 20, 10}},
+		{"DescendLessOrEqualAbsent", func(fn func(int, string) bool) { m.DescendLessOrEqual(25, fn) }, 2, []int{20, 10}},
+		{"DescendLessOrEqualBeforeStart", func(fn func(int, string) bool) { m.DescendLessOrEqual(5, fn) }, 10, nil},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := collect(tc.traverse, tc.limit); !equalInts(got, tc.want) {
+				t.Errorf("Want %v, Got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAllAndBackward(t *testing.T) {
+	om := NewOrderedMap[int, int]()
+	lhm := NewComparableLinkedHashMap[int, int]()
+	for _, k := range []int{3, 1, 2} {
+		om.Put(k, k*10)
+		lhm.Put(k, k*10)
+	}
+
+	tcs := []struct {
+		name string
+		seq  func(yield func(int, int) bool)
+		want []int
+	}{
+		{"OrderedMap.All", om.All(), []int{1, 2, 3}},
+		{"OrderedMap.Backward", om.Backward(), []int{3, 2, 1}},
+		{"LinkedHashMap.All", lhm.All(), []int{3, 1, 2}},
+		{"LinkedHashMap.Backward", lhm.Backward(), []int{2, 1, 3}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var keys []int
+			for k, v := range tc.seq {
+				if v != k*10 {
+					t.Errorf("Want value %d for key %d, Got %d", k*10, k, v)
+				}
+				keys = append(keys, k)
+			}
+			if !equalInts(keys, tc.want) {
+				t.Errorf("Want keys %v, Got %v", tc.want, keys)
+			}
+
+			// Stopping early must not leak or panic.
+			for range tc.seq {
+				break
+			}
+		})
+	}
+
+	mw := NewMapWrapper[int, int]()
+	mw.Put(1, 10)
+	for k, v := range mw.All() {
+		if k != 1 || v != 10 {
+			t.Errorf("Want MapWrapper.All() to yield (1, 10), Got (%d, %d)", k, v)
+		}
+	}
+}
+
+func TestOrderedMapMaxFreeNodes(t *testing.T) {
+	churn := func(m *OrderedMap[int, int]) float64 {
+		for i := 0; i < 100; i++ {
+			m.Put(i, i)
+		}
+		return testing.AllocsPerRun(100, func() {
+			m.Delete(50)
+			m.Put(50, 50)
+		})
+	}
+	without := churn(NewOrderedMap[int, int]())
+	m := NewOrderedMap[int, int](MaxFreeNodes(8))
+	with := churn(m)
+	if with != without-1 {
+		t.Errorf("Want MaxFreeNodes to save the node allocation per Put, Got %v allocs with and %v without", with, without)
+	}
+
+	for i := 0; i < 8; i++ {
+		m.Delete(i)
+	}
+	m.ResetFreelist()
+	k := 1000
+	if got := testing.AllocsPerRun(4, func() {
+		m.Put(k, k)
+		k++
+	}); got != without-1 {
+		t.Errorf("Want %v allocs per Put after ResetFreelist, Got %v", without-1, got)
+	}
+}
+
+func TestOrderedMapCountRange(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		m.Put(k, fmt.Sprint(k))
+	}
+	m.Delete(40)
+
+	tcs := []struct {
+		from, to, want int
+	}{
+		{0, 100, 4},
+		{10, 30, 2},
+		{15, 31, 2},
+		{30, 30, 0},
+		{50, 10, 0},
+		{51, 100, 0},
+		{0, 10, 0},
+		{30, 51, 2},
+	}
+	for _, tc := range tcs {
+		if got := m.CountRange(tc.from, tc.to); got != tc.want {
+			t.Errorf("Want CountRange(%d, %d) == %d, Got %d", tc.from, tc.to, tc.want, got)
+		}
+	}
+}
+
+func TestOrderedMapCursor(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for k := 0; k < 100; k++ {
+		m.Put(k, fmt.Sprint(k))
+	}
+
+	// A compaction-style pass: delete odd keys and rewrite the rest.
+	c := m.SeekCursor(-1)
+	for ok := c.Valid(); ok; ok = c.Next() {
+		if c.Key()%2 == 1 {
+			c.DeleteCurrent()
+			if c.Valid() {
+				t.Fatalf("Want cursor between entries after DeleteCurrent, Got Valid()")
+			}
+			continue
+		}
+		c.SetValue("even")
+	}
+	if m.Len() != 50 {
+		t.Fatalf("Want 50 entries after deleting odd keys, Got %d", m.Len())
+	}
+	want := 0
+	for k, v := range m.All() {
+		if k != want || v != "even" {
+			t.Fatalf("Want (%d, even), Got (%d, %s)", want, k, v)
+		}
+		want += 2
+	}
+
+	// Deleting an entry may move its successor into its tree node; the cursor
+	// must still step to the right neighbours.
+	c = m.SeekCursor(31)
+	if !c.Valid() || c.Key() != 32 {
+		t.Fatalf("Want SeekCursor(31) at 32, Got Valid() == %t", c.Valid())
+	}
+	c.DeleteCurrent()
+	if !c.Prev() || c.Key() != 30 {
+		t.Errorf("Want Prev() after DeleteCurrent at 30, Got %d", c.Key())
+	}
+	c.Next()
+	c.DeleteCurrent()
+	if !c.Next() || c.Key() != 36 {
+		t.Errorf("Want Next() after deleting 34 at 36, Got %d", c.Key())
+	}
+
+	c = m.SeekCursor(1000)
+	if c.Valid() {
+		t.Fatalf("Want SeekCursor past the end to be invalid, Got %d", c.Key())
+	}
+	if c.Next() {
+		t.Errorf("Want Next() past the end to be false, Got true")
+	}
+	if !c.Prev() || c.Key() != 98 {
+		t.Errorf("Want Prev() from past the end at 98, Got Valid() == %t", c.Valid())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want Key() on an invalid cursor to panic, Got no panic")
+		}
+	}()
+	NewOrderedMap[int, int]().SeekCursor(0).Key()
+}
+
+func TestMergeOrderedMaps(t *testing.T) {
+	a, b := NewOrderedMap[int, string](), NewOrderedMap[int, string]()
+	for _, k := range []int{1, 4, 6} {
+		a.Put(k, "a")
+	}
+	for _, k := range []int{2, 4, 8} {
+		b.Put(k, "b")
+	}
+	var got []string
+	for k, v := range MergeOrderedMaps(a, b) {
+		got = append(got, fmt.Sprint(k, v))
+	}
+	want := []string{"1a", "2b", "4a", "4b", "6a", "8b"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+}
+
+func TestCollectSortedMerge(t *testing.T) {
+	a, b := NewOrderedMap[int, int](), NewOrderedMap[int, int]()
+	for _, k := range []int{1, 3, 5, 7} {
+		a.Put(k, k)
+	}
+	for _, k := range []int{2, 3, 7, 9} {
+		b.Put(k, 10*k)
+	}
+	m := CollectSortedMerge(func(_ int, v1, v2 int) int { return v1 + v2 }, a.All(), b.All())
+	if m.Len() != 6 {
+		t.Errorf("Want Len() = 6, Got %d", m.Len())
+	}
+	var got []string
+	for k, v := range m.All() {
+		got = append(got, fmt.Sprint(k, ":", v))
+	}
+	want := []string{"1:1", "2:20", "3:33", "5:5", "7:77", "9:90"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+
+	m = CollectSortedMerge(nil, a.All(), b.All())
+	if v, _ := m.Get(3); v != 30 {
+		t.Errorf("Want last value 30 for key 3, Got %d", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want panic for unsorted seq, Got none")
+		}
+	}()
+	CollectSortedMerge(nil, a.Backward())
+}
+
+func TestOrderedMapClone(t *testing.T) {
+	m := NewOrderedMap[int, []int]()
+	for i := 0; i < 10; i++ {
+		m.Put(i, []int{i})
+	}
+	c := m.Clone()
+	c.Put(10, nil)
+	c.Delete(0)
+	if m.Len() != 10 || !m.Has(0) || m.Has(10) {
+		t.Errorf("Want original map unchanged by edits to its clone, Got %v", m)
+	}
+
+	d := DeepCloneInto[int, []int](NewComparableLinkedHashMap[int, []int](), m, slices.Clone)
+	v, _ := d.Get(3)
+	v[0] = 30
+	if v, _ := m.Get(3); v[0] != 3 {
+		t.Errorf("Want original value [3], Got %v", v)
+	}
+	if d.Len() != 10 {
+		t.Errorf("Want Len() == 10, Got %d", d.Len())
+	}
+}
+
+type celsius float64
+
+func TestOrderedMapZeroValue(t *testing.T) {
+	var s struct {
+		m OrderedMap[celsius, string]
+	}
+	if _, ok := s.m.Get(0); ok || s.m.Len() != 0 {
+		t.Errorf("Want empty zero value, Got %v", &s.m)
+	}
+	for _, k := range []celsius{21.5, -4, 100, 0} {
+		s.m.Put(k, fmt.Sprint(k))
+	}
+	if got := s.m.String(); got != "map[-4:-4 0:0 21.5:21.5 100:100]" {
+		t.Errorf("Want map[-4:-4 0:0 21.5:21.5 100:100], Got %s", got)
+	}
+	if n := s.m.CountRange(0, 100); n != 2 {
+		t.Errorf("Want CountRange(0, 100) == 2, Got %d", n)
+	}
+
+	var j OrderedMap[string, int]
+	if err := json.Unmarshal([]byte(`{"b": 2, "a": 1}`), &j); err != nil || j.String() != "map[a:1 b:2]" {
+		t.Errorf("Want map[a:1 b:2], Got %v, %v", &j, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want Put to panic for a zero value with unordered keys, Got no panic")
+		}
+	}()
+	var bad OrderedMap[struct{}, int]
+	bad.Put(struct{}{}, 1)
+}
+
+func TestOrderedMapTake(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for i, s := range []string{"a", "b", "c", "d"} {
+		m.Put(3-i, s)
+	}
+	if k, v, ok := m.TakeFirst(); !ok || k != 0 || v != "d" {
+		t.Errorf(`Want TakeFirst() == (0, "d", true), Got (%d, %q, %t)`, k, v, ok)
+	}
+	if k, v, ok := m.TakeLast(); !ok || k != 3 || v != "a" {
+		t.Errorf(`Want TakeLast() == (3, "a", true), Got (%d, %q, %t)`, k, v, ok)
+	}
+	if v, ok := m.Take(2); !ok || v != "b" {
+		t.Errorf(`Want Take(2) == ("b", true), Got (%q, %t)`, v, ok)
+	}
+	if v, ok := m.Take(2); ok {
+		t.Errorf(`Want Take(2) == ("", false) after Take, Got (%q, %t)`, v, ok)
+	}
+	if m.String() != "map[1:c]" {
+		t.Errorf("Want map[1:c], Got %v", m)
+	}
+	m.TakeLast()
+	if _, _, ok := m.TakeFirst(); ok || m.Len() != 0 {
+		t.Errorf("Want TakeFirst() to fail on empty map, Got ok == %t, Len() == %d", ok, m.Len())
+	}
+}
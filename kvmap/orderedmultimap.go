@@ -0,0 +1,118 @@
+package kvmap
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+// OrderedMultimap is a Multimap which iterates over keys in order, and keeps
+// the values of equal keys in the order they were Put. Unlike an OrderedMap,
+// which replaces the value of an equal key, it keeps every value Put.
+type OrderedMultimap[K, V any] struct {
+	m    *OrderedMap[K, *[]V]
+	size int
+}
+
+// NewOrderedMultimap returns a pointer to a new OrderedMultimap of keys
+// ordered by cmp.Less. opts configure the underlying OrderedMap of keys.
+func NewOrderedMultimap[K cmp.Ordered, V any](opts ...Option) *OrderedMultimap[K, V] {
+	return &OrderedMultimap[K, V]{m: NewOrderedMap[K, *[]V](opts...)}
+}
+
+// NewOrderedMultimapWithOrdering returns a pointer to a new OrderedMultimap of
+// keys ordered by ordering.
+func NewOrderedMultimapWithOrdering[K, V any](ordering compare.Ordering[K], opts ...Option) *OrderedMultimap[K, V] {
+	return &OrderedMultimap[K, V]{m: NewOrderedMapWithOrdering[K, *[]V](ordering, opts...)}
+}
+
+func (m *OrderedMultimap[K, V]) Put(key K, val V) {
+	if vals, ok := m.m.Get(key); ok {
+		*vals = append(*vals, val)
+	} else {
+		m.m.Put(key, &[]V{val})
+	}
+	m.size++
+}
+
+// Get returns the values of key in the order they were Put. The returned
+// slice must not be modified.
+func (m *OrderedMultimap[K, V]) Get(key K) []V {
+	if vals, ok := m.m.Get(key); ok {
+		return slices.Clip(*vals)
+	}
+	return nil
+}
+
+// EqualRange returns an iter.Seq over the values of key in the order they were
+// Put. m must not be modified during iteration.
+func (m *OrderedMultimap[K, V]) EqualRange(key K) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		vals, ok := m.m.Get(key)
+		if !ok {
+			return
+		}
+		for _, v := range *vals {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Count returns the number of values of key.
+func (m *OrderedMultimap[K, V]) Count(key K) int {
+	if vals, ok := m.m.Get(key); ok {
+		return len(*vals)
+	}
+	return 0
+}
+
+func (m *OrderedMultimap[K, V]) Delete(key K) {
+	if vals, ok := m.m.DeleteAndGet(key); ok {
+		m.size -= len(*vals)
+	}
+}
+
+func (m *OrderedMultimap[K, V]) Has(key K) bool {
+	return m.m.Has(key)
+}
+
+func (m *OrderedMultimap[K, V]) Len() int {
+	return m.size
+}
+
+// KeyLen returns the number of distinct keys in m.
+func (m *OrderedMultimap[K, V]) KeyLen() int {
+	return m.m.Len()
+}
+
+// All returns an iter.Seq2 over the keys of m in order and their values. The
+// yielded slices must not be modified, and m must not be modified during
+// iteration.
+func (m *OrderedMultimap[K, V]) All() iter.Seq2[K, []V] {
+	return func(yield func(K, []V) bool) {
+		for k, vals := range m.m.All() {
+			if !yield(k, slices.Clip(*vals)) {
+				return
+			}
+		}
+	}
+}
+
+// Pairs returns an iter.Seq2 over every key and value of m, in key order and
+// then in the order the values of each key were Put, so that a key with n
+// values is yielded n times. m must not be modified during iteration.
+func (m *OrderedMultimap[K, V]) Pairs() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, vals := range m.m.All() {
+			for _, v := range *vals {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
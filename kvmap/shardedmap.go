@@ -0,0 +1,158 @@
+package kvmap
+
+import (
+	"slices"
+	"sync"
+
+	"github.org/jccarlson/collections/concurrent"
+)
+
+type mapShard[K, V any] struct {
+	lock sync.RWMutex
+	m    Interface[K, V]
+}
+
+// ShardedMap is a map which is safe for concurrent use, which spreads its
+// keys by hash across a fixed number of shards, each a map with its own lock,
+// so that operations on keys in different shards don't contend.
+type ShardedMap[K, V any] struct {
+	hasher MapHasher[K]
+	shards []mapShard[K, V]
+	// size tracks the total Len of the shards, without locking them.
+	size concurrent.Adder
+}
+
+// NewShardedMap returns a pointer to a new ShardedMap with n shards, rounded
+// up to a power of 2, each an empty map returned by newShard. Keys are
+// assigned to shards by hasher.
+func NewShardedMap[K, V any](n int, hasher MapHasher[K], newShard func() Interface[K, V]) *ShardedMap[K, V] {
+	if n <= 0 {
+		panic("ShardedMap must have > 0 shards")
+	}
+	c := 1
+	for c < n {
+		c <<= 1
+	}
+	m := &ShardedMap[K, V]{hasher: hasher, shards: make([]mapShard[K, V], c)}
+	for i := range m.shards {
+		m.shards[i].m = newShard()
+	}
+	return m
+}
+
+// NewComparableShardedMap returns a pointer to a new ShardedMap with
+// comparable keys and n shards, each a LinkedHashMap configured by opts.
+func NewComparableShardedMap[K comparable, V any](n int, opts ...Option) *ShardedMap[K, V] {
+	return NewShardedMap(n, ComparableMapHasher[K](), func() Interface[K, V] {
+		return NewComparableLinkedHashMap[K, V](opts...)
+	})
+}
+
+// shardIndex returns the index of the shard holding key.
+func (m *ShardedMap[K, V]) shardIndex(key *K) int {
+	// The shards hash keys with their own seeds, so the low bits used to
+	// pick a shard are not correlated with their slots within it.
+	return int(m.hasher.Hash(key)) & (len(m.shards) - 1)
+}
+
+func (m *ShardedMap[K, V]) Put(key K, val V) {
+	s := &m.shards[m.shardIndex(&key)]
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := s.m.Len()
+	s.m.Put(key, val)
+	m.size.Add(int64(s.m.Len() - n))
+}
+
+func (m *ShardedMap[K, V]) Get(key K) (val V, ok bool) {
+	s := &m.shards[m.shardIndex(&key)]
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Get(key)
+}
+
+func (m *ShardedMap[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m, as one atomic operation.
+func (m *ShardedMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	s := &m.shards[m.shardIndex(&key)]
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if val, ok = DeleteAndGet(s.m, key); ok {
+		m.size.Add(-1)
+	}
+	return val, ok
+}
+
+func (m *ShardedMap[K, V]) Has(key K) bool {
+	s := &m.shards[m.shardIndex(&key)]
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Has(key)
+}
+
+// Len returns the number of entries in m. While m is being modified, it may
+// not reflect every concurrent Put and Delete.
+func (m *ShardedMap[K, V]) Len() int {
+	return int(m.size.Sum())
+}
+
+// shardedView is the MutableView of a ShardedMap's locked shards.
+type shardedView[K, V any] struct {
+	m      *ShardedMap[K, V]
+	locked []int
+}
+
+func (v *shardedView[K, V]) shard(key *K) Interface[K, V] {
+	i := v.m.shardIndex(key)
+	if _, ok := slices.BinarySearch(v.locked, i); !ok {
+		panic("MutableView used with a key not passed to UpdateBatch")
+	}
+	return v.m.shards[i].m
+}
+
+func (v *shardedView[K, V]) Put(key K, val V) {
+	v.shard(&key).Put(key, val)
+}
+
+func (v *shardedView[K, V]) Get(key K) (V, bool) {
+	return v.shard(&key).Get(key)
+}
+
+func (v *shardedView[K, V]) Delete(key K) {
+	v.shard(&key).Delete(key)
+}
+
+func (v *shardedView[K, V]) Has(key K) bool {
+	return v.shard(&key).Has(key)
+}
+
+// UpdateBatch locks the shards holding keys and calls f with a view of them,
+// so that f's reads and writes of keys are applied atomically with respect
+// to other goroutines. Shards are locked in index order, so concurrent
+// batches can't deadlock. f must only use the view with keys; the view
+// panics if used with a key in a shard which isn't locked.
+func (m *ShardedMap[K, V]) UpdateBatch(keys []K, f func(view MutableView[K, V])) {
+	locked := make([]int, len(keys))
+	for i := range keys {
+		locked[i] = m.shardIndex(&keys[i])
+	}
+	slices.Sort(locked)
+	locked = slices.Compact(locked)
+	n := 0
+	for _, i := range locked {
+		m.shards[i].lock.Lock()
+		n += m.shards[i].m.Len()
+	}
+	defer func() {
+		for _, i := range locked {
+			n -= m.shards[i].m.Len()
+			m.shards[i].lock.Unlock()
+		}
+		m.size.Add(int64(-n))
+	}()
+	f(&shardedView[K, V]{m: m, locked: locked})
+}
@@ -0,0 +1,159 @@
+package kvmap
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// batchUpdater is implemented by the maps with UpdateBatch.
+type batchUpdater[K, V any] interface {
+	Interface[K, V]
+	UpdateBatch(keys []K, f func(view MutableView[K, V]))
+}
+
+func testTransfers(t *testing.T, m batchUpdater[int, int]) {
+	const accounts = 32
+	for i := 0; i < accounts; i++ {
+		m.Put(i, 100)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				from, to := (g*7+i)%accounts, (g*13+i*3+1)%accounts
+				if from == to {
+					continue
+				}
+				m.UpdateBatch([]int{from, to}, func(view MutableView[int, int]) {
+					a, _ := view.Get(from)
+					b, _ := view.Get(to)
+					view.Put(from, a-1)
+					view.Put(to, b+1)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for i := 0; i < accounts; i++ {
+		v, _ := m.Get(i)
+		total += v
+	}
+	if total != accounts*100 {
+		t.Errorf("Want transfers to preserve a total of %d, Got %d", accounts*100, total)
+	}
+}
+
+func TestShardedMapUpdateBatch(t *testing.T) {
+	testTransfers(t, NewComparableShardedMap[int, int](8))
+}
+
+func TestConcurrentWrapperUpdateBatch(t *testing.T) {
+	testTransfers(t, &ConcurrentWrapper[int, int]{Base: NewComparableLinkedHashMap[int, int]()})
+}
+
+func TestShardedMap(t *testing.T) {
+	m := NewComparableShardedMap[string, int](3)
+	if len(m.shards) != 4 {
+		t.Errorf("Want 3 shards rounded up to 4, Got %d", len(m.shards))
+	}
+	for i, k := range []string{"a", "b", "c", "d", "e"} {
+		m.Put(k, i)
+	}
+	m.Delete("c")
+	if m.Len() != 4 || m.Has("c") {
+		t.Errorf("Want Len() == 4 without c, Got Len() == %d, Has(c) == %t", m.Len(), m.Has("c"))
+	}
+	if v, ok := m.Get("e"); !ok || v != 4 {
+		t.Errorf("Want Get(e) == (4, true), Got (%d, %t)", v, ok)
+	}
+}
+
+func TestConcurrentWrapperUpdateBatchUndeclaredKeyPanics(t *testing.T) {
+	m := &ConcurrentWrapper[string, int]{Base: NewMapWrapper[string, int]()}
+	m.UpdateBatch([]string{"a", "b"}, func(view MutableView[string, int]) {
+		view.Put("a", 1)
+		if v, ok := view.Get("a"); ok {
+			view.Put("b", v+1)
+		}
+	})
+	if v, _ := m.Get("b"); v != 2 {
+		t.Errorf("Want Get(b) == 2 after the batch, Got %d", v)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want view use with a key not passed to UpdateBatch to panic, Got no panic")
+		}
+	}()
+	m.UpdateBatch([]string{"a"}, func(view MutableView[string, int]) { view.Get("c") })
+}
+
+func TestShardedMapUpdateBatchUnlockedKeyPanics(t *testing.T) {
+	m := NewComparableShardedMap[int, int](64)
+	zero, other := 0, 1
+	for m.shardIndex(&other) == m.shardIndex(&zero) {
+		other++
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want view use with a key in an unlocked shard to panic, Got no panic")
+		}
+	}()
+	m.UpdateBatch([]int{zero}, func(view MutableView[int, int]) { view.Put(other, 1) })
+}
+
+func TestShardedMapLenConcurrent(t *testing.T) {
+	m := NewComparableShardedMap[int, int](4)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				m.Put(g*200+i, i)
+				if i%2 == 0 {
+					m.Delete(g*200 + i)
+				}
+			}
+			m.UpdateBatch([]int{-g - 1}, func(view MutableView[int, int]) { view.Put(-g-1, g) })
+		}()
+	}
+	wg.Wait()
+	if m.Len() != 8*100+8 {
+		t.Errorf("Want Len() == %d, Got %d", 8*100+8, m.Len())
+	}
+}
+
+func TestShardedMapDeleteAndGetConcurrent(t *testing.T) {
+	m := NewComparableShardedMap[int, int](4)
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	var deleted [1000]atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if v, ok := m.DeleteAndGet(i); ok {
+					deleted[v].Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i := range deleted {
+		if n := deleted[i].Load(); n != 1 {
+			t.Errorf("Want one DeleteAndGet(%d) to succeed, Got %d", i, n)
+		}
+	}
+	if m.Len() != 0 {
+		t.Errorf("Want Len() == 0, Got %d", m.Len())
+	}
+}
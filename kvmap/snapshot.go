@@ -0,0 +1,116 @@
+package kvmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// snapshotMagic begins every OrderedMap snapshot. It is followed by the
+// uvarint number of entries, then each key and value in ascending key order
+// as uvarint-length-prefixed bytes.
+const snapshotMagic = "KVSNAP01"
+
+// ErrCorruptSnapshot is returned by ReadSnapshot if its input is not a
+// snapshot written by WriteSnapshot.
+var ErrCorruptSnapshot = errors.New("kvmap: corrupt OrderedMap snapshot")
+
+// WriteSnapshot writes the entries of m to w in a compact binary format, in
+// ascending key order, encoding keys with keyCodec and values with valCodec.
+// The snapshot can be restored with ReadSnapshot.
+func (m *OrderedMap[K, V]) WriteSnapshot(w io.Writer, keyCodec Codec[K], valCodec Codec[V]) error {
+	bw := bufio.NewWriter(w)
+	buf := binary.AppendUvarint([]byte(snapshotMagic), uint64(m.Len()))
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+	for tn := (*ds.RedBlackTree[Entry[K, V]])(m).First(); tn != nil; tn = tn.Walk(ds.Right) {
+		kb, err := keyCodec.Encode(tn.Elem.Key())
+		if err != nil {
+			return err
+		}
+		vb, err := valCodec.Encode(tn.Elem.Value())
+		if err != nil {
+			return err
+		}
+		buf = binary.AppendUvarint(buf[:0], uint64(len(kb)))
+		buf = append(buf, kb...)
+		buf = binary.AppendUvarint(buf, uint64(len(vb)))
+		buf = append(buf, vb...)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadSnapshot replaces the entries of m with those of a snapshot written by
+// WriteSnapshot with the same codecs. Since the entries are already sorted,
+// the tree is built directly in O(n) time rather than by n Puts. m must have
+// an ordering consistent with the snapshot's; if the keys are not in strictly
+// ascending order under m's ordering, ErrCorruptSnapshot is returned and m is
+// left unchanged.
+func (m *OrderedMap[K, V]) ReadSnapshot(r io.Reader, keyCodec Codec[K], valCodec Codec[V]) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != snapshotMagic {
+		return ErrCorruptSnapshot
+	}
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return ErrCorruptSnapshot
+	}
+
+	readField := func() ([]byte, error) {
+		l, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, ErrCorruptSnapshot
+		}
+		if l > math.MaxInt64 {
+			return nil, ErrCorruptSnapshot
+		}
+		// l comes from the input too, so grow the field as its bytes
+		// arrive rather than allocating l bytes up front.
+		var b bytes.Buffer
+		if _, err := io.CopyN(&b, br, int64(l)); err != nil {
+			return nil, ErrCorruptSnapshot
+		}
+		return b.Bytes(), nil
+	}
+
+	m.lazyInit()
+	tree := (*ds.RedBlackTree[Entry[K, V]])(m)
+	// n comes from the input, so don't trust it for the allocation size.
+	entries := make([]Entry[K, V], 0, min(n, 1<<16))
+	for i := uint64(0); i < n; i++ {
+		kb, err := readField()
+		if err != nil {
+			return err
+		}
+		vb, err := readField()
+		if err != nil {
+			return err
+		}
+		key, err := keyCodec.Decode(kb)
+		if err != nil {
+			return fmt.Errorf("kvmap: decoding snapshot key: %w", err)
+		}
+		val, err := valCodec.Decode(vb)
+		if err != nil {
+			return fmt.Errorf("kvmap: decoding snapshot value: %w", err)
+		}
+		e := &orderedMapEntry[K, V]{key: key, value: &val}
+		if len(entries) > 0 && !tree.Ordering(entries[len(entries)-1], e) {
+			return ErrCorruptSnapshot
+		}
+		entries = append(entries, e)
+	}
+	tree.Build(entries)
+	return nil
+}
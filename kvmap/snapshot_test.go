@@ -0,0 +1,58 @@
+package kvmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestOrderedMapSnapshot(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for _, k := range []int{50, 10, 40, 20, 30, -5} {
+		m.Put(k, strconv.Itoa(k))
+	}
+	var buf bytes.Buffer
+	if err := m.WriteSnapshot(&buf, TextCodec[int](), TextCodec[string]()); err != nil {
+		t.Fatalf("WriteSnapshot() == %v, Want nil", err)
+	}
+
+	restored := NewOrderedMap[int, string]()
+	restored.Put(1000, "replaced")
+	if err := restored.ReadSnapshot(bytes.NewReader(buf.Bytes()), TextCodec[int](), TextCodec[string]()); err != nil {
+		t.Fatalf("ReadSnapshot() == %v, Want nil", err)
+	}
+	if got, want := restored.String(), m.String(); got != want {
+		t.Errorf("Want restored map %s, Got %s", want, got)
+	}
+	restored.Put(25, "25")
+	restored.Delete(-5)
+	if got, want := restored.String(), "map[10:10 20:20 25:25 30:30 40:40 50:50]"; got != want {
+		t.Errorf("Want %s after modifying restored map, Got %s", want, got)
+	}
+
+	// A map ordered differently rejects the snapshot.
+	desc := NewOrderedMapFunc[int, string](func(a, b int) int { return b - a })
+	if err := desc.ReadSnapshot(bytes.NewReader(buf.Bytes()), TextCodec[int](), TextCodec[string]()); !errors.Is(err, ErrCorruptSnapshot) {
+		t.Errorf("Want ErrCorruptSnapshot for a differently ordered map, Got %v", err)
+	}
+	if err := desc.ReadSnapshot(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), TextCodec[int](), TextCodec[string]()); !errors.Is(err, ErrCorruptSnapshot) {
+		t.Errorf("Want ErrCorruptSnapshot for a truncated snapshot, Got %v", err)
+	}
+	if desc.Len() != 0 {
+		t.Errorf("Want map unchanged by failed ReadSnapshot, Got Len() == %d", desc.Len())
+	}
+}
+
+func TestOrderedMapSnapshotCorruptLength(t *testing.T) {
+	for _, l := range []uint64{1 << 62, 1 << 63, 1 << 40} {
+		in := binary.AppendUvarint([]byte(snapshotMagic), 1)
+		in = binary.AppendUvarint(in, l)
+		in = append(in, "short"...)
+		m := NewOrderedMap[int, string]()
+		if err := m.ReadSnapshot(bytes.NewReader(in), TextCodec[int](), TextCodec[string]()); !errors.Is(err, ErrCorruptSnapshot) {
+			t.Errorf("Want ErrCorruptSnapshot for a field length of %d, Got %v", l, err)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package kvmap
+
+import (
+	"cmp"
+	"io"
+	"iter"
+	"slices"
+)
+
+// SpillStorage is the backing store of a SpillMap, holding the encoded keys
+// and values of entries which don't fit in memory.
+type SpillStorage interface {
+	// Store sets the value for key, replacing any existing value.
+	Store(key, val []byte) error
+	// Load returns the value for key, and false if key is not stored.
+	Load(key []byte) (val []byte, ok bool, err error)
+	// Remove deletes key, if it is stored.
+	Remove(key []byte) error
+	// Len returns the number of stored keys.
+	Len() int
+	// All returns an iter.Seq2 over the stored keys and values. The storage
+	// is not modified during iteration.
+	All() iter.Seq2[[]byte, []byte]
+}
+
+// SpillMap is a map which keeps its most recently used entries in a bounded
+// LinkedHashMap, and spills the least recently used entries to a
+// SpillStorage, such as a LogSpillStorage on disk, so that it can hold more
+// entries than fit in memory. Get faults spilled entries back into memory,
+// and counts as a use of entries already there, so entries which are read
+// often stay there.
+//
+// The kvmap.Interface methods can't return errors, so the first error from
+// the storage is kept and reported by Err; operations which fail act as if
+// the storage didn't hold the key.
+type SpillMap[K comparable, V any] struct {
+	hot      *LinkedHashMap[K, V]
+	storage  SpillStorage
+	keyCodec Codec[K]
+	valCodec Codec[V]
+
+	err error
+}
+
+// NewSpillMap returns a pointer to a new, empty SpillMap spilling entries to
+// storage, which must be empty. opts configure the in-memory LinkedHashMap,
+// and must include a MaxWeight > 0 to bound it; entries evicted from it are
+// encoded with keyCodec and valCodec and stored. An OnEvict Option is
+// replaced by the SpillMap's own.
+func NewSpillMap[K comparable, V any](storage SpillStorage, keyCodec Codec[K], valCodec Codec[V], opts ...Option) *SpillMap[K, V] {
+	m := &SpillMap[K, V]{storage: storage, keyCodec: keyCodec, valCodec: valCodec}
+	m.hot = NewComparableLinkedHashMap[K, V](append(slices.Clip(opts), OnEvict(m.spill))...)
+	if m.hot.maxWeight == 0 {
+		panic("SpillMap requires a MaxWeight Option > 0")
+	}
+	return m
+}
+
+func (m *SpillMap[K, V]) setErr(err error) {
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+// Err returns the first error returned by m's storage or codecs, if any.
+func (m *SpillMap[K, V]) Err() error {
+	return m.err
+}
+
+// spill moves an entry evicted from memory to the storage.
+func (m *SpillMap[K, V]) spill(key K, val V) {
+	kb, err := m.keyCodec.Encode(key)
+	if err != nil {
+		m.setErr(err)
+		return
+	}
+	vb, err := m.valCodec.Encode(val)
+	if err != nil {
+		m.setErr(err)
+		return
+	}
+	if err := m.storage.Store(kb, vb); err != nil {
+		m.setErr(err)
+	}
+}
+
+// load returns the value for key from the storage.
+func (m *SpillMap[K, V]) load(key K) (kb []byte, val V, ok bool) {
+	kb, err := m.keyCodec.Encode(key)
+	if err != nil {
+		m.setErr(err)
+		return nil, val, false
+	}
+	vb, ok, err := m.storage.Load(kb)
+	if err != nil {
+		m.setErr(err)
+		return kb, val, false
+	}
+	if !ok {
+		return kb, val, false
+	}
+	if val, err = m.valCodec.Decode(vb); err != nil {
+		m.setErr(err)
+		return kb, val, false
+	}
+	return kb, val, true
+}
+
+// remove deletes key from the storage.
+func (m *SpillMap[K, V]) remove(key K) {
+	kb, err := m.keyCodec.Encode(key)
+	if err != nil {
+		m.setErr(err)
+		return
+	}
+	if err := m.storage.Remove(kb); err != nil {
+		m.setErr(err)
+	}
+}
+
+func (m *SpillMap[K, V]) Put(key K, val V) {
+	// A key is either in memory or stored, so only a key which isn't in
+	// memory can have a stored value to remove.
+	if m.storage.Len() > 0 && !m.hot.Has(key) {
+		m.remove(key)
+	}
+	m.hot.Put(key, val)
+	if !m.hot.Has(key) {
+		// The entry is heavier than the in-memory bound.
+		m.spill(key, val)
+	}
+}
+
+// Get returns the value for key, moving it into memory if it was spilled,
+// and making it the most recently used entry.
+func (m *SpillMap[K, V]) Get(key K) (val V, ok bool) {
+	if e := m.hot.lookup(&key); e != nil {
+		m.hot.moveToBack(e)
+		return *e.value, true
+	}
+	kb, val, ok := m.load(key)
+	if !ok {
+		return val, false
+	}
+	if err := m.storage.Remove(kb); err != nil {
+		m.setErr(err)
+		return val, true
+	}
+	m.hot.Put(key, val)
+	if !m.hot.Has(key) {
+		m.spill(key, val)
+	}
+	return val, true
+}
+
+func (m *SpillMap[K, V]) Delete(key K) {
+	if m.hot.Has(key) {
+		m.hot.Delete(key)
+		return
+	}
+	m.remove(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m. Unlike Delete, it reads the value of a spilled entry from the
+// storage before removing it.
+func (m *SpillMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	if val, ok = m.hot.DeleteAndGet(key); ok {
+		return val, true
+	}
+	kb, val, ok := m.load(key)
+	if !ok {
+		return val, false
+	}
+	if err := m.storage.Remove(kb); err != nil {
+		m.setErr(err)
+	}
+	return val, true
+}
+
+// Has returns true if key is in m, without moving it into memory.
+func (m *SpillMap[K, V]) Has(key K) bool {
+	if m.hot.Has(key) {
+		return true
+	}
+	kb, err := m.keyCodec.Encode(key)
+	if err != nil {
+		m.setErr(err)
+		return false
+	}
+	_, ok, err := m.storage.Load(kb)
+	if err != nil {
+		m.setErr(err)
+	}
+	return ok
+}
+
+func (m *SpillMap[K, V]) Len() int {
+	return m.hot.Len() + m.storage.Len()
+}
+
+// InMemory returns the number of entries of m held in memory.
+func (m *SpillMap[K, V]) InMemory() int {
+	return m.hot.Len()
+}
+
+// All returns an iter.Seq2 over the keys and values of m: first those in
+// memory, from least to most recently used, then those spilled, in the storage's order.
+// Spilled entries are not moved into memory. m must not be modified during
+// iteration.
+func (m *SpillMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.hot.All() {
+			if !yield(k, v) {
+				return
+			}
+		}
+		for kb, vb := range m.storage.All() {
+			k, err := m.keyCodec.Decode(kb)
+			if err != nil {
+				m.setErr(err)
+				continue
+			}
+			v, err := m.valCodec.Decode(vb)
+			if err != nil {
+				m.setErr(err)
+				continue
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// LogFile is the file interface used by LogSpillStorage. *os.File implements
+// it.
+type LogFile interface {
+	io.ReaderAt
+	io.WriterAt
+	Truncate(size int64) error
+}
+
+// logRecord locates a value in a LogSpillStorage's file.
+type logRecord struct {
+	offset int64
+	n      int
+}
+
+// minLogCompaction is the number of bytes of removed values a
+// LogSpillStorage accumulates before compacting its file.
+const minLogCompaction = 1 << 20
+
+// LogSpillStorage is a SpillStorage which appends values to a log file and
+// indexes them by key in memory, so that each Load reads a value with a
+// single ReadAt. The space of removed values is reclaimed by compacting the
+// file in place once it makes up most of the file. The file is scratch space:
+// its contents can't be reopened.
+type LogSpillStorage struct {
+	f     LogFile
+	index map[string]logRecord
+	// size is the length of the log, and live the number of its bytes which
+	// hold values in index.
+	size, live int64
+}
+
+// NewLogSpillStorage returns a pointer to a new LogSpillStorage using f,
+// which is truncated, as its log.
+func NewLogSpillStorage(f LogFile) (*LogSpillStorage, error) {
+	if err := f.Truncate(0); err != nil {
+		return nil, err
+	}
+	return &LogSpillStorage{f: f, index: make(map[string]logRecord)}, nil
+}
+
+func (s *LogSpillStorage) Store(key, val []byte) error {
+	if _, err := s.f.WriteAt(val, s.size); err != nil {
+		return err
+	}
+	s.removeIndex(string(key))
+	s.index[string(key)] = logRecord{s.size, len(val)}
+	s.size += int64(len(val))
+	s.live += int64(len(val))
+	return nil
+}
+
+func (s *LogSpillStorage) Load(key []byte) (val []byte, ok bool, err error) {
+	r, ok := s.index[string(key)]
+	if !ok {
+		return nil, false, nil
+	}
+	val = make([]byte, r.n)
+	if _, err := s.f.ReadAt(val, r.offset); err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+func (s *LogSpillStorage) removeIndex(key string) {
+	if r, ok := s.index[key]; ok {
+		delete(s.index, key)
+		s.live -= int64(r.n)
+	}
+}
+
+func (s *LogSpillStorage) Remove(key []byte) error {
+	s.removeIndex(string(key))
+	if dead := s.size - s.live; dead >= minLogCompaction && dead > s.live {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact moves every live value towards the start of the log, in order, so
+// that the removed values at the end can be truncated.
+func (s *LogSpillStorage) compact() error {
+	keys := make([]string, 0, len(s.index))
+	for k := range s.index {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int { return cmp.Compare(s.index[a].offset, s.index[b].offset) })
+
+	var buf []byte
+	var offset int64
+	for _, k := range keys {
+		r := s.index[k]
+		if r.offset != offset {
+			buf = slices.Grow(buf[:0], r.n)[:r.n]
+			if _, err := s.f.ReadAt(buf, r.offset); err != nil {
+				return err
+			}
+			if _, err := s.f.WriteAt(buf, offset); err != nil {
+				return err
+			}
+			s.index[k] = logRecord{offset, r.n}
+		}
+		offset += int64(r.n)
+	}
+	if err := s.f.Truncate(offset); err != nil {
+		return err
+	}
+	s.size = offset
+	return nil
+}
+
+func (s *LogSpillStorage) Len() int {
+	return len(s.index)
+}
+
+// All returns an iter.Seq2 over the stored keys and values, in unspecified
+// order. Iteration stops at the first read error.
+func (s *LogSpillStorage) All() iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		for k := range s.index {
+			v, _, err := s.Load([]byte(k))
+			if err != nil || !yield([]byte(k), v) {
+				return
+			}
+		}
+	}
+}
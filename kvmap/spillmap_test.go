@@ -0,0 +1,136 @@
+package kvmap
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func newTestLogSpillStorage(t *testing.T) *LogSpillStorage {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "spill"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	s, err := NewLogSpillStorage(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSpillMap(t *testing.T) {
+	storage := newTestLogSpillStorage(t)
+	m := NewSpillMap[int, string](storage, TextCodec[int](), TextCodec[string](), MaxWeight(10))
+
+	for i := 0; i < 100; i++ {
+		m.Put(i, strconv.Itoa(i))
+	}
+	if m.Len() != 100 || m.InMemory() != 10 || storage.Len() != 90 {
+		t.Errorf("Want Len() == 100 with 10 in memory, Got %d with %d", m.Len(), m.InMemory())
+	}
+
+	// Reading a spilled entry faults it back in, spilling the oldest.
+	if v, ok := m.Get(3); !ok || v != "3" {
+		t.Errorf("Want Get(3) == (3, true), Got (%s, %t)", v, ok)
+	}
+	if !m.hot.Has(3) || m.hot.Has(90) || !m.Has(90) {
+		t.Errorf("Want 3 in memory and 90 spilled")
+	}
+
+	m.Delete(3)
+	m.Delete(50)
+	m.Put(60, "sixty")
+	if m.Has(3) || m.Has(50) || m.Len() != 98 {
+		t.Errorf("Want 3 and 50 deleted leaving 98 entries, Got Len() == %d", m.Len())
+	}
+	if v, _ := m.Get(60); v != "sixty" {
+		t.Errorf("Want Get(60) == sixty, Got %s", v)
+	}
+
+	seen := map[int]bool{}
+	for k, v := range m.All() {
+		if want := strconv.Itoa(k); k != 60 && v != want {
+			t.Errorf("Want value %s for key %d, Got %s", want, k, v)
+		}
+		seen[k] = true
+	}
+	if len(seen) != 98 {
+		t.Errorf("Want All() to yield 98 entries, Got %d", len(seen))
+	}
+	if err := m.Err(); err != nil {
+		t.Errorf("Want Err() == nil, Got %v", err)
+	}
+}
+
+// countingStorage is a LogSpillStorage which counts Load and Remove calls.
+type countingStorage struct {
+	*LogSpillStorage
+	loads, removes int
+}
+
+func (s *countingStorage) Load(key []byte) ([]byte, bool, error) {
+	s.loads++
+	return s.LogSpillStorage.Load(key)
+}
+
+func (s *countingStorage) Remove(key []byte) error {
+	s.removes++
+	return s.LogSpillStorage.Remove(key)
+}
+
+func TestSpillMapKeepsReadEntriesInMemory(t *testing.T) {
+	storage := &countingStorage{LogSpillStorage: newTestLogSpillStorage(t)}
+	m := NewSpillMap[int, string](storage, TextCodec[int](), TextCodec[string](), MaxWeight(10))
+
+	for i := 0; i < 100; i++ {
+		m.Put(i, strconv.Itoa(i))
+		if v, ok := m.Get(0); !ok || v != "0" {
+			t.Fatalf("Want Get(0) == (0, true), Got (%s, %t)", v, ok)
+		}
+	}
+	if !m.hot.Has(0) || storage.loads != 0 {
+		t.Errorf("Want 0 kept in memory by reads, Got %d loads from storage", storage.loads)
+	}
+
+	// Putting keys which are in memory doesn't touch the storage.
+	removes := storage.removes
+	for _, k := range []int{0, 91, 95, 99} {
+		m.Put(k, "again")
+	}
+	if storage.removes != removes {
+		t.Errorf("Want no storage removals for Puts of keys in memory, Got %d", storage.removes-removes)
+	}
+	if err := m.Err(); err != nil {
+		t.Errorf("Want Err() == nil, Got %v", err)
+	}
+}
+
+func TestLogSpillStorageCompacts(t *testing.T) {
+	s := newTestLogSpillStorage(t)
+	val := make([]byte, 1<<10)
+	for i := 0; i < 4<<10; i++ {
+		val[0] = byte(i)
+		if err := s.Store([]byte(strconv.Itoa(i)), val); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 4<<10; i++ {
+		if i%4 != 0 {
+			if err := s.Remove([]byte(strconv.Itoa(i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if s.size >= 2<<20 {
+		t.Errorf("Want log compacted below 2MiB, Got %d bytes", s.size)
+	}
+	for i := 0; i < 4<<10; i += 4 {
+		v, ok, err := s.Load([]byte(strconv.Itoa(i)))
+		if err != nil || !ok || len(v) != 1<<10 || v[0] != byte(i) {
+			t.Fatalf("Want Load(%d) to return its value, Got ok == %t, err == %v", i, ok, err)
+		}
+	}
+}
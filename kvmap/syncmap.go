@@ -0,0 +1,85 @@
+package kvmap
+
+// SyncMap adapts a ConcurrentWrapper to the method set of sync.Map, so code
+// written against sync.Map can switch to a typed map without rewriting call
+// sites. The zero SyncMap is not usable; create one with AsSyncMap.
+type SyncMap[K, V any] struct {
+	*ConcurrentWrapper[K, V]
+}
+
+// AsSyncMap returns a SyncMap backed by m. Operations on the SyncMap and on m
+// are mutually consistent.
+func AsSyncMap[K, V any](m *ConcurrentWrapper[K, V]) SyncMap[K, V] {
+	return SyncMap[K, V]{m}
+}
+
+// Load returns the value stored under key, if any.
+func (s SyncMap[K, V]) Load(key K) (value V, ok bool) {
+	return s.Get(key)
+}
+
+// Store sets the value for key.
+func (s SyncMap[K, V]) Store(key K, value V) {
+	s.Put(key, value)
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise, it
+// stores and returns value. loaded is true if the value was loaded.
+func (s SyncMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if v, ok := s.Base.Get(key); ok {
+		return v, true
+	}
+	s.Base.Put(key, value)
+	s.wroteLocked()
+	return value, false
+}
+
+// LoadAndDelete deletes the value for key, returning the previous value if
+// any. loaded reports whether key was present.
+func (s SyncMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if value, loaded = s.Base.Get(key); loaded {
+		s.Base.Delete(key)
+		s.wroteLocked()
+	}
+	return
+}
+
+// Swap stores value for key and returns the previous value if any. loaded
+// reports whether key was present.
+func (s SyncMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	previous, loaded = s.Base.Get(key)
+	s.Base.Put(key, value)
+	s.wroteLocked()
+	return
+}
+
+// Range calls f for each key and value in the map, stopping if f returns
+// false. As with sync.Map, Range iterates over a snapshot, so f may modify
+// the map. Range panics if the wrapped Base is not an IterableMap.
+func (s SyncMap[K, V]) Range(f func(key K, value V) bool) {
+	type kv struct {
+		k K
+		v V
+	}
+	s.lock.RLock()
+	it, ok := s.Base.(IterableMap[K, V])
+	if !ok {
+		s.lock.RUnlock()
+		panic("kvmap: SyncMap.Range requires a Base implementing IterableMap")
+	}
+	snapshot := make([]kv, 0, s.Base.Len())
+	ForEach(it, func(k K, v V) { snapshot = append(snapshot, kv{k, v}) })
+	s.lock.RUnlock()
+
+	for _, e := range snapshot {
+		if !f(e.k, e.v) {
+			return
+		}
+	}
+}
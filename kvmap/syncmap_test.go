@@ -0,0 +1,90 @@
+package kvmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncMap(t *testing.T) {
+	s := AsSyncMap(&ConcurrentWrapper[string, int]{Base: NewOrderedMap[string, int]()})
+
+	if v, loaded := s.LoadOrStore("a", 1); loaded || v != 1 {
+		t.Errorf("Want LoadOrStore(a, 1) == (1, false), Got (%d, %t)", v, loaded)
+	}
+	if v, loaded := s.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Errorf("Want LoadOrStore(a, 2) == (1, true), Got (%d, %t)", v, loaded)
+	}
+	s.Store("b", 2)
+	if v, ok := s.Load("b"); !ok || v != 2 {
+		t.Errorf("Want Load(b) == (2, true), Got (%d, %t)", v, ok)
+	}
+	if v, loaded := s.Swap("b", 3); !loaded || v != 2 {
+		t.Errorf("Want Swap(b, 3) == (2, true), Got (%d, %t)", v, loaded)
+	}
+	if v, loaded := s.LoadAndDelete("b"); !loaded || v != 3 {
+		t.Errorf("Want LoadAndDelete(b) == (3, true), Got (%d, %t)", v, loaded)
+	}
+	if _, loaded := s.LoadAndDelete("b"); loaded {
+		t.Errorf("Want LoadAndDelete(b) on missing key == (_, false), Got (_, true)")
+	}
+
+	s.Store("c", 3)
+	var keys []string
+	s.Range(func(k string, _ int) bool {
+		// Modifying the map from f must not deadlock.
+		s.Delete(k)
+		keys = append(keys, k)
+		return true
+	})
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Errorf("Want Range over [a c], Got %v", keys)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Want Len() == 0 after deleting in Range, Got %d", s.Len())
+	}
+}
+
+func TestSyncMapReadCopyUpdate(t *testing.T) {
+	for _, every := range []int{1, 3} {
+		s := AsSyncMap(&ConcurrentWrapper[int, int]{Base: NewOrderedMap[int, int](), Clone: cloneOrderedMap, PublishEvery: every})
+		s.Store(1, 1)
+		s.Store(2, 2)
+		s.Store(3, 3)
+
+		s.LoadOrStore(4, 4)
+		s.Swap(1, 10)
+		s.LoadAndDelete(2)
+		if v, ok := s.Load(4); !ok || v != 4 {
+			t.Errorf("PublishEvery %d: Want Load(4) == (4, true) after LoadOrStore, Got (%d, %t)", every, v, ok)
+		}
+		if v, ok := s.Load(1); !ok || v != 10 {
+			t.Errorf("PublishEvery %d: Want Load(1) == (10, true) after Swap, Got (%d, %t)", every, v, ok)
+		}
+		if _, ok := s.Load(2); ok {
+			t.Errorf("PublishEvery %d: Want Load(2) to miss after LoadAndDelete, Got a value", every)
+		}
+	}
+}
+
+func TestSyncMapLoadOrStoreConcurrent(t *testing.T) {
+	s := AsSyncMap(&ConcurrentWrapper[int, int]{Base: NewComparableLinkedHashMap[int, int]()})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	stored := 0
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, loaded := s.LoadOrStore(0, i); !loaded {
+				mu.Lock()
+				stored++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if stored != 1 {
+		t.Errorf("Want exactly 1 LoadOrStore to store, Got %d", stored)
+	}
+}
@@ -0,0 +1,86 @@
+package kvmap
+
+import (
+	"time"
+
+	"github.org/jccarlson/collections"
+	"github.org/jccarlson/collections/compare"
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// TimeSeriesMap is an OrderedMap keyed by time.Time, with additional methods
+// for querying windows of time and trimming old entries. It is suitable for
+// in-memory telemetry buffers.
+type TimeSeriesMap[V any] struct {
+	*OrderedMap[time.Time, V]
+}
+
+// NewTimeSeriesMap returns a new, empty TimeSeriesMap.
+func NewTimeSeriesMap[V any]() *TimeSeriesMap[V] {
+	return &TimeSeriesMap[V]{NewOrderedMapWithOrderableKeys[time.Time, V]()}
+}
+
+func (m *TimeSeriesMap[V]) tree() *ds.RedBlackTree[Entry[time.Time, V]] {
+	return (*ds.RedBlackTree[Entry[time.Time, V]])(m.OrderedMap)
+}
+
+// Append records val at time t. If there is already a value at t, it is
+// replaced.
+func (m *TimeSeriesMap[V]) Append(t time.Time, val V) {
+	m.Put(t, val)
+}
+
+// Latest returns the entry with the latest time, or ok == false if m is
+// empty.
+func (m *TimeSeriesMap[V]) Latest() (t time.Time, val V, ok bool) {
+	last := m.tree().Last()
+	if last == nil {
+		return
+	}
+	return last.Elem.Key(), last.Elem.Value(), true
+}
+
+// Earliest returns the entry with the earliest time, or ok == false if m is
+// empty.
+func (m *TimeSeriesMap[V]) Earliest() (t time.Time, val V, ok bool) {
+	first := m.tree().First()
+	if first == nil {
+		return
+	}
+	return first.Elem.Key(), first.Elem.Value(), true
+}
+
+// Between returns an Iterator over the entries with times in the half-open
+// interval [from, to), in time order.
+func (m *TimeSeriesMap[V]) Between(from, to time.Time) collections.Iterator[Entry[time.Time, V]] {
+	return &timeSeriesIterator[V]{
+		orderedMapIterator: orderedMapIterator[time.Time, V]{
+			direction: ds.Right,
+			tn:        m.tree().Ceiling(&orderedMapEntry[time.Time, V]{key: from}),
+		},
+		to: to,
+	}
+}
+
+// TrimBefore deletes all entries with times before t, and returns the number
+// of entries deleted.
+func (m *TimeSeriesMap[V]) TrimBefore(t time.Time) int {
+	n := 0
+	for first := m.tree().First(); first != nil && first.Elem.Key().Before(t); first = m.tree().First() {
+		m.Delete(first.Elem.Key())
+		n++
+	}
+	return n
+}
+
+type timeSeriesIterator[V any] struct {
+	orderedMapIterator[time.Time, V]
+	to time.Time
+}
+
+func (i *timeSeriesIterator[V]) Next() (e Entry[time.Time, V], ok bool) {
+	if i.tn == nil || !compare.OrderableOrdering(i.tn.Elem.Key(), i.to) {
+		return
+	}
+	return i.orderedMapIterator.Next()
+}
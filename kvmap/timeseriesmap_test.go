@@ -0,0 +1,52 @@
+package kvmap
+
+import (
+	"testing"
+	"time"
+
+	"github.org/jccarlson/collections"
+)
+
+func TestTimeSeriesMap(t *testing.T) {
+	m := NewTimeSeriesMap[int]()
+	base := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return base.Add(time.Duration(i) * time.Minute) }
+
+	if _, _, ok := m.Latest(); ok {
+		t.Errorf("Want Latest() ok == false for empty map, Got true")
+	}
+	for _, i := range []int{3, 1, 4, 0, 5, 9, 2, 6} {
+		m.Append(at(i), i)
+	}
+
+	if ts, v, ok := m.Latest(); !ok || !ts.Equal(at(9)) || v != 9 {
+		t.Errorf("Want Latest() == (%v, 9, true), Got (%v, %d, %t)", at(9), ts, v, ok)
+	}
+	if ts, v, ok := m.Earliest(); !ok || !ts.Equal(at(0)) || v != 0 {
+		t.Errorf("Want Earliest() == (%v, 0, true), Got (%v, %d, %t)", at(0), ts, v, ok)
+	}
+
+	between := collections.ToSlice(collections.Map(m.Between(at(2), at(6)), func(e Entry[time.Time, int]) int { return e.Value() }))
+	if want := []int{2, 3, 4, 5}; !equalInts(between, want) {
+		t.Errorf("Want Between(2m, 6m) == %v, Got %v", want, between)
+	}
+
+	if n := m.TrimBefore(at(4)); n != 4 {
+		t.Errorf("Want TrimBefore(4m) == 4, Got %d", n)
+	}
+	if ts, _, ok := m.Earliest(); !ok || !ts.Equal(at(4)) || m.Len() != 4 {
+		t.Errorf("Want Earliest() == %v and Len() == 4 after TrimBefore, Got %v and %d", at(4), ts, m.Len())
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+package kvmap
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrTxConflict is returned by Tx.Commit when CheckConflicts is set and a key
+// the transaction used was written by someone else since it was first used.
+var ErrTxConflict = errors.New("kvmap: transaction conflicts with a concurrent write")
+
+// ErrTxDone is returned by Tx.Commit when the transaction has already been
+// committed or rolled back.
+var ErrTxDone = errors.New("kvmap: transaction already committed or rolled back")
+
+// TxMap wraps any kvmap.Interface so that groups of changes can be made
+// atomically through transactions. Begin starts a Tx, which buffers its Puts
+// and Deletes, seeing its own writes, until Commit applies them all at once
+// or Rollback discards them. TxMap is safe for concurrent use, including its
+// own Put and Delete, which act as single-write transactions.
+//
+// By default, transactions which overlap in time don't affect each other's
+// commits, and the last commit to write a key wins. If CheckConflicts is
+// set, TxMap tracks a version for each key, and a Tx only commits if none of
+// the keys it read or wrote has been changed since it first used them;
+// otherwise Commit returns ErrTxConflict and nothing is applied, and the
+// transaction can be retried from the start. Versions are kept only while
+// transactions are in progress, so every Tx must end with Commit or Rollback.
+//
+// Base must only be modified through the TxMap.
+type TxMap[K comparable, V any] struct {
+	Base           Interface[K, V]
+	CheckConflicts bool
+
+	lock sync.RWMutex
+	// versions holds the seq of the last write to each key written while
+	// transactions were active, when CheckConflicts is set. It is cleared
+	// when none are, since no Tx can have used the old versions.
+	versions map[K]uint64
+	seq      uint64
+	// active is the number of transactions in progress.
+	active int
+}
+
+// writeLocked applies a write to m.Base. m.lock must be held for writing.
+func (m *TxMap[K, V]) writeLocked(key K, w txWrite[V]) {
+	if w.deleted {
+		m.Base.Delete(key)
+	} else {
+		m.Base.Put(key, w.val)
+	}
+	if !m.CheckConflicts || m.active == 0 {
+		return
+	}
+	if m.versions == nil {
+		m.versions = make(map[K]uint64)
+	}
+	m.seq++
+	m.versions[key] = m.seq
+}
+
+func (m *TxMap[K, V]) Put(key K, val V) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.writeLocked(key, txWrite[V]{val: val})
+}
+
+func (m *TxMap[K, V]) Get(key K) (val V, ok bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.Base.Get(key)
+}
+
+func (m *TxMap[K, V]) Delete(key K) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.writeLocked(key, txWrite[V]{deleted: true})
+}
+
+func (m *TxMap[K, V]) Has(key K) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.Base.Has(key)
+}
+
+func (m *TxMap[K, V]) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.Base.Len()
+}
+
+// Begin starts a new transaction on m.
+func (m *TxMap[K, V]) Begin() *Tx[K, V] {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.active++
+	return &Tx[K, V]{m: m, writes: make(map[K]txWrite[V])}
+}
+
+// endLocked records the end of a transaction. m.lock must be held for
+// writing.
+func (m *TxMap[K, V]) endLocked() {
+	if m.active--; m.active == 0 {
+		m.versions = nil
+	}
+}
+
+// txWrite is a write buffered by a Tx.
+type txWrite[V any] struct {
+	val     V
+	deleted bool
+}
+
+// Tx is a transaction on a TxMap, which implements kvmap.Interface over the
+// TxMap's contents with the transaction's writes applied. A Tx must only be
+// used by one goroutine at a time, and can't be used after Commit or
+// Rollback.
+type Tx[K comparable, V any] struct {
+	m      *TxMap[K, V]
+	writes map[K]txWrite[V]
+	// used holds the version of each key when the transaction first read or
+	// wrote it, when CheckConflicts is set.
+	used map[K]uint64
+	done bool
+}
+
+func (tx *Tx[K, V]) checkDone() {
+	if tx.done {
+		panic("Tx used after Commit or Rollback")
+	}
+}
+
+// useLocked records the version of key, if it is the first use of key by tx
+// and conflicts are checked. tx.m.lock must be held.
+func (tx *Tx[K, V]) useLocked(key K) {
+	if !tx.m.CheckConflicts {
+		return
+	}
+	if tx.used == nil {
+		tx.used = make(map[K]uint64)
+	}
+	if _, ok := tx.used[key]; !ok {
+		tx.used[key] = tx.m.versions[key]
+	}
+}
+
+func (tx *Tx[K, V]) write(key K, w txWrite[V]) {
+	tx.checkDone()
+	if tx.m.CheckConflicts {
+		tx.m.lock.RLock()
+		tx.useLocked(key)
+		tx.m.lock.RUnlock()
+	}
+	tx.writes[key] = w
+}
+
+func (tx *Tx[K, V]) Put(key K, val V) {
+	tx.write(key, txWrite[V]{val: val})
+}
+
+func (tx *Tx[K, V]) Delete(key K) {
+	tx.write(key, txWrite[V]{deleted: true})
+}
+
+func (tx *Tx[K, V]) Get(key K) (val V, ok bool) {
+	tx.checkDone()
+	if w, ok := tx.writes[key]; ok {
+		return w.val, !w.deleted
+	}
+	tx.m.lock.RLock()
+	defer tx.m.lock.RUnlock()
+	tx.useLocked(key)
+	return tx.m.Base.Get(key)
+}
+
+func (tx *Tx[K, V]) Has(key K) bool {
+	_, ok := tx.Get(key)
+	return ok
+}
+
+func (tx *Tx[K, V]) Len() int {
+	tx.checkDone()
+	tx.m.lock.RLock()
+	defer tx.m.lock.RUnlock()
+	n := tx.m.Base.Len()
+	for k, w := range tx.writes {
+		switch had := tx.m.Base.Has(k); {
+		case w.deleted && had:
+			n--
+		case !w.deleted && !had:
+			n++
+		}
+	}
+	return n
+}
+
+// Commit atomically applies the writes of tx to its TxMap, and ends tx. If
+// CheckConflicts is set and a key tx used has been written since, Commit
+// returns ErrTxConflict and tx's writes are discarded.
+func (tx *Tx[K, V]) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	m := tx.m
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	defer m.endLocked()
+	for k, v := range tx.used {
+		if m.versions[k] != v {
+			return ErrTxConflict
+		}
+	}
+	for k, w := range tx.writes {
+		m.writeLocked(k, w)
+	}
+	return nil
+}
+
+// Rollback discards the writes of tx, and ends it. It does nothing if tx has
+// already ended.
+func (tx *Tx[K, V]) Rollback() {
+	if tx.done {
+		return
+	}
+	tx.done = true
+	tx.writes, tx.used = nil, nil
+	tx.m.lock.Lock()
+	defer tx.m.lock.Unlock()
+	tx.m.endLocked()
+}
@@ -0,0 +1,95 @@
+package kvmap
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestTxMapCommitRollback(t *testing.T) {
+	m := &TxMap[string, int]{Base: NewComparableLinkedHashMap[string, int]()}
+	m.Put("a", 1)
+	m.Put("b", 2)
+
+	tx := m.Begin()
+	tx.Put("c", 3)
+	tx.Delete("a")
+	if v, ok := tx.Get("c"); !ok || v != 3 {
+		t.Errorf("Want tx to read its own Put, Got (%d, %t)", v, ok)
+	}
+	if tx.Has("a") || tx.Len() != 2 {
+		t.Errorf("Want tx to see a deleted and Len() == 2, Got Has(a) == %t, Len() == %d", tx.Has("a"), tx.Len())
+	}
+	if m.Has("c") || !m.Has("a") {
+		t.Errorf("Want uncommitted writes invisible outside tx, Got Has(c) == %t, Has(a) == %t", m.Has("c"), m.Has("a"))
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Want Commit() to succeed, Got %v", err)
+	}
+	if m.Has("a") || !m.Has("c") || m.Len() != 2 {
+		t.Errorf("Want committed writes applied, Got Has(a) == %t, Has(c) == %t, Len() == %d", m.Has("a"), m.Has("c"), m.Len())
+	}
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Want second Commit() to return ErrTxDone, Got %v", err)
+	}
+
+	tx = m.Begin()
+	tx.Put("b", 20)
+	tx.Rollback()
+	if v, _ := m.Get("b"); v != 2 {
+		t.Errorf("Want Rollback() to discard the Put, Got b == %d", v)
+	}
+}
+
+func TestTxMapCheckConflicts(t *testing.T) {
+	m := &TxMap[string, int]{Base: NewComparableLinkedHashMap[string, int](), CheckConflicts: true}
+	m.Put("x", 1)
+
+	tx1, tx2 := m.Begin(), m.Begin()
+	v1, _ := tx1.Get("x")
+	v2, _ := tx2.Get("x")
+	tx1.Put("x", v1+1)
+	tx2.Put("x", v2+1)
+	if err := tx1.Commit(); err != nil {
+		t.Fatalf("Want first Commit() to succeed, Got %v", err)
+	}
+	if err := tx2.Commit(); !errors.Is(err, ErrTxConflict) {
+		t.Errorf("Want second Commit() to return ErrTxConflict, Got %v", err)
+	}
+	if v, _ := m.Get("x"); v != 2 {
+		t.Errorf("Want x == 2, Got %d", v)
+	}
+
+	// A key read while absent conflicts with a concurrent Put of it.
+	tx := m.Begin()
+	tx.Has("y")
+	m.Put("y", 1)
+	if err := tx.Commit(); !errors.Is(err, ErrTxConflict) {
+		t.Errorf("Want ErrTxConflict after a concurrent Put, Got %v", err)
+	}
+}
+
+func TestTxMapConcurrentIncrements(t *testing.T) {
+	m := &TxMap[string, int]{Base: NewComparableLinkedHashMap[string, int](), CheckConflicts: true}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for {
+					tx := m.Begin()
+					v, _ := tx.Get("n")
+					tx.Put("n", v+1)
+					if tx.Commit() == nil {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Get("n"); v != 800 {
+		t.Errorf("Want 800 increments, Got %d", v)
+	}
+}
@@ -0,0 +1,297 @@
+package kvmap
+
+import (
+	"sync"
+
+	"github.org/jccarlson/collections"
+)
+
+// mapVersion is a value of a key in a VersionedMap as of a version, linked to
+// the key's previous version.
+type mapVersion[V any] struct {
+	version uint64
+	value   V
+	deleted bool
+	prev    *mapVersion[V]
+}
+
+// visibleAt returns the newest version in the chain starting at v which is
+// not newer than version, or nil if there is none.
+func (v *mapVersion[V]) visibleAt(version uint64) *mapVersion[V] {
+	for v != nil && v.version > version {
+		v = v.prev
+	}
+	return v
+}
+
+// VersionedMap is a thread-safe map which supports cheap, consistent
+// point-in-time snapshots using multi-version concurrency control. Each write
+// creates a new version of the written key, and a Snapshot sees the newest
+// version of each key no newer than the snapshot. Taking a snapshot is O(1),
+// and writes can continue concurrently with reads and iteration of any
+// snapshot.
+//
+// Old versions are retained until no unreleased Snapshot can see them, and a
+// deleted key retains a small tombstone until then. Tombstones are reclaimed
+// once they outnumber the live keys while there are no unreleased snapshots.
+type VersionedMap[K comparable, V any] struct {
+	mu sync.RWMutex
+
+	version uint64
+	chains  map[K]*mapVersion[V]
+	size    int
+
+	// keys is an append-only log of every key in chains, in order of first
+	// write, so that snapshots can iterate over a stable prefix. It is only
+	// compacted while there are no snapshots.
+	keys []K
+	// tombstones is the number of keys whose newest version is a deletion.
+	tombstones int
+
+	// snapshots counts the unreleased snapshots at each version.
+	snapshots map[uint64]int
+}
+
+// NewVersionedMap returns a pointer to a new, empty VersionedMap.
+func NewVersionedMap[K comparable, V any]() *VersionedMap[K, V] {
+	return &VersionedMap[K, V]{
+		chains:    make(map[K]*mapVersion[V]),
+		snapshots: make(map[uint64]int),
+	}
+}
+
+// oldestSnapshot returns the version of the oldest unreleased snapshot, or
+// the current version if there are none. m.mu must be held.
+func (m *VersionedMap[K, V]) oldestSnapshot() uint64 {
+	oldest := m.version
+	for v := range m.snapshots {
+		if v < oldest {
+			oldest = v
+		}
+	}
+	return oldest
+}
+
+// snapshotSince returns true if there is an unreleased snapshot at version or
+// later. m.mu must be held.
+func (m *VersionedMap[K, V]) snapshotSince(version uint64) bool {
+	for v := range m.snapshots {
+		if v >= version {
+			return true
+		}
+	}
+	return false
+}
+
+// write adds a new version for key. m.mu must be held for writing.
+func (m *VersionedMap[K, V]) write(key K, val V, deleted bool) {
+	head, ok := m.chains[key]
+	wasLive := ok && !head.deleted
+	if deleted && !wasLive {
+		return
+	}
+	if !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.version++
+	old := head
+	head = &mapVersion[V]{version: m.version, value: val, deleted: deleted, prev: old}
+	if old != nil && !m.snapshotSince(old.version) {
+		// No snapshot sees the superseded version, so unlink it.
+		head.prev = old.prev
+	}
+	m.chains[key] = head
+	switch {
+	case deleted:
+		m.size--
+		m.tombstones++
+	case !wasLive:
+		m.size++
+		if ok {
+			m.tombstones--
+		}
+	}
+
+	// Drop versions which no snapshot can see.
+	if keep := head.visibleAt(m.oldestSnapshot()); keep != nil {
+		keep.prev = nil
+	}
+	m.maybeCompact()
+}
+
+// maybeCompact drops the tombstones of deleted keys and the old versions of
+// live keys, if there are no snapshots and tombstones outnumber live keys, so
+// that the cost is amortized over the deletions. m.mu must be held for
+// writing.
+func (m *VersionedMap[K, V]) maybeCompact() {
+	if len(m.snapshots) > 0 || m.tombstones <= m.size {
+		return
+	}
+	live := m.keys[:0]
+	for _, key := range m.keys {
+		head := m.chains[key]
+		if head.deleted {
+			delete(m.chains, key)
+			continue
+		}
+		head.prev = nil
+		live = append(live, key)
+	}
+	clear(m.keys[len(live):])
+	m.keys, m.tombstones = live, 0
+}
+
+func (m *VersionedMap[K, V]) Put(key K, val V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.write(key, val, false)
+}
+
+func (m *VersionedMap[K, V]) Delete(key K) {
+	m.DeleteAndGet(key)
+}
+
+// DeleteAndGet removes key from m and returns its value, or false if key was
+// not in m, as one atomic operation.
+func (m *VersionedMap[K, V]) DeleteAndGet(key K) (val V, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v := m.chains[key]; v != nil && !v.deleted {
+		val, ok = v.value, true
+	}
+	var zero V
+	m.write(key, zero, true)
+	return val, ok
+}
+
+func (m *VersionedMap[K, V]) Get(key K) (val V, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if v := m.chains[key]; v != nil && !v.deleted {
+		return v.value, true
+	}
+	return
+}
+
+func (m *VersionedMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *VersionedMap[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.size
+}
+
+// Version returns the current version of m, which is incremented by every
+// write which changes m.
+func (m *VersionedMap[K, V]) Version() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.version
+}
+
+// Snapshot returns an immutable view of m as of its current version. The
+// snapshot should be released with Release when it is no longer used, so that
+// the versions it retains can be reclaimed.
+func (m *VersionedMap[K, V]) Snapshot() *MapSnapshot[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots[m.version]++
+	return &MapSnapshot[K, V]{m: m, version: m.version, size: m.size, nkeys: len(m.keys)}
+}
+
+// MapSnapshot is an immutable, point-in-time view of a VersionedMap. It is
+// safe for concurrent use, and its contents never change, regardless of
+// writes to the VersionedMap.
+type MapSnapshot[K comparable, V any] struct {
+	m        *VersionedMap[K, V]
+	version  uint64
+	size     int
+	nkeys    int
+	released bool
+}
+
+// Version returns the version of the VersionedMap the snapshot views.
+func (s *MapSnapshot[K, V]) Version() uint64 {
+	return s.version
+}
+
+func (s *MapSnapshot[K, V]) Get(key K) (val V, ok bool) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+	if v := s.m.chains[key].visibleAt(s.version); v != nil && !v.deleted {
+		return v.value, true
+	}
+	return
+}
+
+func (s *MapSnapshot[K, V]) Has(key K) bool {
+	_, ok := s.Get(key)
+	return ok
+}
+
+func (s *MapSnapshot[K, V]) Len() int {
+	return s.size
+}
+
+// Iterator returns an Iterator over the snapshot's entries, in order of the
+// keys' first insertion into the VersionedMap, except that a key which was
+// deleted and Put again may be ordered by when it was Put again. Calling
+// SetValue on the returned entries panics.
+func (s *MapSnapshot[K, V]) Iterator() collections.Iterator[Entry[K, V]] {
+	return &mapSnapshotIterator[K, V]{s: s}
+}
+
+// Release releases the versions retained by the snapshot. The snapshot must
+// not be used after it is released.
+func (s *MapSnapshot[K, V]) Release() {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	if s.released {
+		return
+	}
+	s.released = true
+	if s.m.snapshots[s.version]--; s.m.snapshots[s.version] == 0 {
+		delete(s.m.snapshots, s.version)
+	}
+	s.m.maybeCompact()
+}
+
+type mapSnapshotIterator[K comparable, V any] struct {
+	s *MapSnapshot[K, V]
+	i int
+}
+
+func (i *mapSnapshotIterator[K, V]) Next() (e Entry[K, V], ok bool) {
+	m := i.s.m
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for ; i.i < i.s.nkeys; i.i++ {
+		key := m.keys[i.i]
+		if v := m.chains[key].visibleAt(i.s.version); v != nil && !v.deleted {
+			i.i++
+			return snapshotEntry[K, V]{key, v.value}, true
+		}
+	}
+	return
+}
+
+type snapshotEntry[K, V any] struct {
+	key   K
+	value V
+}
+
+func (e snapshotEntry[K, V]) Key() K {
+	return e.key
+}
+
+func (e snapshotEntry[K, V]) Value() V {
+	return e.value
+}
+
+func (e snapshotEntry[K, V]) SetValue(V) {
+	panic("cannot set the value of a MapSnapshot entry")
+}
@@ -0,0 +1,168 @@
+package kvmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestVersionedMapSnapshots(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Put("a", 1)
+	m.Put("b", 2)
+	s1 := m.Snapshot()
+
+	m.Put("a", 10)
+	m.Delete("b")
+	m.Put("c", 3)
+	s2 := m.Snapshot()
+	m.Put("b", 20)
+
+	tcs := []struct {
+		name string
+		m    interface {
+			Get(string) (int, bool)
+			Len() int
+		}
+		want map[string]int
+	}{
+		{"s1", s1, map[string]int{"a": 1, "b": 2}},
+		{"s2", s2, map[string]int{"a": 10, "c": 3}},
+		{"current", m, map[string]int{"a": 10, "b": 20, "c": 3}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range []string{"a", "b", "c"} {
+				want, wantOK := tc.want[k]
+				if v, ok := tc.m.Get(k); ok != wantOK || v != want {
+					t.Errorf("Want Get(%q) == (%d, %t), Got (%d, %t)", k, want, wantOK, v, ok)
+				}
+			}
+			if l := tc.m.Len(); l != len(tc.want) {
+				t.Errorf("Want Len() == %d, Got %d", len(tc.want), l)
+			}
+		})
+	}
+
+	got := map[string]int{}
+	it := s2.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		got[e.Key()] = e.Value()
+	}
+	if len(got) != 2 || got["a"] != 10 || got["c"] != 3 {
+		t.Errorf("Want s2 to iterate over map[a:10 c:3], Got %v", got)
+	}
+
+	s1.Release()
+	s2.Release()
+	m.Put("a", 100)
+	if v := m.chains["a"]; v.prev != nil {
+		t.Errorf("Want old versions of a reclaimed after all snapshots released, Got version %d", v.prev.version)
+	}
+}
+
+func TestVersionedMapDropsVersionsNoSnapshotSees(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Put("a", 0)
+	s := m.Snapshot()
+	defer s.Release()
+	for i := 1; i <= 1000; i++ {
+		m.Put("a", i)
+	}
+	n := 0
+	for v := m.chains["a"]; v != nil; v = v.prev {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Want only the newest version and the snapshot's retained, Got %d versions", n)
+	}
+	if v, _ := s.Get("a"); v != 0 {
+		t.Errorf("Want snapshot Get(a) == 0, Got %d", v)
+	}
+	if v, _ := m.Get("a"); v != 1000 {
+		t.Errorf("Want Get(a) == 1000, Got %d", v)
+	}
+}
+
+func TestVersionedMapCompactsTombstones(t *testing.T) {
+	m := NewVersionedMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	s := m.Snapshot()
+	m.Put(999, -1)
+	for i := 0; i < 600; i++ {
+		m.Delete(i)
+	}
+	if len(m.keys) != 1000 {
+		t.Errorf("Want keys retained while a snapshot is unreleased, Got %d keys", len(m.keys))
+	}
+	if v, ok := s.Get(0); !ok || v != 0 {
+		t.Errorf("Want snapshot Get(0) == (0, true), Got (%d, %t)", v, ok)
+	}
+
+	s.Release()
+	if len(m.keys) != 400 || len(m.chains) != 400 || m.chains[999].prev != nil {
+		t.Errorf("Want 400 keys with no old versions after the snapshot is released, Got %d keys and %d chains", len(m.keys), len(m.chains))
+	}
+	for i := 0; i < 1000; i++ {
+		m.Delete(i)
+	}
+	if len(m.keys) != 0 || len(m.chains) != 0 || m.Len() != 0 {
+		t.Errorf("Want no tombstones left after deleting every key, Got %d keys and %d chains", len(m.keys), len(m.chains))
+	}
+
+	// Deleted keys can be Put again, and snapshots iterate over them.
+	m.Put(5, 5)
+	m.Put(3, 3)
+	s = m.Snapshot()
+	defer s.Release()
+	var got []int
+	it := s.Iterator()
+	for e, ok := it.Next(); ok; e, ok = it.Next() {
+		got = append(got, e.Key())
+	}
+	if len(got) != 2 || got[0] != 5 || got[1] != 3 {
+		t.Errorf("Want snapshot keys [5 3], Got %v", got)
+	}
+}
+
+func TestVersionedMapConcurrentSnapshotIteration(t *testing.T) {
+	m := NewVersionedMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m.Put(i, 0)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for round := 1; round <= 50; round++ {
+			for i := 0; i < 100; i++ {
+				m.Put(i, round)
+			}
+		}
+	}()
+
+	for n := 0; n < 50; n++ {
+		s := m.Snapshot()
+		// Every write between two snapshots' versions belongs to at most two
+		// rounds, so a consistent view has values from adjacent rounds only.
+		min, max, count := -1, -1, 0
+		it := s.Iterator()
+		for e, ok := it.Next(); ok; e, ok = it.Next() {
+			v := e.Value()
+			if min == -1 || v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+			count++
+		}
+		if count != 100 || max-min > 1 {
+			t.Errorf("Want 100 entries from adjacent rounds, Got %d entries from rounds %d to %d", count, min, max)
+		}
+		s.Release()
+	}
+	wg.Wait()
+}
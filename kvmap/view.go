@@ -0,0 +1,150 @@
+package kvmap
+
+import (
+	"iter"
+	"slices"
+
+	"github.org/jccarlson/collections"
+	"github.org/jccarlson/collections/compare"
+)
+
+// MapView is a lazily evaluated query over the entries of an IterableMap,
+// built by chaining Where, OrderBy and Limit onto View. Each method returns a
+// new MapView, leaving its receiver unchanged, and nothing is read from the
+// map until All is called.
+type MapView[K, V any] struct {
+	m     IterableMap[K, V]
+	where []func(K, V) bool
+	order compare.Ordering[Entry[K, V]]
+	// limit is the maximum number of entries yielded, or -1 if unlimited.
+	limit int
+}
+
+// View returns a MapView of all the entries of m, in m's iteration order.
+func View[K, V any](m IterableMap[K, V]) MapView[K, V] {
+	return MapView[K, V]{m: m, limit: -1}
+}
+
+// Where returns a view of the entries of v for which pred returns true.
+func (v MapView[K, V]) Where(pred func(key K, val V) bool) MapView[K, V] {
+	v.where = append(slices.Clip(v.where), pred)
+	return v
+}
+
+// OrderBy returns a view of the entries of v sorted by ord. Entries which
+// are equal under ord keep the map's iteration order.
+func (v MapView[K, V]) OrderBy(ord compare.Ordering[Entry[K, V]]) MapView[K, V] {
+	v.order = ord
+	return v
+}
+
+// Limit returns a view of at most the first n entries of v.
+func (v MapView[K, V]) Limit(n int) MapView[K, V] {
+	if n < 0 {
+		panic("MapView limit must be >= 0")
+	}
+	v.limit = n
+	return v
+}
+
+// viewEntry is a copy of a map entry held while sorting a MapView, with the
+// position at which the map yielded it.
+type viewEntry[K, V any] struct {
+	key   K
+	value V
+	pos   int
+}
+
+func (e *viewEntry[K, V]) Key() K {
+	return e.key
+}
+
+func (e *viewEntry[K, V]) Value() V {
+	return e.value
+}
+
+func (e *viewEntry[K, V]) SetValue(val V) {
+	e.value = val
+}
+
+// matches returns an iter.Seq2 over the entries of the map satisfying every
+// Where predicate, in the map's iteration order.
+func (v MapView[K, V]) matches() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+	entries:
+		for e := range EntrySeq(v.m) {
+			k, val := e.Key(), e.Value()
+			for _, pred := range v.where {
+				if !pred(k, val) {
+					continue entries
+				}
+			}
+			if !yield(k, val) {
+				return
+			}
+		}
+	}
+}
+
+// sorted returns the entries of the view in order. With a Limit, only that
+// many entries are kept while reading the map.
+func (v MapView[K, V]) sorted() []*viewEntry[K, V] {
+	// Break ties by position, so that the order is stable.
+	ord := func(a, b *viewEntry[K, V]) bool {
+		if v.order(a, b) {
+			return true
+		}
+		return !v.order(b, a) && a.pos < b.pos
+	}
+
+	var es []*viewEntry[K, V]
+	if v.limit < 0 {
+		for k, val := range v.matches() {
+			es = append(es, &viewEntry[K, V]{k, val, len(es)})
+		}
+		slices.SortFunc(es, compare.Ordering[*viewEntry[K, V]](ord).Compare)
+		return es
+	}
+	if v.limit == 0 {
+		return nil
+	}
+	// Keep the first limit entries in a bounded queue, in which entries
+	// earlier in the order have higher priority.
+	q := collections.NewBoundedPriorityQueue(v.limit, compare.Reverse(compare.Ordering[*viewEntry[K, V]](ord)))
+	pos := 0
+	for k, val := range v.matches() {
+		q.Offer(&viewEntry[K, V]{k, val, pos})
+		pos++
+	}
+	return q.Sorted()
+}
+
+// All returns an iter.Seq2 over the keys and values of the view. Sorted
+// views read every matching entry of the map before yielding the first;
+// other views read the map only as far as iteration goes. The map must not
+// be modified during iteration.
+func (v MapView[K, V]) All() iter.Seq2[K, V] {
+	if v.order != nil {
+		return func(yield func(K, V) bool) {
+			for _, e := range v.sorted() {
+				if !yield(e.key, e.value) {
+					return
+				}
+			}
+		}
+	}
+	return func(yield func(K, V) bool) {
+		if v.limit == 0 {
+			return
+		}
+		n := 0
+		for k, val := range v.matches() {
+			if !yield(k, val) {
+				return
+			}
+			if n++; n == v.limit {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package kvmap
+
+import (
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	m := NewComparableLinkedHashMap[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		m.Put(k, i%4)
+	}
+	byValue := func(a, b Entry[string, int]) bool { return a.Value() < b.Value() }
+	notB := func(k string, _ int) bool { return k != "b" }
+
+	tcs := []struct {
+		name string
+		view MapView[string, int]
+		want string
+	}{
+		{"All", View[string, int](m), "abcdefgh"},
+		{"Where", View[string, int](m).Where(notB).Where(func(_ string, v int) bool { return v > 0 }), "cdfgh"},
+		{"Limit", View[string, int](m).Where(notB).Limit(3), "acd"},
+		{"LimitZero", View[string, int](m).Limit(0), ""},
+		// Ties keep insertion order.
+		{"OrderBy", View[string, int](m).OrderBy(byValue), "aebfcgdh"},
+		{"OrderByLimit", View[string, int](m).Where(notB).OrderBy(byValue).Limit(4), "aefc"},
+		{"OrderByLimitBeyondLen", View[string, int](m).OrderBy(byValue).Limit(100), "aebfcgdh"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ""
+			for k := range tc.view.All() {
+				got += k
+			}
+			if got != tc.want {
+				t.Errorf("Want keys %q, Got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestViewIsLazy(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	calls := 0
+	v := View[int, int](m).Where(func(k, _ int) bool { calls++; return k%2 == 0 })
+	if calls != 0 {
+		t.Fatalf("Want no predicate calls before All, Got %d", calls)
+	}
+	for k := range v.All() {
+		if k == 4 {
+			break
+		}
+	}
+	if calls != 5 {
+		t.Errorf("Want iteration to stop after 5 predicate calls, Got %d", calls)
+	}
+}
+
+func TestViewClosesIterator(t *testing.T) {
+	var open int
+	m := closeCountingMap[int, int]{NewOrderedMap[int, int](), &open}
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+	for range View[int, int](m).Limit(2).All() {
+	}
+	for range View[int, int](m).All() {
+		break
+	}
+	if open != 0 {
+		t.Errorf("Want views to close the map's Iterators, Got %d open", open)
+	}
+}
@@ -0,0 +1,146 @@
+package kvmap
+
+import (
+	"context"
+	"iter"
+)
+
+// ChangeKind is the kind of mutation described by a ChangeEvent.
+type ChangeKind int
+
+const (
+	// ChangePut is a Put of a new or existing key.
+	ChangePut ChangeKind = iota
+	// ChangeDelete is a Delete of an existing key.
+	ChangeDelete
+	// ChangeEvict is an eviction by the Base map.
+	ChangeEvict
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangePut:
+		return "Put"
+	case ChangeDelete:
+		return "Delete"
+	case ChangeEvict:
+		return "Evict"
+	}
+	return "ChangeKind(?)"
+}
+
+// ChangeEvent describes a mutation of an ObservableWrapper.
+type ChangeEvent[K, V any] struct {
+	Kind ChangeKind
+	Key  K
+	// Old is the value of Key before the change, if HadOld is true. HadOld
+	// is always true for ChangeDelete and ChangeEvict events.
+	Old    V
+	HadOld bool
+	// New is the value of Key after a ChangePut.
+	New V
+}
+
+// WatchOverflow is the policy a watcher applies when a new event arrives and
+// its buffer is full.
+type WatchOverflow int
+
+const (
+	// WatchBlock blocks the mutating goroutine until the watcher consumes an
+	// event or its context is done. No events are lost, but a slow watcher
+	// slows writers.
+	WatchBlock WatchOverflow = iota
+	// WatchDropOldest discards the oldest buffered event to make room.
+	WatchDropOldest
+	// WatchDropNewest discards the new event.
+	WatchDropNewest
+)
+
+// Watch returns a single-use iter.Seq delivering a ChangeEvent for each
+// mutation of m from the time Watch is called until ctx is done, stop is
+// called or the consuming loop exits. Up to buffer events are buffered
+// between m and the consumer; overflow determines what happens when the
+// buffer is full. The drop policies need a buffer of at least 1 to hold
+// events for the consumer, so Watch panics if they are given none.
+//
+// The watcher is registered until then even if the sequence is never ranged
+// over, so callers must call stop, typically deferred, once they no longer
+// need the sequence; with WatchBlock, a sequence which is not consumed
+// blocks every writer of m once its buffer is full.
+//
+// Events are delivered in the order the listeners were notified, which is
+// the order of the mutations as long as they are not made concurrently. The
+// consumer must not mutate m while ranging over the sequence if overflow is
+// WatchBlock, as that may deadlock.
+func (m *ObservableWrapper[K, V]) Watch(ctx context.Context, buffer int, overflow WatchOverflow) (events iter.Seq[ChangeEvent[K, V]], stop func()) {
+	if buffer < 0 {
+		panic("Watch buffer must be >= 0")
+	}
+	if buffer == 0 && overflow != WatchBlock {
+		panic("Watch buffer must be >= 1 unless overflow is WatchBlock")
+	}
+	ctx, cancelCtx := context.WithCancel(ctx)
+	ch := make(chan ChangeEvent[K, V], buffer)
+
+	send := func(ev ChangeEvent[K, V]) {
+		switch overflow {
+		case WatchBlock:
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+			}
+		case WatchDropOldest:
+			for {
+				select {
+				case ch <- ev:
+					return
+				case <-ctx.Done():
+					return
+				default:
+				}
+				select {
+				case <-ch:
+				default:
+				}
+			}
+		case WatchDropNewest:
+			select {
+			case ch <- ev:
+			default:
+			}
+		}
+	}
+
+	cancelListener := m.Listen(Listener[K, V]{
+		OnPut: func(key K, old V, hadOld bool, new V) {
+			send(ChangeEvent[K, V]{Kind: ChangePut, Key: key, Old: old, HadOld: hadOld, New: new})
+		},
+		OnDelete: func(key K, old V) {
+			send(ChangeEvent[K, V]{Kind: ChangeDelete, Key: key, Old: old, HadOld: true})
+		},
+		OnEvict: func(key K, val V) {
+			send(ChangeEvent[K, V]{Kind: ChangeEvict, Key: key, Old: val, HadOld: true})
+		},
+	})
+	// Unregister when ctx is done, even if the sequence is never consumed.
+	context.AfterFunc(ctx, cancelListener)
+	stop = func() {
+		cancelCtx()
+		// Unregister now, rather than when the AfterFunc gets to run.
+		cancelListener()
+	}
+
+	return func(yield func(ChangeEvent[K, V]) bool) {
+		defer stop()
+		for {
+			select {
+			case ev := <-ch:
+				if !yield(ev) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, stop
+}
@@ -0,0 +1,96 @@
+package kvmap
+
+import (
+	"context"
+	"testing"
+)
+
+func TestObservableWrapperWatch(t *testing.T) {
+	m := &ObservableWrapper[string, int]{Base: NewMapWrapper[string, int]()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, stop := m.Watch(ctx, 0, WatchBlock)
+	defer stop()
+	done := make(chan []ChangeEvent[string, int])
+	go func() {
+		var got []ChangeEvent[string, int]
+		for ev := range events {
+			got = append(got, ev)
+			if len(got) == 3 {
+				break
+			}
+		}
+		done <- got
+	}()
+
+	m.Put("a", 1)
+	m.Put("a", 2)
+	m.Delete("a")
+	got := <-done
+
+	want := []ChangeEvent[string, int]{
+		{Kind: ChangePut, Key: "a", New: 1},
+		{Kind: ChangePut, Key: "a", Old: 1, HadOld: true, New: 2},
+		{Kind: ChangeDelete, Key: "a", Old: 2, HadOld: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Want %d events, Got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Want event %d == %+v, Got %+v", i, want[i], got[i])
+		}
+	}
+
+	// The watcher stopped consuming, so further mutations must not block.
+	m.Put("b", 1)
+}
+
+func TestObservableWrapperWatchDropOldest(t *testing.T) {
+	m := &ObservableWrapper[int, int]{Base: NewMapWrapper[int, int]()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, stop := m.Watch(ctx, 2, WatchDropOldest)
+	defer stop()
+	for i := 0; i < 5; i++ {
+		m.Put(i, i)
+	}
+	var keys []int
+	for ev := range events {
+		keys = append(keys, ev.Key)
+		if len(keys) == 2 {
+			cancel()
+		}
+	}
+	if len(keys) != 2 || keys[0] != 3 || keys[1] != 4 {
+		t.Errorf("Want only the newest events for keys [3 4], Got %v", keys)
+	}
+}
+
+func TestObservableWrapperWatchStopWithoutConsuming(t *testing.T) {
+	m := &ObservableWrapper[int, int]{Base: NewMapWrapper[int, int]()}
+	_, stop := m.Watch(context.Background(), 0, WatchBlock)
+	if len(m.listeners) != 1 {
+		t.Fatalf("Want a watcher registered by Watch, Got %d listeners", len(m.listeners))
+	}
+	stop()
+	if len(m.listeners) != 0 {
+		t.Errorf("Want stop() to unregister the watcher, Got %d listeners", len(m.listeners))
+	}
+}
+
+func TestObservableWrapperWatchDropWithoutBufferPanics(t *testing.T) {
+	m := &ObservableWrapper[int, int]{Base: NewMapWrapper[int, int]()}
+	for _, overflow := range []WatchOverflow{WatchDropOldest, WatchDropNewest} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Want Watch with buffer 0 and overflow %d to panic, Got no panic", overflow)
+				}
+			}()
+			m.Watch(context.Background(), 0, overflow)
+		}()
+	}
+}
@@ -0,0 +1,272 @@
+package collections
+
+import "iter"
+
+// listNode is an element of a LinkedList, or the list's sentinel.
+type listNode[E any] struct {
+	value      E
+	next, prev *listNode[E]
+	// removed is set when the node is removed from its list, so that cursors
+	// still at it can detect it.
+	removed bool
+}
+
+// LinkedList is a doubly-linked list, which is edited through Cursors. A
+// Cursor can move through the list and insert or remove elements at its
+// position in O(1) time, and can split the list or splice another into it in
+// O(1) time. Several cursors may be used on a list at once: a cursor whose
+// element is removed by another, or whose list is split or spliced by
+// another, panics when used rather than corrupting the list. The zero value
+// is an empty LinkedList ready to use.
+type LinkedList[E any] struct {
+	// root is the sentinel of the circular list of nodes: root.next is the
+	// first node and root.prev the last.
+	root *listNode[E]
+
+	// size is the number of elements, if sizeKnown is true. It is not known
+	// after a split, until it is counted.
+	size      int
+	sizeKnown bool
+
+	// gen is incremented when elements move to another list, to invalidate
+	// the list's cursors.
+	gen int
+}
+
+// init lazily initializes the zero value of l.
+func (l *LinkedList[E]) init() {
+	if l.root == nil {
+		l.root = &listNode[E]{}
+		l.root.next, l.root.prev = l.root, l.root
+		l.size, l.sizeKnown = 0, true
+	}
+}
+
+// insertBefore inserts a node holding e before at.
+func (l *LinkedList[E]) insertBefore(e E, at *listNode[E]) {
+	n := &listNode[E]{value: e, next: at, prev: at.prev}
+	at.prev.next = n
+	at.prev = n
+	l.size++
+}
+
+// remove unlinks n, which must be an element of l, and returns its value.
+func (l *LinkedList[E]) remove(n *listNode[E]) E {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.next, n.prev, n.removed = nil, nil, true
+	l.size--
+	return n.value
+}
+
+// PushFront inserts e at the front of l.
+func (l *LinkedList[E]) PushFront(e E) {
+	l.init()
+	l.insertBefore(e, l.root.next)
+}
+
+// PushBack inserts e at the back of l.
+func (l *LinkedList[E]) PushBack(e E) {
+	l.init()
+	l.insertBefore(e, l.root)
+}
+
+// PopFront removes and returns the element at the front of l. ok is false if
+// l is empty.
+func (l *LinkedList[E]) PopFront() (e E, ok bool) {
+	l.init()
+	if l.root.next == l.root {
+		return
+	}
+	return l.remove(l.root.next), true
+}
+
+// PopBack removes and returns the element at the back of l. ok is false if l
+// is empty.
+func (l *LinkedList[E]) PopBack() (e E, ok bool) {
+	l.init()
+	if l.root.prev == l.root {
+		return
+	}
+	return l.remove(l.root.prev), true
+}
+
+// Len returns the number of elements in l. It takes O(1) time, except for the
+// first call after l is split, which counts the elements.
+func (l *LinkedList[E]) Len() int {
+	l.init()
+	if !l.sizeKnown {
+		l.size = 0
+		for n := l.root.next; n != l.root; n = n.next {
+			l.size++
+		}
+		l.sizeKnown = true
+	}
+	return l.size
+}
+
+// All returns an iter.Seq over the elements of l from front to back. l must
+// not be modified during iteration.
+func (l *LinkedList[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		l.init()
+		for n := l.root.next; n != l.root; n = n.next {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iter.Seq over the elements of l from back to front. l
+// must not be modified during iteration.
+func (l *LinkedList[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		l.init()
+		for n := l.root.prev; n != l.root; n = n.prev {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Front returns a cursor at the first element of l, or at the end of l if it
+// is empty.
+func (l *LinkedList[E]) Front() *Cursor[E] {
+	l.init()
+	return &Cursor[E]{l: l, n: l.root.next, gen: l.gen}
+}
+
+// Back returns a cursor at the last element of l, or at the end of l if it is
+// empty.
+func (l *LinkedList[E]) Back() *Cursor[E] {
+	l.init()
+	return &Cursor[E]{l: l, n: l.root.prev, gen: l.gen}
+}
+
+// Cursor is a position in a LinkedList: either at one of its elements, or at
+// the end of the list, which lies both after the last element and before the
+// first.
+type Cursor[E any] struct {
+	l   *LinkedList[E]
+	n   *listNode[E]
+	gen int
+}
+
+// check panics if c can no longer be used.
+func (c *Cursor[E]) check() {
+	if c.gen != c.l.gen {
+		panic("Cursor used after its LinkedList was split or spliced")
+	}
+	if c.n.removed {
+		panic("Cursor used after its element was removed")
+	}
+}
+
+// AtEnd returns true if c is at the end of its list rather than at an
+// element.
+func (c *Cursor[E]) AtEnd() bool {
+	c.check()
+	return c.n == c.l.root
+}
+
+// Value returns the element c is at. It panics if c is at the end.
+func (c *Cursor[E]) Value() E {
+	if c.AtEnd() {
+		panic("Cursor at the end of its LinkedList has no value")
+	}
+	return c.n.value
+}
+
+// SetValue replaces the element c is at. It panics if c is at the end.
+func (c *Cursor[E]) SetValue(e E) {
+	if c.AtEnd() {
+		panic("Cursor at the end of its LinkedList has no value")
+	}
+	c.n.value = e
+}
+
+// Next moves c to the next element, or from the last element to the end, or
+// from the end to the first element. It returns false if c is then at the
+// end.
+func (c *Cursor[E]) Next() bool {
+	c.check()
+	c.n = c.n.next
+	return c.n != c.l.root
+}
+
+// Prev moves c to the previous element, or from the first element to the end,
+// or from the end to the last element. It returns false if c is then at the
+// end.
+func (c *Cursor[E]) Prev() bool {
+	c.check()
+	c.n = c.n.prev
+	return c.n != c.l.root
+}
+
+// InsertBefore inserts e before c's position, so that at the end it becomes
+// the last element. c does not move.
+func (c *Cursor[E]) InsertBefore(e E) {
+	c.check()
+	c.l.insertBefore(e, c.n)
+}
+
+// InsertAfter inserts e after c's position, so that at the end it becomes the
+// first element. c does not move.
+func (c *Cursor[E]) InsertAfter(e E) {
+	c.check()
+	c.l.insertBefore(e, c.n.next)
+}
+
+// Remove removes and returns the element c is at, moving c to the element
+// after it. It panics if c is at the end.
+func (c *Cursor[E]) Remove() E {
+	if c.AtEnd() {
+		panic("Cursor at the end of its LinkedList has no value")
+	}
+	n := c.n
+	c.n = n.next
+	return c.l.remove(n)
+}
+
+// Split removes the elements from c's position to the back of its list, and
+// returns them as a new LinkedList, leaving c at the end of its list. Other
+// cursors of the list can no longer be used.
+func (c *Cursor[E]) Split() *LinkedList[E] {
+	c.check()
+	l := c.l
+	tail := &LinkedList[E]{}
+	tail.init()
+	if c.n != l.root {
+		first, last := c.n, l.root.prev
+		l.root.prev, first.prev.next = first.prev, l.root
+		tail.root.next, tail.root.prev = first, last
+		first.prev, last.next = tail.root, tail.root
+		l.sizeKnown, tail.sizeKnown = false, false
+	}
+	l.gen++
+	c.n, c.gen = l.root, l.gen
+	return tail
+}
+
+// Splice moves the elements of other before c's position, leaving other
+// empty. c does not move. The cursors of other can no longer be used. It
+// panics if other is c's list.
+func (c *Cursor[E]) Splice(other *LinkedList[E]) {
+	c.check()
+	if other == c.l {
+		panic("LinkedList cannot be spliced into itself")
+	}
+	other.init()
+	if other.root.next != other.root {
+		first, last := other.root.next, other.root.prev
+		first.prev, c.n.prev.next = c.n.prev, first
+		last.next, c.n.prev = c.n, last
+		c.l.size += other.size
+		c.l.sizeKnown = c.l.sizeKnown && other.sizeKnown
+		other.root.next, other.root.prev = other.root, other.root
+		other.size, other.sizeKnown = 0, true
+	}
+	other.gen++
+}
@@ -0,0 +1,119 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinkedList(t *testing.T) {
+	var l LinkedList[int]
+	for i := 1; i <= 3; i++ {
+		l.PushBack(i)
+	}
+	l.PushFront(0)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("Want [0 1 2 3], Got %v", got)
+	}
+	if got := slices.Collect(l.Backward()); !slices.Equal(got, []int{3, 2, 1, 0}) {
+		t.Errorf("Want [3 2 1 0] backward, Got %v", got)
+	}
+	if e, ok := l.PopBack(); !ok || e != 3 {
+		t.Errorf("Want PopBack() == (3, true), Got (%d, %t)", e, ok)
+	}
+	if e, ok := l.PopFront(); !ok || e != 0 || l.Len() != 2 {
+		t.Errorf("Want PopFront() == (0, true) leaving 2, Got (%d, %t) leaving %d", e, ok, l.Len())
+	}
+}
+
+func TestLinkedListCursor(t *testing.T) {
+	var l LinkedList[int]
+	for i := 0; i < 6; i++ {
+		l.PushBack(i)
+	}
+
+	// Remove odd elements and double the rest.
+	for c := l.Front(); !c.AtEnd(); {
+		if c.Value()%2 == 1 {
+			c.Remove()
+			continue
+		}
+		c.SetValue(c.Value() * 2)
+		c.Next()
+	}
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 4, 8}) {
+		t.Errorf("Want [0 4 8], Got %v", got)
+	}
+
+	c := l.Front()
+	c.Next()
+	c.InsertBefore(2)
+	c.InsertAfter(6)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 2, 4, 6, 8}) || c.Value() != 4 {
+		t.Errorf("Want [0 2 4 6 8] with cursor at 4, Got %v at %d", got, c.Value())
+	}
+
+	// The end lies between the last and first elements.
+	end := l.Back()
+	if end.Next() || !end.AtEnd() {
+		t.Errorf("Want Next() from the last element to reach the end")
+	}
+	end.InsertBefore(10)
+	end.InsertAfter(-2)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{-2, 0, 2, 4, 6, 8, 10}) || l.Len() != 7 {
+		t.Errorf("Want [-2 0 2 4 6 8 10], Got %v with Len() == %d", got, l.Len())
+	}
+}
+
+func TestLinkedListSplitSplice(t *testing.T) {
+	var l LinkedList[int]
+	for i := 0; i < 6; i++ {
+		l.PushBack(i)
+	}
+	other := l.Back()
+
+	c := l.Front()
+	c.Next()
+	c.Next()
+	tail := c.Split()
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 1}) || l.Len() != 2 {
+		t.Errorf("Want [0 1] left after Split, Got %v with Len() == %d", got, l.Len())
+	}
+	if got := slices.Collect(tail.All()); !slices.Equal(got, []int{2, 3, 4, 5}) || tail.Len() != 4 {
+		t.Errorf("Want [2 3 4 5] split off, Got %v with Len() == %d", got, tail.Len())
+	}
+	if !c.AtEnd() {
+		t.Errorf("Want the splitting cursor at the end of its list")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Want another cursor of a split list to panic, Got no panic")
+			}
+		}()
+		other.Value()
+	}()
+
+	// Splice the tail back in between 0 and 1.
+	c = l.Back()
+	c.Splice(tail)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 2, 3, 4, 5, 1}) || l.Len() != 6 {
+		t.Errorf("Want [0 2 3 4 5 1] after Splice, Got %v with Len() == %d", got, l.Len())
+	}
+	if tail.Len() != 0 || c.Value() != 1 {
+		t.Errorf("Want the spliced list empty and the cursor still at 1, Got Len() == %d, Value() == %d", tail.Len(), c.Value())
+	}
+}
+
+func TestLinkedListCursorOnRemovedElementPanics(t *testing.T) {
+	var l LinkedList[string]
+	l.PushBack("a")
+	c1, c2 := l.Front(), l.Front()
+	c1.Remove()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want a cursor at a removed element to panic, Got no panic")
+		}
+	}()
+	c2.Next()
+}
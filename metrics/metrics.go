@@ -0,0 +1,74 @@
+// Package metrics exports container statistics as expvar variables, so they
+// are served at /debug/vars alongside the rest of a program's metrics.
+package metrics
+
+import (
+	"expvar"
+
+	"github.org/jccarlson/collections/cache"
+	"github.org/jccarlson/collections/kvmap"
+)
+
+type sizer interface {
+	Len() int
+}
+
+type hashMapStatser interface {
+	Stats() kvmap.HashMapStats
+}
+
+type cacheStatser interface {
+	Stats() cache.Stats
+}
+
+// Publish registers an expvar.Map under name which reports the statistics of
+// c each time it is read. Which variables are published depends on the
+// methods c has:
+//
+//   - Len() int: "size"
+//   - Stats() kvmap.HashMapStats: "size", "capacity", "tombstones",
+//     "longest_probe", "rehashes" and "reseeds"
+//   - c itself being a func() kvmap.HashMapStats: the same as Stats()
+//   - Stats() cache.Stats: "hits", "misses", "hit_rate", "evictions",
+//     "load_successes", "load_failures" and "average_load_time_ns"
+//
+// Variables are read without synchronization, so c must be safe to read
+// concurrently with its other users: a cache.Cache, a map wrapped in a
+// kvmap.ConcurrentWrapper, whose Stats takes its lock, or a func which takes
+// the lock its caller guards the map with and returns the map's Stats. The
+// maps of package kvmap are not safe for concurrent use, so a map which is
+// written while published must not be passed directly. As with
+// expvar.Publish, Publish panics if name is already registered.
+func Publish(name string, c any) *expvar.Map {
+	m := new(expvar.Map).Init()
+
+	if s, ok := c.(sizer); ok {
+		m.Set("size", expvar.Func(func() any { return s.Len() }))
+	}
+	switch s := c.(type) {
+	case func() kvmap.HashMapStats:
+		publishHashMapStats(m, s)
+	case hashMapStatser:
+		publishHashMapStats(m, s.Stats)
+	case cacheStatser:
+		m.Set("hits", expvar.Func(func() any { return s.Stats().Hits }))
+		m.Set("misses", expvar.Func(func() any { return s.Stats().Misses }))
+		m.Set("hit_rate", expvar.Func(func() any { return s.Stats().HitRate() }))
+		m.Set("evictions", expvar.Func(func() any { return s.Stats().Evictions }))
+		m.Set("load_successes", expvar.Func(func() any { return s.Stats().LoadSuccesses }))
+		m.Set("load_failures", expvar.Func(func() any { return s.Stats().LoadFailures }))
+		m.Set("average_load_time_ns", expvar.Func(func() any { return s.Stats().AverageLoadTime().Nanoseconds() }))
+	}
+
+	expvar.Publish(name, m)
+	return m
+}
+
+func publishHashMapStats(m *expvar.Map, stats func() kvmap.HashMapStats) {
+	m.Set("size", expvar.Func(func() any { return stats().Len }))
+	m.Set("capacity", expvar.Func(func() any { return stats().Capacity }))
+	m.Set("tombstones", expvar.Func(func() any { return stats().Tombstones }))
+	m.Set("longest_probe", expvar.Func(func() any { return stats().LongestProbe }))
+	m.Set("rehashes", expvar.Func(func() any { return stats().Rehashes }))
+	m.Set("reseeds", expvar.Func(func() any { return stats().Reseeds }))
+}
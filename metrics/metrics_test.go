@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.org/jccarlson/collections/cache"
+	"github.org/jccarlson/collections/kvmap"
+)
+
+func readVars(t *testing.T, name string) map[string]float64 {
+	t.Helper()
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("Want expvar %q to be published, Got nil", name)
+	}
+	var got map[string]float64
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("Want valid JSON from expvar %q, Got error: %v", name, err)
+	}
+	return got
+}
+
+func TestPublishHashMap(t *testing.T) {
+	m := &kvmap.ConcurrentWrapper[int, int]{Base: kvmap.NewComparableLinkedHashMap[int, int](kvmap.Capacity(16))}
+	Publish("test_hashmap", m)
+
+	// Enough entries that the map builds its hash table.
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+	m.Delete(0)
+
+	got := readVars(t, "test_hashmap")
+	want := map[string]float64{"size": 9, "capacity": 16, "tombstones": 1}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Want %s == %v, Got %v", k, v, got[k])
+		}
+	}
+	if _, ok := got["longest_probe"]; !ok {
+		t.Errorf("Want longest_probe to be published, Got %v", got)
+	}
+}
+
+func TestPublishCache(t *testing.T) {
+	c := cache.New[string, int](cache.LRU[string](), cache.MaxEntries(10))
+	Publish("test_cache", c)
+
+	c.Put("a", 1)
+	c.Get("a")
+	c.Get("b")
+
+	got := readVars(t, "test_cache")
+	want := map[string]float64{"size": 1, "hits": 1, "misses": 1, "hit_rate": 0.5}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Want %s == %v, Got %v", k, v, got[k])
+		}
+	}
+}
+
+func TestPublishStatsFunc(t *testing.T) {
+	var mu sync.Mutex
+	m := kvmap.NewComparableLinkedHashMap[int, int](kvmap.Capacity(16))
+	Publish("test_hashmap_func", func() kvmap.HashMapStats {
+		mu.Lock()
+		defer mu.Unlock()
+		return m.Stats()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			mu.Lock()
+			m.Put(i, i)
+			mu.Unlock()
+		}
+	}()
+	readVars(t, "test_hashmap_func")
+	<-done
+
+	if got := readVars(t, "test_hashmap_func"); got["size"] != 100 {
+		t.Errorf("Want size == 100, Got %v", got["size"])
+	}
+}
+
+func TestPublishSizer(t *testing.T) {
+	m := kvmap.NewOrderedMap[int, int]()
+	Publish("test_orderedmap", m)
+	m.Put(1, 1)
+
+	got := readVars(t, "test_orderedmap")
+	if len(got) != 1 || got["size"] != 1 {
+		t.Errorf("Want {size: 1}, Got %v", got)
+	}
+}
@@ -0,0 +1,174 @@
+// Package persistent provides immutable collections. Every update returns a
+// new version of a collection, sharing most of its structure with the old
+// one, which remains valid and unchanged, so snapshots cost nothing to take
+// and are safe to share between goroutines.
+package persistent
+
+import "iter"
+
+// dequeBalance bounds the ratio between the lengths of a Deque's front and
+// rear lists.
+const dequeBalance = 3
+
+// list is an immutable singly-linked list.
+type list[E any] struct {
+	head E
+	tail *list[E]
+}
+
+// Deque is an immutable double-ended queue. PushFront, PushBack, PopFront and
+// PopBack return new Deques in amortized O(1) time, leaving the receiver
+// unchanged. The zero value is an empty Deque ready to use.
+//
+// Deque is a banker's deque: elements are held in a front list and a
+// reversed rear list, which are rebalanced in O(n) time when one grows much
+// longer than the other. The amortized bound assumes each version is updated
+// once; repeatedly popping from the same old version can repeat a
+// rebalance.
+type Deque[E any] struct {
+	front, rear   *list[E]
+	nfront, nrear int
+}
+
+// DequeOf returns a Deque holding es, with es[0] at the front.
+func DequeOf[E any](es ...E) Deque[E] {
+	return newBalancedDeque(es)
+}
+
+// newBalancedDeque returns a Deque holding es, split evenly between its
+// front and rear lists.
+func newBalancedDeque[E any](es []E) Deque[E] {
+	mid := (len(es) + 1) / 2
+	var d Deque[E]
+	for i := mid - 1; i >= 0; i-- {
+		d.front = &list[E]{es[i], d.front}
+	}
+	for i := mid; i < len(es); i++ {
+		d.rear = &list[E]{es[i], d.rear}
+	}
+	d.nfront, d.nrear = mid, len(es)-mid
+	return d
+}
+
+// balance returns d, rebuilt if its lists are too uneven.
+func (d Deque[E]) balance() Deque[E] {
+	if d.nfront > dequeBalance*d.nrear+1 || d.nrear > dequeBalance*d.nfront+1 {
+		return newBalancedDeque(d.appendTo(make([]E, 0, d.Len())))
+	}
+	return d
+}
+
+// appendTo appends the elements of d to es, from front to back.
+func (d Deque[E]) appendTo(es []E) []E {
+	for l := d.front; l != nil; l = l.tail {
+		es = append(es, l.head)
+	}
+	n := len(es)
+	for l := d.rear; l != nil; l = l.tail {
+		es = append(es, l.head)
+	}
+	// The rear list is reversed.
+	for i, j := n, len(es)-1; i < j; i, j = i+1, j-1 {
+		es[i], es[j] = es[j], es[i]
+	}
+	return es
+}
+
+// Len returns the number of elements in d.
+func (d Deque[E]) Len() int {
+	return d.nfront + d.nrear
+}
+
+// PushFront returns a Deque with e added at the front of d.
+func (d Deque[E]) PushFront(e E) Deque[E] {
+	d.front = &list[E]{e, d.front}
+	d.nfront++
+	return d.balance()
+}
+
+// PushBack returns a Deque with e added at the back of d.
+func (d Deque[E]) PushBack(e E) Deque[E] {
+	d.rear = &list[E]{e, d.rear}
+	d.nrear++
+	return d.balance()
+}
+
+// PeekFront returns the element at the front of d. ok is false if d is
+// empty.
+func (d Deque[E]) PeekFront() (e E, ok bool) {
+	switch {
+	case d.front != nil:
+		return d.front.head, true
+	case d.rear != nil:
+		// Balanced, so the rear holds the only element.
+		return d.rear.head, true
+	}
+	return
+}
+
+// PeekBack returns the element at the back of d. ok is false if d is empty.
+func (d Deque[E]) PeekBack() (e E, ok bool) {
+	switch {
+	case d.rear != nil:
+		return d.rear.head, true
+	case d.front != nil:
+		return d.front.head, true
+	}
+	return
+}
+
+// PopFront returns the element at the front of d, and a Deque without it. ok
+// is false if d is empty.
+func (d Deque[E]) PopFront() (e E, rest Deque[E], ok bool) {
+	switch {
+	case d.front != nil:
+		e, d.front = d.front.head, d.front.tail
+		d.nfront--
+	case d.rear != nil:
+		e, d.rear = d.rear.head, d.rear.tail
+		d.nrear--
+	default:
+		return e, d, false
+	}
+	return e, d.balance(), true
+}
+
+// PopBack returns the element at the back of d, and a Deque without it. ok is
+// false if d is empty.
+func (d Deque[E]) PopBack() (e E, rest Deque[E], ok bool) {
+	switch {
+	case d.rear != nil:
+		e, d.rear = d.rear.head, d.rear.tail
+		d.nrear--
+	case d.front != nil:
+		e, d.front = d.front.head, d.front.tail
+		d.nfront--
+	default:
+		return e, d, false
+	}
+	return e, d.balance(), true
+}
+
+// All returns an iter.Seq over the elements of d from front to back.
+func (d Deque[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for l := d.front; l != nil; l = l.tail {
+			if !yield(l.head) {
+				return
+			}
+		}
+		if d.rear == nil {
+			return
+		}
+		// The rear list is reversed, so collect it first.
+		var rear []E
+		for l := d.rear; l != nil; l = l.tail {
+			rear = append(rear, l.head)
+		}
+		for i := len(rear) - 1; i >= 0; i-- {
+			if !yield(rear[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package persistent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	for i := 0; i < 10; i++ {
+		d = d.PushBack(i).PushFront(-i - 1)
+	}
+	if d.Len() != 20 {
+		t.Errorf("Want Len() == 20, Got %d", d.Len())
+	}
+	if got := slices.Collect(d.All()); got[0] != -10 || got[19] != 9 || !slices.IsSorted(got) {
+		t.Errorf("Want elements from -10 to 9 in order, Got %v", got)
+	}
+
+	rest := d
+	for want := -10; want < 5; want++ {
+		var e int
+		var ok bool
+		if e, rest, ok = rest.PopFront(); !ok || e != want {
+			t.Fatalf("Want PopFront() == (%d, true), Got (%d, %t)", want, e, ok)
+		}
+	}
+	for want := 9; want >= 5; want-- {
+		var e int
+		var ok bool
+		if e, rest, ok = rest.PopBack(); !ok || e != want {
+			t.Fatalf("Want PopBack() == (%d, true), Got (%d, %t)", want, e, ok)
+		}
+	}
+	if _, _, ok := rest.PopFront(); ok || rest.Len() != 0 {
+		t.Errorf("Want an empty Deque, Got Len() == %d", rest.Len())
+	}
+	if d.Len() != 20 {
+		t.Errorf("Want popping to leave the original Deque unchanged, Got Len() == %d", d.Len())
+	}
+}
+
+func TestDequeVersions(t *testing.T) {
+	base := DequeOf(1, 2, 3)
+	a := base.PushBack(4)
+	b := base.PushFront(0)
+	_, c, _ := base.PopBack()
+
+	tcs := []struct {
+		name string
+		d    Deque[int]
+		want []int
+	}{
+		{"base", base, []int{1, 2, 3}},
+		{"PushBack", a, []int{1, 2, 3, 4}},
+		{"PushFront", b, []int{0, 1, 2, 3}},
+		{"PopBack", c, []int{1, 2}},
+	}
+	for _, tc := range tcs {
+		if got := slices.Collect(tc.d.All()); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: Want %v, Got %v", tc.name, tc.want, got)
+		}
+	}
+	if e, ok := c.PeekBack(); !ok || e != 2 {
+		t.Errorf("Want PeekBack() == (2, true), Got (%d, %t)", e, ok)
+	}
+	if e, ok := DequeOf(7).PeekFront(); !ok || e != 7 {
+		t.Errorf("Want PeekFront() of a single element == (7, true), Got (%d, %t)", e, ok)
+	}
+}
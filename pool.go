@@ -0,0 +1,88 @@
+package collections
+
+import "sync"
+
+// Pool is a free list of reusable values of type E, such as buffers or the
+// entry structs of other containers. Unlike sync.Pool, a Pool never drops
+// idle values behind the caller's back: a value Put is returned by a later
+// Get unless MaxIdle is exceeded or Validate rejects it. A Pool is safe for
+// concurrent use. The zero value is an empty Pool ready to use, whose Get
+// returns the zero value of E when the Pool is empty.
+type Pool[E any] struct {
+	// New returns a new value for Get when the Pool is empty. If it is nil,
+	// Get returns the zero value of E instead.
+	New func() E
+	// MaxIdle is the maximum number of idle values kept by the Pool. Values
+	// Put while the Pool is full are discarded. If it is 0, the number of idle
+	// values is unbounded.
+	MaxIdle int
+	// Reset, if not nil, is called with each value Put, before it is kept, to
+	// clear state which shouldn't be seen by the value's next user.
+	Reset func(E)
+	// Validate, if not nil, is called with each idle value before Get
+	// returns it. Values for which it returns false are discarded.
+	Validate func(E) bool
+
+	mu   sync.Mutex
+	idle []E
+}
+
+// NewPool returns a pointer to a new, empty Pool which creates values with
+// newFn.
+func NewPool[E any](newFn func() E) *Pool[E] {
+	return &Pool[E]{New: newFn}
+}
+
+// Get removes and returns the most recently Put valid value in p, or a new
+// value if there is none.
+func (p *Pool[E]) Get() E {
+	for {
+		p.mu.Lock()
+		n := len(p.idle)
+		if n == 0 {
+			p.mu.Unlock()
+			break
+		}
+		e := p.idle[n-1]
+		var zero E
+		p.idle[n-1] = zero
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		if p.Validate == nil || p.Validate(e) {
+			return e
+		}
+	}
+	if p.New == nil {
+		var zero E
+		return zero
+	}
+	return p.New()
+}
+
+// Put returns e to p for reuse, unless p already holds MaxIdle values. The
+// caller must not use e afterwards.
+func (p *Pool[E]) Put(e E) {
+	if p.Reset != nil {
+		p.Reset(e)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.MaxIdle > 0 && len(p.idle) >= p.MaxIdle {
+		return
+	}
+	p.idle = append(p.idle, e)
+}
+
+// Idle returns the number of idle values in p.
+func (p *Pool[E]) Idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle)
+}
+
+// Clear discards every idle value in p.
+func (p *Pool[E]) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle = nil
+}
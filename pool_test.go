@@ -0,0 +1,73 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+type pooledBuf struct {
+	b      []byte
+	broken bool
+}
+
+func TestPool(t *testing.T) {
+	created := 0
+	p := NewPool(func() *pooledBuf {
+		created++
+		return &pooledBuf{b: make([]byte, 0, 64)}
+	})
+	p.MaxIdle = 2
+	p.Reset = func(b *pooledBuf) { b.b = b.b[:0] }
+	p.Validate = func(b *pooledBuf) bool { return !b.broken }
+
+	b1, b2, b3 := p.Get(), p.Get(), p.Get()
+	if created != 3 {
+		t.Errorf("Want 3 values created, Got %d", created)
+	}
+	b1.b = append(b1.b, "data"...)
+	p.Put(b1)
+	p.Put(b2)
+	p.Put(b3)
+	if p.Idle() != 2 {
+		t.Errorf("Want Idle() == 2 with MaxIdle 2, Got %d", p.Idle())
+	}
+
+	// Values are reused most recently Put first, and are Reset.
+	if got := p.Get(); got != b2 {
+		t.Errorf("Want Get() to return b2, Got another value")
+	}
+	b1.broken = true
+	if got := p.Get(); got == b1 || created != 4 {
+		t.Errorf("Want invalid b1 discarded and a new value created, Got b1 == %t, %d created", got == b1, created)
+	}
+	if len(b1.b) != 0 {
+		t.Errorf("Want b1 Reset when Put, Got %q", b1.b)
+	}
+}
+
+func TestPoolZeroValue(t *testing.T) {
+	var p Pool[int]
+	if got := p.Get(); got != 0 {
+		t.Errorf("Want Get() == 0 from empty zero Pool, Got %d", got)
+	}
+	p.Put(5)
+	if got := p.Get(); got != 5 {
+		t.Errorf("Want Get() == 5, Got %d", got)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				p.Put(p.Get())
+			}
+		}()
+	}
+	wg.Wait()
+	p.Clear()
+	if p.Idle() != 0 {
+		t.Errorf("Want Idle() == 0 after Clear, Got %d", p.Idle())
+	}
+}
@@ -0,0 +1,195 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+)
+
+// maxRopeLeaf is the maximum number of elements stored in a Rope leaf.
+const maxRopeLeaf = 128
+
+type ropeNode[E any] struct {
+	left, right *ropeNode[E]
+	// leaf holds the elements of a leaf node, and is nil for internal nodes.
+	// Leaves are never modified once created, so they may share backing
+	// arrays.
+	leaf []E
+
+	size, height int
+}
+
+func (n *ropeNode[E]) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *ropeNode[E]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func newRopeLeaf[E any](es []E) *ropeNode[E] {
+	if len(es) == 0 {
+		return nil
+	}
+	return &ropeNode[E]{leaf: es, size: len(es), height: 1}
+}
+
+func newRopeNode[E any](left, right *ropeNode[E]) *ropeNode[E] {
+	return &ropeNode[E]{
+		left:   left,
+		right:  right,
+		size:   left.size + right.size,
+		height: max(left.height, right.height) + 1,
+	}
+}
+
+// balanceRope returns a node equivalent to newRopeNode(left, right), rotated
+// so that its children's heights differ by at most 1. The heights of left and
+// right must differ by at most 2.
+func balanceRope[E any](left, right *ropeNode[E]) *ropeNode[E] {
+	switch {
+	case left.height > right.height+1:
+		if left.left.getHeight() >= left.right.getHeight() {
+			return newRopeNode(left.left, newRopeNode(left.right, right))
+		}
+		lr := left.right
+		return newRopeNode(newRopeNode(left.left, lr.left), newRopeNode(lr.right, right))
+	case right.height > left.height+1:
+		if right.right.getHeight() >= right.left.getHeight() {
+			return newRopeNode(newRopeNode(left, right.left), right.right)
+		}
+		rl := right.left
+		return newRopeNode(newRopeNode(left, rl.left), newRopeNode(rl.right, right.right))
+	}
+	return newRopeNode(left, right)
+}
+
+// joinRope returns a balanced node holding the elements of l followed by
+// those of r, in time proportional to the difference in their heights.
+func joinRope[E any](l, r *ropeNode[E]) *ropeNode[E] {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.leaf != nil && r.leaf != nil && l.size+r.size <= maxRopeLeaf:
+		return newRopeLeaf(slices.Concat(l.leaf, r.leaf))
+	case l.height > r.height+1:
+		return balanceRope(l.left, joinRope(l.right, r))
+	case r.height > l.height+1:
+		return balanceRope(joinRope(l, r.left), r.right)
+	}
+	return newRopeNode(l, r)
+}
+
+// splitRope returns nodes holding the first i elements of n and the rest.
+func splitRope[E any](n *ropeNode[E], i int) (*ropeNode[E], *ropeNode[E]) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case n.leaf != nil:
+		return newRopeLeaf(n.leaf[:i:i]), newRopeLeaf(n.leaf[i:])
+	case i < n.left.size:
+		ll, lr := splitRope(n.left, i)
+		return ll, joinRope(lr, n.right)
+	}
+	rl, rr := splitRope(n.right, i-n.left.size)
+	return joinRope(n.left, rl), rr
+}
+
+// buildRope returns a balanced node holding es, split into full leaves.
+func buildRope[E any](es []E) *ropeNode[E] {
+	if len(es) <= maxRopeLeaf {
+		return newRopeLeaf(es)
+	}
+	// Split on a leaf boundary, so every leaf but the last is full.
+	leaves := (len(es) + maxRopeLeaf - 1) / maxRopeLeaf
+	mid := leaves / 2 * maxRopeLeaf
+	return joinRope(buildRope(es[:mid:mid]), buildRope(es[mid:]))
+}
+
+// Rope is an immutable sequence stored as a balanced tree of chunks, for
+// large sequences assembled from, or cut into, many pieces. Concat, Split
+// and Index take O(log n) time, and the results of Concat and Split share
+// structure with their inputs. The zero value is an empty Rope.
+type Rope[E any] struct {
+	root *ropeNode[E]
+}
+
+// NewRope returns a Rope holding a copy of es.
+func NewRope[E any](es ...E) Rope[E] {
+	return Rope[E]{buildRope(slices.Clone(es))}
+}
+
+// Len returns the number of elements in r.
+func (r Rope[E]) Len() int {
+	return r.root.getSize()
+}
+
+// Index returns the i'th element of r. It panics if i is out of range.
+func (r Rope[E]) Index(i int) E {
+	if i < 0 || i >= r.Len() {
+		panic("Rope index out of range")
+	}
+	n := r.root
+	for n.leaf == nil {
+		if i < n.left.size {
+			n = n.left
+		} else {
+			i -= n.left.size
+			n = n.right
+		}
+	}
+	return n.leaf[i]
+}
+
+// Concat returns a Rope holding the elements of r followed by those of other.
+func (r Rope[E]) Concat(other Rope[E]) Rope[E] {
+	return Rope[E]{joinRope(r.root, other.root)}
+}
+
+// Split returns Ropes holding the first i elements of r and the rest. It
+// panics if i is out of the range [0, r.Len()].
+func (r Rope[E]) Split(i int) (Rope[E], Rope[E]) {
+	if i < 0 || i > r.Len() {
+		panic("Rope split index out of range")
+	}
+	left, right := splitRope(r.root, i)
+	return Rope[E]{left}, Rope[E]{right}
+}
+
+// All returns an iter.Seq over the elements of r in order.
+func (r Rope[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		var walk func(n *ropeNode[E]) bool
+		walk = func(n *ropeNode[E]) bool {
+			if n == nil {
+				return true
+			}
+			if n.leaf != nil {
+				for _, e := range n.leaf {
+					if !yield(e) {
+						return false
+					}
+				}
+				return true
+			}
+			return walk(n.left) && walk(n.right)
+		}
+		walk(r.root)
+	}
+}
+
+// AppendTo appends the elements of r to dst and returns the extended slice.
+func (r Rope[E]) AppendTo(dst []E) []E {
+	dst = slices.Grow(dst, r.Len())
+	for e := range r.All() {
+		dst = append(dst, e)
+	}
+	return dst
+}
@@ -0,0 +1,81 @@
+package collections
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkRope verifies the size, height and balance invariants of n.
+func checkRope[E any](t *testing.T, n *ropeNode[E]) {
+	t.Helper()
+	if n == nil || n.leaf != nil {
+		return
+	}
+	checkRope(t, n.left)
+	checkRope(t, n.right)
+	if n.size != n.left.size+n.right.size {
+		t.Fatalf("Want node size %d, Got %d", n.left.size+n.right.size, n.size)
+	}
+	if d := n.left.height - n.right.height; d > 1 || d < -1 {
+		t.Fatalf("Want child heights to differ by at most 1, Got %d and %d", n.left.height, n.right.height)
+	}
+}
+
+func TestRopeConcatSplit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var r Rope[int]
+	var want []int
+	for i := 0; i < 200; i++ {
+		piece := make([]int, rnd.Intn(300))
+		for j := range piece {
+			piece[j] = rnd.Int()
+		}
+		if rnd.Intn(2) == 0 {
+			r, want = r.Concat(NewRope(piece...)), append(want, piece...)
+		} else {
+			r, want = NewRope(piece...).Concat(r), append(piece, want...)
+		}
+		checkRope(t, r.root)
+	}
+	if r.Len() != len(want) {
+		t.Fatalf("Want Len() == %d, Got %d", len(want), r.Len())
+	}
+	if got := r.AppendTo(nil); !slices.Equal(got, want) {
+		t.Fatalf("Want Rope contents to match concatenated pieces")
+	}
+
+	for i := 0; i < 100; i++ {
+		at := rnd.Intn(r.Len() + 1)
+		left, right := r.Split(at)
+		checkRope(t, left.root)
+		checkRope(t, right.root)
+		if !slices.Equal(left.AppendTo(nil), want[:at]) || !slices.Equal(right.AppendTo(nil), want[at:]) {
+			t.Fatalf("Want Split(%d) to divide the Rope at %d", at, at)
+		}
+		idx := rnd.Intn(r.Len())
+		if got := r.Index(idx); got != want[idx] {
+			t.Fatalf("Want Index(%d) == %d, Got %d", idx, want[idx], got)
+		}
+	}
+}
+
+func TestRopeImmutable(t *testing.T) {
+	src := []rune("hello world")
+	r := NewRope(src...)
+	src[0] = 'j'
+	hello, world := r.Split(5)
+	_ = hello.Concat(NewRope([]rune(", there")...))
+
+	if got := string(r.AppendTo(nil)); got != "hello world" {
+		t.Errorf(`Want "hello world", Got %q`, got)
+	}
+	if got := string(world.Concat(hello).AppendTo(nil)); got != " worldhello" {
+		t.Errorf(`Want " worldhello", Got %q`, got)
+	}
+
+	var empty Rope[rune]
+	if l, r := empty.Split(0); l.Len() != 0 || r.Len() != 0 {
+		t.Errorf("Want empty halves from splitting an empty Rope, Got %d and %d", l.Len(), r.Len())
+	}
+}
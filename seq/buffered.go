@@ -0,0 +1,56 @@
+package seq
+
+import (
+	"context"
+	"iter"
+)
+
+// Buffered returns a sequence yielding the elements of s, which are produced
+// ahead of the consumer by a background goroutine, up to n elements ahead.
+// This overlaps producing elements with consuming them, which helps when
+// both are expensive. s must be safe to run in another goroutine. Each
+// iteration of the returned sequence iterates s once, and stops it when the
+// consumer stops early.
+func Buffered[E any](s iter.Seq[E], n int) iter.Seq[E] {
+	return BufferedContext(context.Background(), s, n)
+}
+
+// BufferedContext is like Buffered, but iteration also ends, and the
+// background goroutine stops iterating s, once ctx is done.
+func BufferedContext[E any](ctx context.Context, s iter.Seq[E], n int) iter.Seq[E] {
+	if n <= 0 {
+		panic("Buffered requires n > 0")
+	}
+	return func(yield func(E) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		buf := make(chan E, n)
+		exited := make(chan struct{})
+		go func() {
+			defer close(exited)
+			defer close(buf)
+			for e := range s {
+				select {
+				case buf <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		// Don't return until s has stopped running.
+		defer func() {
+			cancel()
+			<-exited
+		}()
+
+		for {
+			select {
+			case e, ok := <-buf:
+				if !ok || !yield(e) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package seq
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestBuffered(t *testing.T) {
+	var pulled int
+	var finished bool
+	got := slices.Collect(Buffered(count(100, &pulled, &finished), 8))
+	if len(got) != 100 || !slices.IsSorted(got) || !finished {
+		t.Errorf("Want 0 to 99 in order from a finished source, Got %d elements, finished == %t", len(got), finished)
+	}
+}
+
+func TestBufferedStopsEarly(t *testing.T) {
+	var pulled int
+	var finished bool
+	for e := range Buffered(count(1000, &pulled, &finished), 4) {
+		if e == 10 {
+			break
+		}
+	}
+	// The producer runs at most the buffer size plus one element ahead.
+	if !finished || pulled > 11+4+1 {
+		t.Errorf("Want the source stopped shortly after 10, Got finished == %t, %d pulled", finished, pulled)
+	}
+}
+
+func TestBufferedContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endless := func(yield func(int) bool) {
+		for i := 0; yield(i); i++ {
+		}
+	}
+	n := 0
+	for range BufferedContext(ctx, endless, 2) {
+		if n++; n == 50 {
+			cancel()
+		}
+	}
+	if n < 50 {
+		t.Errorf("Want at least 50 elements before cancellation, Got %d", n)
+	}
+}
@@ -0,0 +1,76 @@
+package seq
+
+import (
+	"iter"
+
+	"github.org/jccarlson/collections/compare"
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// mergeHead is the next pair of one of the sequences being merged.
+type mergeHead[K, V any] struct {
+	k K
+	v V
+	// i is the index of the sequence, which breaks ties.
+	i    int
+	next func() (K, V, bool)
+}
+
+// MergeSorted returns a sequence yielding the elements of seqs, each of which
+// must be sorted by ord, in sorted order. Equal elements are yielded in the
+// order of the sequences holding them. It holds one element of each sequence
+// in a heap, so yielding each element takes O(log len(seqs)) time.
+func MergeSorted[E any](ord compare.Ordering[E], seqs ...iter.Seq[E]) iter.Seq[E] {
+	seqs2 := make([]iter.Seq2[E, struct{}], len(seqs))
+	for i, s := range seqs {
+		seqs2[i] = func(yield func(E, struct{}) bool) {
+			for e := range s {
+				if !yield(e, struct{}{}) {
+					return
+				}
+			}
+		}
+	}
+	merged := MergeSorted2(ord, seqs2...)
+	return func(yield func(E) bool) {
+		for e := range merged {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// MergeSorted2 is like MergeSorted for sequences of pairs sorted by key,
+// such as the All sequences of OrderedMaps.
+func MergeSorted2[K, V any](ord compare.Ordering[K], seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		h := ds.BinaryHeap[mergeHead[K, V]]{Ordering: func(a, b mergeHead[K, V]) bool {
+			if ord(a.k, b.k) {
+				return true
+			}
+			return !ord(b.k, a.k) && a.i < b.i
+		}}
+		heads := make([]mergeHead[K, V], 0, len(seqs))
+		for i, s := range seqs {
+			next, stop := iter.Pull2(s)
+			defer stop()
+			if k, v, ok := next(); ok {
+				heads = append(heads, mergeHead[K, V]{k, v, i, next})
+			}
+		}
+		h.InitFrom(heads)
+
+		for h.Len() > 0 {
+			top, _ := h.Peek()
+			if !yield(top.k, top.v) {
+				return
+			}
+			if k, v, ok := top.next(); ok {
+				h.Replace(mergeHead[K, V]{k, v, top.i, top.next})
+			} else {
+				h.Pop()
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package seq
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.org/jccarlson/collections/compare"
+)
+
+func TestMergeSorted(t *testing.T) {
+	tcs := []struct {
+		name string
+		seqs [][]int
+		want []int
+	}{
+		{"None", nil, nil},
+		{"One", [][]int{{1, 2, 3}}, []int{1, 2, 3}},
+		{"Interleaved", [][]int{{1, 4, 7}, {2, 5, 8}, {3, 6, 9}}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"Uneven", [][]int{{}, {5}, {1, 2, 3, 10}}, []int{1, 2, 3, 5, 10}},
+		{"Duplicates", [][]int{{1, 3}, {1, 3}}, []int{1, 1, 3, 3}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var seqs []iter.Seq[int]
+			for _, s := range tc.seqs {
+				seqs = append(seqs, slices.Values(s))
+			}
+			if got := slices.Collect(MergeSorted(compare.Less[int], seqs...)); !slices.Equal(got, tc.want) {
+				t.Errorf("Want %v, Got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMergeSorted2IsStable(t *testing.T) {
+	a := slices.All([]string{"a0", "b0"})
+	b := slices.All([]string{"a1", "b1", "c1"})
+	var got []string
+	for k, v := range MergeSorted2(compare.Less[int], a, b) {
+		got = append(got, v)
+		if k == 1 {
+			// Stopping early must stop the inputs too.
+			break
+		}
+	}
+	if !slices.Equal(got, []string{"a0", "a1", "b0"}) {
+		t.Errorf("Want ties in input order [a0 a1 b0], Got %v", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Package seq provides utilities for building and combining iter.Seq
+// sequences.
+package seq
+
+import (
+	"iter"
+	"sync"
+)
+
+// TeePolicy determines what TeeWithPolicy does when a consumer falls so far
+// behind the fastest that its buffer is full.
+type TeePolicy int
+
+const (
+	// TeeBlock makes faster consumers wait until the slow consumer catches
+	// up. Consumers must then run in separate goroutines, or a consumer
+	// which isn't iterating blocks the others forever.
+	TeeBlock TeePolicy = iota
+	// TeeDropOldest discards the oldest buffered elements of the slow
+	// consumer, so that it skips ahead.
+	TeeDropOldest
+	// TeeUnbounded lets buffers grow without limit, so that the sequences
+	// can be consumed one after another in a single goroutine.
+	TeeUnbounded
+)
+
+// DefaultTeeBuffer is the buffer size used by Tee.
+const DefaultTeeBuffer = 256
+
+// Tee returns n sequences which each yield the elements of s, while
+// iterating over s only once. It is TeeWithPolicy with a buffer of
+// DefaultTeeBuffer elements and TeeBlock.
+func Tee[E any](s iter.Seq[E], n int) []iter.Seq[E] {
+	return TeeWithPolicy(s, n, DefaultTeeBuffer, TeeBlock)
+}
+
+// TeeWithPolicy returns n sequences which each yield the elements of s, while
+// iterating over s only once, so that an expensive sequence can feed several
+// consumers. s is iterated as the fastest consumer demands, and the elements
+// each other consumer hasn't reached yet are buffered for it, up to bufSize,
+// beyond which policy applies.
+//
+// The sequences may be iterated concurrently, and each may be iterated only
+// once. A consumer which stops early no longer receives elements, and s is
+// stopped once every consumer has stopped or finished.
+func TeeWithPolicy[E any](s iter.Seq[E], n, bufSize int, policy TeePolicy) []iter.Seq[E] {
+	if n <= 0 || bufSize <= 0 {
+		panic("Tee requires n > 0 and bufSize > 0")
+	}
+	t := &tee[E]{s: s, bufs: make([]teeBuffer[E], n), active: n, bufSize: bufSize, policy: policy}
+	t.cond.L = &t.mu
+	seqs := make([]iter.Seq[E], n)
+	for i := range seqs {
+		t.bufs[i].active = true
+		seqs[i] = func(yield func(E) bool) { t.consume(i, yield) }
+	}
+	return seqs
+}
+
+type teeBuffer[E any] struct {
+	queue  []E
+	active bool
+}
+
+type tee[E any] struct {
+	s    iter.Seq[E]
+	next func() (E, bool)
+	stop func()
+
+	mu   sync.Mutex
+	cond sync.Cond
+
+	bufs []teeBuffer[E]
+	// active is the number of consumers which haven't stopped or finished.
+	active int
+	// done is set once s is exhausted.
+	done bool
+
+	bufSize int
+	policy  TeePolicy
+}
+
+// detachLocked stops buffering for consumer i, and stops s once no consumer
+// is left. t.mu must be held.
+func (t *tee[E]) detachLocked(i int) {
+	if !t.bufs[i].active {
+		return
+	}
+	t.bufs[i] = teeBuffer[E]{}
+	t.cond.Broadcast()
+	if t.active--; t.active == 0 && t.stop != nil {
+		t.stop()
+	}
+}
+
+// mustWaitLocked returns true if pulling another element would overflow the
+// buffer of a consumer other than i under TeeBlock. t.mu must be held.
+func (t *tee[E]) mustWaitLocked(i int) bool {
+	if t.policy != TeeBlock {
+		return false
+	}
+	for j := range t.bufs {
+		if j != i && t.bufs[j].active && len(t.bufs[j].queue) >= t.bufSize {
+			return true
+		}
+	}
+	return false
+}
+
+// pullLocked takes the next element of s, buffering it for every active
+// consumer other than i. t.mu must be held.
+func (t *tee[E]) pullLocked(i int) (e E, ok bool) {
+	if t.next == nil {
+		t.next, t.stop = iter.Pull(t.s)
+	}
+	if e, ok = t.next(); !ok {
+		t.done = true
+		t.cond.Broadcast()
+		return e, false
+	}
+	for j := range t.bufs {
+		b := &t.bufs[j]
+		if j == i || !b.active {
+			continue
+		}
+		if t.policy == TeeDropOldest && len(b.queue) >= t.bufSize {
+			var zero E
+			b.queue[0] = zero
+			b.queue = b.queue[1:]
+		}
+		b.queue = append(b.queue, e)
+	}
+	t.cond.Broadcast()
+	return e, true
+}
+
+func (t *tee[E]) consume(i int, yield func(E) bool) {
+	t.mu.Lock()
+	locked := true
+	defer func() {
+		// t.mu isn't held if yield panicked.
+		if !locked {
+			t.mu.Lock()
+		}
+		t.detachLocked(i)
+		t.mu.Unlock()
+	}()
+	for t.bufs[i].active {
+		var e E
+		if b := &t.bufs[i]; len(b.queue) > 0 {
+			var zero E
+			e, b.queue[0] = b.queue[0], zero
+			b.queue = b.queue[1:]
+			// Room was made for a blocked consumer.
+			t.cond.Broadcast()
+		} else if t.done {
+			return
+		} else if t.mustWaitLocked(i) {
+			t.cond.Wait()
+			continue
+		} else {
+			var ok bool
+			if e, ok = t.pullLocked(i); !ok {
+				return
+			}
+		}
+
+		t.mu.Unlock()
+		locked = false
+		ok := yield(e)
+		t.mu.Lock()
+		locked = true
+		if !ok {
+			return
+		}
+	}
+}
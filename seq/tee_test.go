@@ -0,0 +1,116 @@
+package seq
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// count returns a sequence of 0 to n-1 which records how many elements it
+// yielded and whether it finished.
+func count(n int, pulled *int, finished *bool) func(func(int) bool) {
+	return func(yield func(int) bool) {
+		defer func() { *finished = true }()
+		for i := 0; i < n; i++ {
+			*pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestTeeConcurrent(t *testing.T) {
+	var pulled int
+	var finished bool
+	seqs := TeeWithPolicy(count(1000, &pulled, &finished), 3, 4, TeeBlock)
+
+	results := make([][]int, len(seqs))
+	var wg sync.WaitGroup
+	for i, s := range seqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = slices.Collect(s)
+		}()
+	}
+	wg.Wait()
+	for i, r := range results {
+		if len(r) != 1000 || !slices.IsSorted(r) || r[999] != 999 {
+			t.Errorf("Want consumer %d to get 0 to 999 in order, Got %d elements", i, len(r))
+		}
+	}
+	if pulled != 1000 || !finished {
+		t.Errorf("Want the source iterated once to completion, Got %d pulled, finished == %t", pulled, finished)
+	}
+}
+
+func TestTeeUnboundedSequential(t *testing.T) {
+	var pulled int
+	var finished bool
+	seqs := TeeWithPolicy(count(10, &pulled, &finished), 2, 1, TeeUnbounded)
+	a, b := slices.Collect(seqs[0]), slices.Collect(seqs[1])
+	if !slices.Equal(a, b) || len(a) != 10 || pulled != 10 {
+		t.Errorf("Want both consumers to get 10 elements from one pass, Got %v and %v with %d pulled", a, b, pulled)
+	}
+}
+
+func TestTeeDropOldest(t *testing.T) {
+	var pulled int
+	var finished bool
+	seqs := TeeWithPolicy(count(10, &pulled, &finished), 2, 3, TeeDropOldest)
+	for range seqs[0] {
+	}
+	if got := slices.Collect(seqs[1]); !slices.Equal(got, []int{7, 8, 9}) {
+		t.Errorf("Want the slow consumer to get only the last 3 elements, Got %v", got)
+	}
+}
+
+func TestTeeStopsSourceWhenAllStop(t *testing.T) {
+	var pulled int
+	var finished bool
+	seqs := TeeWithPolicy(count(1000, &pulled, &finished), 2, 1000, TeeBlock)
+	for e := range seqs[0] {
+		if e == 5 {
+			break
+		}
+	}
+	if finished {
+		t.Errorf("Want the source running while a consumer remains, Got finished")
+	}
+	for e := range seqs[1] {
+		if e == 2 {
+			break
+		}
+	}
+	if !finished || pulled != 6 {
+		t.Errorf("Want the source stopped after 6 elements once both consumers stop, Got finished == %t, %d pulled", finished, pulled)
+	}
+	if got := slices.Collect(seqs[0]); got != nil {
+		t.Errorf("Want a stopped consumer to yield nothing more, Got %v", got)
+	}
+}
+
+func TestTeeConsumerPanic(t *testing.T) {
+	var pulled int
+	var finished bool
+	seqs := TeeWithPolicy(count(10, &pulled, &finished), 2, 10, TeeUnbounded)
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Want to recover the consumer's panic, Got %v", r)
+			}
+		}()
+		for e := range seqs[0] {
+			if e == 3 {
+				panic("boom")
+			}
+		}
+	}()
+	if got := slices.Collect(seqs[1]); len(got) != 10 {
+		t.Errorf("Want the other consumer to get 10 elements after a panic, Got %v", got)
+	}
+	if !finished || pulled != 10 {
+		t.Errorf("Want the source iterated once to completion, Got %d pulled, finished == %t", pulled, finished)
+	}
+}
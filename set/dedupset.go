@@ -0,0 +1,111 @@
+// Package set provides set types built on the maps and hashers in package
+// kvmap.
+package set
+
+import (
+	"fmt"
+	"math"
+
+	"github.org/jccarlson/collections/kvmap"
+)
+
+// DedupSet is a set of comparable elements optimized for checking whether an
+// element has been seen before, when most checks are for new elements. A
+// Bloom filter answers most negative checks without touching the exact
+// backing set, which is only consulted when the filter reports a possible
+// match. Elements cannot be removed from a DedupSet.
+type DedupSet[E comparable] struct {
+	hasher kvmap.MapHasher[E]
+	bits   []uint64
+	// nbits is the size of the Bloom filter in bits.
+	nbits uint64
+	// k is the number of bits set per element.
+	k int
+
+	exact map[E]struct{}
+}
+
+// NewDedupSet returns a pointer to a new, empty DedupSet sized so that after
+// expected elements are added, a check for a new element consults the exact
+// backing set with probability about fpRate. Lower rates use more memory for
+// the filter: about 1.44*log2(1/fpRate) bits per expected element.
+func NewDedupSet[E comparable](expected int, fpRate float64) *DedupSet[E] {
+	if expected <= 0 {
+		panic("DedupSet expected size must be > 0")
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		panic(fmt.Sprintf("DedupSet false-positive rate %f out of range (0.0, 1.0)", fpRate))
+	}
+
+	nbits := uint64(math.Ceil(-float64(expected) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	nbits = (nbits + 63) &^ 63
+	k := int(math.Round(float64(nbits) / float64(expected) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &DedupSet[E]{
+		hasher: kvmap.ComparableMapHasher[E](),
+		bits:   make([]uint64, nbits/64),
+		nbits:  nbits,
+		k:      k,
+		exact:  make(map[E]struct{}, expected),
+	}
+}
+
+// probes calls f with each of the k filter bit positions for the element
+// with hash h, stopping if f returns false. Positions are derived from the
+// two halves of h by double hashing.
+func (s *DedupSet[E]) probes(h uint64, f func(bit uint64) bool) {
+	h1, h2 := h&0xffffffff, h>>32|1
+	for i := 0; i < s.k; i++ {
+		if !f((h1 + uint64(i)*h2) % s.nbits) {
+			return
+		}
+	}
+}
+
+func (s *DedupSet[E]) mayContain(h uint64) bool {
+	ok := true
+	s.probes(h, func(bit uint64) bool {
+		ok = s.bits[bit/64]&(1<<(bit%64)) != 0
+		return ok
+	})
+	return ok
+}
+
+// Add adds e to s, and returns true if e was not already in s.
+func (s *DedupSet[E]) Add(e E) bool {
+	h := s.hasher.Hash(&e)
+	if s.mayContain(h) {
+		if _, ok := s.exact[e]; ok {
+			return false
+		}
+	}
+	s.probes(h, func(bit uint64) bool {
+		s.bits[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+	s.exact[e] = struct{}{}
+	return true
+}
+
+// Has returns true if e is in s.
+func (s *DedupSet[E]) Has(e E) bool {
+	if !s.mayContain(s.hasher.Hash(&e)) {
+		return false
+	}
+	_, ok := s.exact[e]
+	return ok
+}
+
+// Len returns the number of elements in s.
+func (s *DedupSet[E]) Len() int {
+	return len(s.exact)
+}
+
+// FalsePositiveRate returns the estimated probability, given the current
+// number of elements, that a check for a new element must consult the exact
+// backing set.
+func (s *DedupSet[E]) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(s.k)*float64(len(s.exact))/float64(s.nbits)), float64(s.k))
+}
@@ -0,0 +1,69 @@
+package set
+
+import (
+	"testing"
+)
+
+func TestDedupSet(t *testing.T) {
+	s := NewDedupSet[int](100, 0.01)
+	for i := 0; i < 100; i++ {
+		if !s.Add(i) {
+			t.Errorf("Want Add(%d) == true for new element, Got false", i)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if s.Add(i) {
+			t.Errorf("Want Add(%d) == false for seen element, Got true", i)
+		}
+		if !s.Has(i) {
+			t.Errorf("Want Has(%d) == true, Got false", i)
+		}
+	}
+	for i := 100; i < 200; i++ {
+		if s.Has(i) {
+			t.Errorf("Want Has(%d) == false, Got true", i)
+		}
+	}
+	if s.Len() != 100 {
+		t.Errorf("Want Len() == 100, Got %d", s.Len())
+	}
+}
+
+func TestDedupSetFalsePositiveRate(t *testing.T) {
+	const n = 10000
+	for _, rate := range []float64{0.1, 0.01, 0.001} {
+		s := NewDedupSet[int](n, rate)
+		for i := 0; i < n; i++ {
+			s.Add(i)
+		}
+		if est := s.FalsePositiveRate(); est > rate*1.5 {
+			t.Errorf("Want estimated false-positive rate near %v, Got %v", rate, est)
+		}
+
+		fp := 0
+		for i := n; i < 2*n; i++ {
+			if s.mayContain(s.hasher.Hash(&i)) {
+				fp++
+			}
+		}
+		if got := float64(fp) / n; got > rate*2 {
+			t.Errorf("Want measured false-positive rate near %v, Got %v", rate, got)
+		}
+	}
+}
+
+func TestNewDedupSetPanics(t *testing.T) {
+	for _, tc := range []struct {
+		expected int
+		rate     float64
+	}{{0, 0.1}, {10, 0}, {10, 1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Want NewDedupSet(%d, %v) to panic, Got no panic", tc.expected, tc.rate)
+				}
+			}()
+			NewDedupSet[int](tc.expected, tc.rate)
+		}()
+	}
+}
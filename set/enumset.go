@@ -0,0 +1,209 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// EnumSet is a set of small non-negative integers, such as the values of an
+// enum type, stored as a bitmask of one bit per possible element. Membership
+// checks are a single bit test, and set algebra works a word of 64 elements
+// at a time. Memory use is proportional to the largest element, so EnumSet
+// suits domains of at most a few thousand values. The zero value is an empty
+// EnumSet ready to use.
+type EnumSet[E constraints.Integer] struct {
+	words []uint64
+}
+
+// NewEnumSet returns a new EnumSet holding elems.
+func NewEnumSet[E constraints.Integer](elems ...E) EnumSet[E] {
+	var s EnumSet[E]
+	for _, e := range elems {
+		s.Add(e)
+	}
+	return s
+}
+
+// EnumSetFromSeq returns a new EnumSet holding the elements of seq, e.g. the
+// keys of a map via maps.Keys.
+func EnumSetFromSeq[E constraints.Integer](seq iter.Seq[E]) EnumSet[E] {
+	var s EnumSet[E]
+	for e := range seq {
+		s.Add(e)
+	}
+	return s
+}
+
+func enumIndex[E constraints.Integer](e E) (word int, bit uint64) {
+	if e < 0 {
+		panic(fmt.Sprintf("EnumSet element %d must be >= 0", e))
+	}
+	return int(uint64(e) / 64), 1 << (uint64(e) % 64)
+}
+
+// Add adds e to s, and returns true if e was not already in s. Add panics if
+// e is negative.
+func (s *EnumSet[E]) Add(e E) bool {
+	w, b := enumIndex(e)
+	if w >= len(s.words) {
+		s.words = append(s.words, make([]uint64, w+1-len(s.words))...)
+	}
+	if s.words[w]&b != 0 {
+		return false
+	}
+	s.words[w] |= b
+	return true
+}
+
+// Remove removes e from s, and returns true if e was in s.
+func (s *EnumSet[E]) Remove(e E) bool {
+	if !s.Has(e) {
+		return false
+	}
+	w, b := enumIndex(e)
+	s.words[w] &^= b
+	s.trim()
+	return true
+}
+
+// trim drops trailing zero words, so that equal sets have equal words.
+func (s *EnumSet[E]) trim() {
+	n := len(s.words)
+	for n > 0 && s.words[n-1] == 0 {
+		n--
+	}
+	s.words = s.words[:n]
+}
+
+// Has returns true if e is in s.
+func (s EnumSet[E]) Has(e E) bool {
+	if e < 0 {
+		return false
+	}
+	w, b := enumIndex(e)
+	return w < len(s.words) && s.words[w]&b != 0
+}
+
+// Len returns the number of elements in s.
+func (s EnumSet[E]) Len() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Clear removes every element from s.
+func (s *EnumSet[E]) Clear() {
+	s.words = nil
+}
+
+// Clone returns a copy of s.
+func (s EnumSet[E]) Clone() EnumSet[E] {
+	return EnumSet[E]{append([]uint64(nil), s.words...)}
+}
+
+// All returns an iter.Seq over the elements of s in ascending order. s must
+// not be modified during iteration.
+func (s EnumSet[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i, w := range s.words {
+			for ; w != 0; w &= w - 1 {
+				if !yield(E(i*64 + bits.TrailingZeros64(w))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ToMap returns a new map holding the elements of s as keys.
+func (s EnumSet[E]) ToMap() map[E]struct{} {
+	m := make(map[E]struct{}, s.Len())
+	for e := range s.All() {
+		m[e] = struct{}{}
+	}
+	return m
+}
+
+// combine returns a new EnumSet whose words are f applied to the words of s
+// and t, treating missing words as 0.
+func (s EnumSet[E]) combine(t EnumSet[E], f func(a, b uint64) uint64) EnumSet[E] {
+	r := EnumSet[E]{make([]uint64, max(len(s.words), len(t.words)))}
+	for i := range r.words {
+		var a, b uint64
+		if i < len(s.words) {
+			a = s.words[i]
+		}
+		if i < len(t.words) {
+			b = t.words[i]
+		}
+		r.words[i] = f(a, b)
+	}
+	r.trim()
+	return r
+}
+
+// Union returns a new EnumSet holding the elements in s or t.
+func (s EnumSet[E]) Union(t EnumSet[E]) EnumSet[E] {
+	return s.combine(t, func(a, b uint64) uint64 { return a | b })
+}
+
+// Intersection returns a new EnumSet holding the elements in both s and t.
+func (s EnumSet[E]) Intersection(t EnumSet[E]) EnumSet[E] {
+	return s.combine(t, func(a, b uint64) uint64 { return a & b })
+}
+
+// Difference returns a new EnumSet holding the elements in s but not t.
+func (s EnumSet[E]) Difference(t EnumSet[E]) EnumSet[E] {
+	return s.combine(t, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// SymmetricDifference returns a new EnumSet holding the elements in exactly
+// one of s and t.
+func (s EnumSet[E]) SymmetricDifference(t EnumSet[E]) EnumSet[E] {
+	return s.combine(t, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// Equal returns true if s and t hold the same elements.
+func (s EnumSet[E]) Equal(t EnumSet[E]) bool {
+	if len(s.words) != len(t.words) {
+		return false
+	}
+	for i := range s.words {
+		if s.words[i] != t.words[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if every element of s is in t.
+func (s EnumSet[E]) IsSubsetOf(t EnumSet[E]) bool {
+	if len(s.words) > len(t.words) {
+		return false
+	}
+	for i := range s.words {
+		if s.words[i]&^t.words[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s EnumSet[E]) String() string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for e := range s.All() {
+		if sb.Len() > 1 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprint(&sb, e)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package set
+
+import (
+	"maps"
+	"testing"
+)
+
+type weekday uint8
+
+const (
+	sunday weekday = iota
+	monday
+	tuesday
+	wednesday
+	thursday
+	friday
+	saturday
+)
+
+func TestEnumSet(t *testing.T) {
+	weekend := NewEnumSet(saturday, sunday)
+	var s EnumSet[weekday]
+	if !s.Add(monday) || !s.Add(friday) || s.Add(monday) {
+		t.Errorf("Want Add to report whether the element was new")
+	}
+	if !s.Has(friday) || s.Has(sunday) || s.Len() != 2 {
+		t.Errorf("Want {1 5}, Got %v", s)
+	}
+
+	tcs := []struct {
+		name string
+		got  EnumSet[weekday]
+		want string
+	}{
+		{"Union", s.Union(weekend), "{0 1 5 6}"},
+		{"Intersection", s.Intersection(NewEnumSet(friday, saturday)), "{5}"},
+		{"Difference", s.Difference(NewEnumSet(friday)), "{1}"},
+		{"SymmetricDifference", s.SymmetricDifference(NewEnumSet(friday, saturday)), "{1 6}"},
+	}
+	for _, tc := range tcs {
+		if got := tc.got.String(); got != tc.want {
+			t.Errorf("%s: Want %s, Got %s", tc.name, tc.want, got)
+		}
+	}
+
+	if !NewEnumSet(friday).IsSubsetOf(s) || s.IsSubsetOf(weekend) {
+		t.Errorf("Want {5} subset of %v and %[1]v not subset of %v", s, weekend)
+	}
+	if !s.Union(weekend).Difference(weekend).Equal(s) {
+		t.Errorf("Want (s | weekend) - weekend == s")
+	}
+	if !s.Remove(friday) || s.Remove(friday) || !s.Equal(NewEnumSet(monday)) {
+		t.Errorf("Want Remove to report whether the element was present, leaving {1}, Got %v", s)
+	}
+}
+
+func TestEnumSetLargeElementsAndMaps(t *testing.T) {
+	s := NewEnumSet(3, 64, 200, 1000)
+	var got []int
+	for e := range s.All() {
+		got = append(got, e)
+	}
+	if len(got) != 4 || got[0] != 3 || got[1] != 64 || got[2] != 200 || got[3] != 1000 {
+		t.Errorf("Want All() == [3 64 200 1000], Got %v", got)
+	}
+	if !EnumSetFromSeq(maps.Keys(s.ToMap())).Equal(s) {
+		t.Errorf("Want round trip through ToMap to equal %v", s)
+	}
+	s.Remove(1000)
+	if !s.Equal(NewEnumSet(200, 64, 3)) {
+		t.Errorf("Want sets with equal elements Equal regardless of history")
+	}
+	if s.Has(-1) {
+		t.Errorf("Want Has(-1) == false, Got true")
+	}
+}
@@ -0,0 +1,168 @@
+package set
+
+import (
+	"iter"
+	"math"
+
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// scoredMember is a member of a ScoredSet with its score. seq breaks ties
+// between equal scores, ordering members by when they were given the score.
+type scoredMember[E any] struct {
+	member E
+	score  float64
+	seq    uint64
+}
+
+func scoredMemberOrdering[E any](m1, m2 *scoredMember[E]) bool {
+	if m1.score != m2.score {
+		return m1.score < m2.score
+	}
+	return m1.seq < m2.seq
+}
+
+// ScoredSet is a set of members, each with a float64 score, which iterates in
+// score order, like a Redis sorted set. A hash map finds members' scores, and
+// a red-black tree orders members by score. Members with equal scores are
+// ordered by when they were given that score.
+type ScoredSet[E comparable] struct {
+	members map[E]*scoredMember[E]
+	byScore ds.RedBlackTree[*scoredMember[E]]
+	seq     uint64
+}
+
+// NewScoredSet returns a pointer to a new, empty ScoredSet.
+func NewScoredSet[E comparable]() *ScoredSet[E] {
+	return &ScoredSet[E]{
+		members: make(map[E]*scoredMember[E]),
+		byScore: ds.RedBlackTree[*scoredMember[E]]{Ordering: scoredMemberOrdering[E]},
+	}
+}
+
+// Add sets the score of member, adding it to s if it is not present. It
+// returns true if member was added. Add panics if score is NaN.
+func (s *ScoredSet[E]) Add(member E, score float64) bool {
+	if math.IsNaN(score) {
+		panic("ScoredSet score must not be NaN")
+	}
+	m, ok := s.members[member]
+	if ok {
+		if m.score == score {
+			return false
+		}
+		s.byScore.Delete(m)
+	} else {
+		m = &scoredMember[E]{member: member}
+		s.members[member] = m
+	}
+	s.seq++
+	m.score, m.seq = score, s.seq
+	s.byScore.Put(m)
+	return !ok
+}
+
+// IncrBy adds delta to the score of member, adding it to s with a score of
+// delta if it is not present, and returns the new score.
+func (s *ScoredSet[E]) IncrBy(member E, delta float64) float64 {
+	score := delta
+	if m, ok := s.members[member]; ok {
+		score += m.score
+	}
+	s.Add(member, score)
+	return score
+}
+
+// Score returns the score of member. ok is false if member is not in s.
+func (s *ScoredSet[E]) Score(member E) (score float64, ok bool) {
+	m, ok := s.members[member]
+	if !ok {
+		return 0, false
+	}
+	return m.score, true
+}
+
+// Remove removes member from s, and returns true if it was present.
+func (s *ScoredSet[E]) Remove(member E) bool {
+	m, ok := s.members[member]
+	if !ok {
+		return false
+	}
+	delete(s.members, member)
+	s.byScore.Delete(m)
+	return true
+}
+
+// Has returns true if member is in s.
+func (s *ScoredSet[E]) Has(member E) bool {
+	_, ok := s.members[member]
+	return ok
+}
+
+// Len returns the number of members in s.
+func (s *ScoredSet[E]) Len() int {
+	return len(s.members)
+}
+
+// Rank returns the 0-based position of member in s in ascending score order.
+// ok is false if member is not in s. Rank takes O(log n) time.
+func (s *ScoredSet[E]) Rank(member E) (rank int, ok bool) {
+	m, ok := s.members[member]
+	if !ok {
+		return 0, false
+	}
+	return s.byScore.Rank(m), true
+}
+
+// walkScores calls yield with each member and score from n onwards in direction d,
+// until yield or more returns false.
+func walkScores[E any](n *ds.TreeNode[*scoredMember[E]], d ds.Direction, more func(*scoredMember[E]) bool, yield func(E, float64) bool) {
+	for ; n != nil && more(n.Elem); n = n.Walk(d) {
+		if !yield(n.Elem.member, n.Elem.score) {
+			return
+		}
+	}
+}
+
+func always[E any](*scoredMember[E]) bool { return true }
+
+// All returns an iter.Seq2 over the members and scores of s in ascending
+// score order. s must not be modified during iteration.
+func (s *ScoredSet[E]) All() iter.Seq2[E, float64] {
+	return func(yield func(E, float64) bool) {
+		walkScores(s.byScore.First(), ds.Right, always[E], yield)
+	}
+}
+
+// Backward returns an iter.Seq2 over the members and scores of s in
+// descending score order. s must not be modified during iteration.
+func (s *ScoredSet[E]) Backward() iter.Seq2[E, float64] {
+	return func(yield func(E, float64) bool) {
+		walkScores(s.byScore.Last(), ds.Left, always[E], yield)
+	}
+}
+
+// RangeByScore returns an iter.Seq2 over the members of s with scores in
+// [min, max], in ascending score order. s must not be modified during
+// iteration.
+func (s *ScoredSet[E]) RangeByScore(min, max float64) iter.Seq2[E, float64] {
+	return func(yield func(E, float64) bool) {
+		start := s.byScore.Ceiling(&scoredMember[E]{score: min})
+		walkScores(start, ds.Right, func(m *scoredMember[E]) bool { return m.score <= max }, yield)
+	}
+}
+
+// RangeByRank returns an iter.Seq2 over the members of s with ranks in
+// [start, stop), in ascending score order. Finding the member at start takes
+// O(log n) time. s must not be modified during iteration.
+func (s *ScoredSet[E]) RangeByRank(start, stop int) iter.Seq2[E, float64] {
+	return func(yield func(E, float64) bool) {
+		start := max(start, 0)
+		n := s.byScore.Select(start)
+		rank := start
+		walkScores(n, ds.Right, func(*scoredMember[E]) bool {
+			rank++
+			return rank <= stop
+		}, yield)
+	}
+}
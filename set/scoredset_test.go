@@ -0,0 +1,74 @@
+package set
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func members(seq iter.Seq2[string, float64]) []string {
+	var r []string
+	for m := range seq {
+		r = append(r, m)
+	}
+	return r
+}
+
+func TestScoredSet(t *testing.T) {
+	s := NewScoredSet[string]()
+	for _, m := range []struct {
+		name  string
+		score float64
+	}{{"carol", 30}, {"alice", 10}, {"bob", 20}, {"dave", 20}} {
+		if !s.Add(m.name, m.score) {
+			t.Errorf("Want Add(%s) == true, Got false", m.name)
+		}
+	}
+	if s.Add("alice", 10) {
+		t.Errorf("Want Add() of an existing member == false, Got true")
+	}
+	if got := s.IncrBy("alice", 15); got != 25 {
+		t.Errorf("Want IncrBy(alice, 15) == 25, Got %v", got)
+	}
+	if got := s.IncrBy("erin", 5); got != 5 {
+		t.Errorf("Want IncrBy(erin, 5) == 5, Got %v", got)
+	}
+
+	want := []string{"erin", "bob", "dave", "alice", "carol"}
+	if got := members((s.All())); !slices.Equal(got, want) {
+		t.Errorf("Want %v, Got %v", want, got)
+	}
+	if got := members((s.Backward())); !slices.Equal(got, []string{"carol", "alice", "dave", "bob", "erin"}) {
+		t.Errorf("Want reversed order, Got %v", got)
+	}
+	for i, m := range want {
+		if r, ok := s.Rank(m); !ok || r != i {
+			t.Errorf("Want Rank(%s) == (%d, true), Got (%d, %t)", m, i, r, ok)
+		}
+	}
+	if got := members((s.RangeByScore(20, 25))); !slices.Equal(got, []string{"bob", "dave", "alice"}) {
+		t.Errorf("Want RangeByScore(20, 25) == [bob dave alice], Got %v", got)
+	}
+	if got := members((s.RangeByRank(1, 3))); !slices.Equal(got, []string{"bob", "dave"}) {
+		t.Errorf("Want RangeByRank(1, 3) == [bob dave], Got %v", got)
+	}
+	if got := members((s.RangeByRank(-1, 1))); !slices.Equal(got, []string{"erin"}) {
+		t.Errorf("Want RangeByRank(-1, 1) == [erin], Got %v", got)
+	}
+	if got := members((s.RangeByRank(4, 10))); !slices.Equal(got, []string{"carol"}) {
+		t.Errorf("Want RangeByRank(4, 10) == [carol], Got %v", got)
+	}
+	if got := members((s.RangeByRank(5, 10))); len(got) != 0 {
+		t.Errorf("Want RangeByRank(5, 10) to be empty, Got %v", got)
+	}
+
+	if !s.Remove("bob") || s.Remove("bob") || s.Has("bob") || s.Len() != 4 {
+		t.Errorf("Want bob removed once, Got Len() == %d", s.Len())
+	}
+	if score, ok := s.Score("dave"); !ok || score != 20 {
+		t.Errorf("Want Score(dave) == (20, true), Got (%v, %t)", score, ok)
+	}
+	if _, ok := s.Rank("bob"); ok {
+		t.Errorf("Want Rank() of a removed member == (_, false), Got (_, true)")
+	}
+}
@@ -0,0 +1,59 @@
+package collections
+
+import (
+	"github.org/jccarlson/collections/compare"
+	"github.org/jccarlson/collections/internal/ds"
+)
+
+// sequenced is an element of a StablePriorityQueue with the sequence number
+// of its Push.
+type sequenced[E any] struct {
+	e   E
+	seq uint64
+}
+
+// StablePriorityQueue is a priority queue like BinaryHeap, except that
+// elements which are equal under its ordering are popped in the order they
+// were pushed, as schedulers need for fairness between equal priorities.
+type StablePriorityQueue[E any] struct {
+	heap    ds.BinaryHeap[sequenced[E]]
+	nextSeq uint64
+}
+
+// NewStablePriorityQueue returns a pointer to a new, empty
+// StablePriorityQueue ordered by ord, whose top is an element which no other
+// element is ordered before.
+func NewStablePriorityQueue[E any](ord compare.Ordering[E]) *StablePriorityQueue[E] {
+	q := &StablePriorityQueue[E]{}
+	q.heap.Ordering = func(s1, s2 sequenced[E]) bool {
+		if ord(s1.e, s2.e) {
+			return true
+		}
+		return !ord(s2.e, s1.e) && s1.seq < s2.seq
+	}
+	return q
+}
+
+// Push adds e to q, after any elements in q equal to it.
+func (q *StablePriorityQueue[E]) Push(e E) {
+	q.heap.Push(sequenced[E]{e, q.nextSeq})
+	q.nextSeq++
+}
+
+// Pop removes and returns the top of q, which of equal elements is the
+// earliest pushed. ok is false if q is empty.
+func (q *StablePriorityQueue[E]) Pop() (e E, ok bool) {
+	s, ok := q.heap.Pop()
+	return s.e, ok
+}
+
+// Peek returns the top of q without removing it. ok is false if q is empty.
+func (q *StablePriorityQueue[E]) Peek() (e E, ok bool) {
+	s, ok := q.heap.Peek()
+	return s.e, ok
+}
+
+// Len returns the number of elements in q.
+func (q *StablePriorityQueue[E]) Len() int {
+	return q.heap.Len()
+}
@@ -0,0 +1,110 @@
+package collections
+
+// minShrinkCap is the capacity below which SliceStack and SliceQueue don't
+// shrink their backing slices.
+const minShrinkCap = 16
+
+// shouldShrink returns true if a container holding n elements in a backing
+// slice of capacity c should release memory by halving its capacity.
+func shouldShrink(n, c int) bool {
+	return c > minShrinkCap && n < c/4
+}
+
+// shrink returns a copy of s in a new slice with capacity c/2.
+func shrink[E any](s []E, c int) []E {
+	r := make([]E, len(s), c/2)
+	copy(r, s)
+	return r
+}
+
+// SliceStack is a minimal LIFO stack backed by a slice, which shrinks as
+// elements are popped. The zero value is an empty SliceStack ready to use.
+type SliceStack[E any] struct {
+	elems []E
+}
+
+// Push adds e to the top of s.
+func (s *SliceStack[E]) Push(e E) {
+	s.elems = append(s.elems, e)
+}
+
+// Pop removes and returns the element at the top of s. ok is false if s is
+// empty.
+func (s *SliceStack[E]) Pop() (e E, ok bool) {
+	n := len(s.elems)
+	if n == 0 {
+		return
+	}
+	var zero E
+	e, s.elems[n-1] = s.elems[n-1], zero
+	s.elems = s.elems[:n-1]
+	if shouldShrink(n-1, cap(s.elems)) {
+		s.elems = shrink(s.elems, cap(s.elems))
+	}
+	return e, true
+}
+
+// Peek returns the element at the top of s without removing it. ok is false
+// if s is empty.
+func (s *SliceStack[E]) Peek() (e E, ok bool) {
+	if len(s.elems) == 0 {
+		return
+	}
+	return s.elems[len(s.elems)-1], true
+}
+
+// Len returns the number of elements in s.
+func (s *SliceStack[E]) Len() int {
+	return len(s.elems)
+}
+
+// SliceQueue is a minimal FIFO queue backed by a slice, which shrinks as
+// elements are dequeued. Unlike Deque, its capacity need not be a power of 2.
+// The zero value is an empty SliceQueue ready to use.
+type SliceQueue[E any] struct {
+	elems []E
+	// head is the index in elems of the first element.
+	head int
+}
+
+// Enqueue adds e to the back of q.
+func (q *SliceQueue[E]) Enqueue(e E) {
+	if len(q.elems) == cap(q.elems) && q.head > 0 {
+		// Reuse the space before head instead of growing.
+		n := copy(q.elems, q.elems[q.head:])
+		clear(q.elems[n:])
+		q.elems, q.head = q.elems[:n], 0
+	}
+	q.elems = append(q.elems, e)
+}
+
+// Dequeue removes and returns the element at the front of q. ok is false if
+// q is empty.
+func (q *SliceQueue[E]) Dequeue() (e E, ok bool) {
+	if q.head == len(q.elems) {
+		return
+	}
+	var zero E
+	e, q.elems[q.head] = q.elems[q.head], zero
+	q.head++
+	if n := q.Len(); shouldShrink(n, cap(q.elems)) {
+		q.elems, q.head = shrink(q.elems[q.head:], cap(q.elems)), 0
+	} else if n == 0 {
+		q.elems, q.head = q.elems[:0], 0
+	}
+	return e, true
+}
+
+// Peek returns the element at the front of q without removing it. ok is false
+// if q is empty.
+func (q *SliceQueue[E]) Peek() (e E, ok bool) {
+	if q.head == len(q.elems) {
+		return
+	}
+	return q.elems[q.head], true
+}
+
+// Len returns the number of elements in q.
+func (q *SliceQueue[E]) Len() int {
+	return len(q.elems) - q.head
+}
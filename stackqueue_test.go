@@ -0,0 +1,58 @@
+package collections
+
+import (
+	"testing"
+)
+
+func TestSliceStack(t *testing.T) {
+	var s SliceStack[int]
+	for i := 0; i < 1000; i++ {
+		s.Push(i)
+	}
+	if e, ok := s.Peek(); !ok || e != 999 {
+		t.Errorf("Want Peek() == (999, true), Got (%d, %t)", e, ok)
+	}
+	for want := 999; want >= 0; want-- {
+		if e, ok := s.Pop(); !ok || e != want {
+			t.Fatalf("Want Pop() == (%d, true), Got (%d, %t)", want, e, ok)
+		}
+	}
+	if _, ok := s.Pop(); ok || s.Len() != 0 {
+		t.Errorf("Want empty SliceStack, Got Len() == %d", s.Len())
+	}
+	if c := cap(s.elems); c > minShrinkCap {
+		t.Errorf("Want capacity <= %d after popping all elements, Got %d", minShrinkCap, c)
+	}
+}
+
+func TestSliceQueue(t *testing.T) {
+	var q SliceQueue[int]
+	next := 0
+	// Interleave operations so the queue wraps and compacts repeatedly.
+	for i := 0; i < 1000; i++ {
+		q.Enqueue(i)
+		if i%3 == 0 {
+			if e, ok := q.Dequeue(); !ok || e != next {
+				t.Fatalf("Want Dequeue() == (%d, true), Got (%d, %t)", next, e, ok)
+			}
+			next++
+		}
+	}
+	if q.Len() != 1000-next {
+		t.Errorf("Want Len() == %d, Got %d", 1000-next, q.Len())
+	}
+	if e, ok := q.Peek(); !ok || e != next {
+		t.Errorf("Want Peek() == (%d, true), Got (%d, %t)", next, e, ok)
+	}
+	for ; next < 1000; next++ {
+		if e, ok := q.Dequeue(); !ok || e != next {
+			t.Fatalf("Want Dequeue() == (%d, true), Got (%d, %t)", next, e, ok)
+		}
+	}
+	if _, ok := q.Dequeue(); ok {
+		t.Errorf("Want Dequeue() on empty SliceQueue == (_, false), Got (_, true)")
+	}
+	if c := cap(q.elems); c > minShrinkCap {
+		t.Errorf("Want capacity <= %d after dequeuing all elements, Got %d", minShrinkCap, c)
+	}
+}